@@ -0,0 +1,144 @@
+package dshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// App runs an application wired by a container: it constructs the invoked targets,
+// runs lifecycle hooks, waits for SIGINT/SIGTERM and shuts down gracefully.
+//
+// Example:
+//
+//	app := container.NewApp(
+//	    container.WithModule("db", DatabaseModule),
+//	    container.WithModule("http", HTTPModule),
+//	    container.WithInvoke(func(srv *http.Server) {}),
+//	)
+//	if err := app.Run(); err != nil {
+//	    log.Fatal(err)
+//	}
+type App struct {
+	c           *Container
+	setup       []func(c *Container) // Registrations, applied once options are processed
+	invokes     []any
+	stopTimeout time.Duration
+}
+
+// AppOption configures an App
+type AppOption func(*App)
+
+// UsingContainer makes the app use c instead of the global container
+func UsingContainer(c *Container) AppOption {
+	return func(a *App) {
+		a.c = c
+	}
+}
+
+// WithModule registers a module in the app's container
+func WithModule(name string, m Module) AppOption {
+	return func(a *App) {
+		a.setup = append(a.setup, func(c *Container) {
+			c.RegisterModule(name, m)
+		})
+	}
+}
+
+// WithRegistrations registers token-based registrations in the app's container
+func WithRegistrations(registrations ...AnyRegistration) AppOption {
+	return func(a *App) {
+		a.setup = append(a.setup, func(c *Container) {
+			c.Register(registrations...)
+		})
+	}
+}
+
+// WithInvoke adds functions called on Start with resolved parameters, which eagerly
+// constructs their dependencies. A non-nil error returned last aborts the start.
+func WithInvoke(fns ...any) AppOption {
+	return func(a *App) {
+		a.invokes = append(a.invokes, fns...)
+	}
+}
+
+// WithStopTimeout bounds the graceful shutdown performed by Run (default 15s)
+func WithStopTimeout(d time.Duration) AppOption {
+	return func(a *App) {
+		a.stopTimeout = d
+	}
+}
+
+// NewApp creates an app on the global container, configured by opts
+func NewApp(opts ...AppOption) *App {
+	a := &App{
+		c:           global(),
+		stopTimeout: 15 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+	checkGlobalUse(a.c, "NewApp")
+
+	for _, setup := range a.setup {
+		setup(a.c)
+	}
+
+	return a
+}
+
+// Container returns the app's container
+func (a *App) Container() *Container {
+	return a.c
+}
+
+// Start calls the invoked functions in order, then runs the OnStart hooks.
+func (a *App) Start(ctx context.Context) error {
+	for i, fn := range a.invokes {
+		results, err := a.c.InvokeE(fn)
+		if err != nil {
+			return fmt.Errorf("app: invoke %d: %w", i, err)
+		}
+		if n := len(results); n > 0 {
+			if err, ok := results[n-1].(error); ok && err != nil {
+				return fmt.Errorf("app: invoke %d: %w", i, err)
+			}
+		}
+	}
+
+	return a.c.Start(ctx)
+}
+
+// Stop runs the OnStop hooks, then closes the container.
+func (a *App) Stop(ctx context.Context) error {
+	return errors.Join(a.c.Stop(ctx), a.c.Close(ctx))
+}
+
+// Run starts the app, blocks until SIGINT or SIGTERM, then stops it within the stop timeout.
+func (a *App) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return a.RunContext(ctx)
+}
+
+// RunContext is Run, stopping when ctx is done instead of on a signal.
+func (a *App) RunContext(ctx context.Context) error {
+	if err := a.Start(ctx); err != nil {
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.stopTimeout)
+		defer cancel()
+		return errors.Join(err, a.c.Close(stopCtx))
+	}
+
+	<-ctx.Done()
+
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.stopTimeout)
+	defer cancel()
+
+	return a.Stop(stopCtx)
+}
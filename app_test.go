@@ -0,0 +1,54 @@
+package dshot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestApp_RunContext(t *testing.T) {
+	var events []string
+
+	app := dshot.NewApp(
+		dshot.WithModule("db", func(c *dshot.Container) {
+			dshot.ProvideAutoFactory(func(lc *dshot.LifecycleHooks) *Database {
+				lc.Append(dshot.Hook{
+					OnStart: func(context.Context) error { events = append(events, "start"); return nil },
+					OnStop:  func(context.Context) error { events = append(events, "stop"); return nil },
+				})
+				return &Database{}
+			}, c)
+		}),
+		dshot.WithInvoke(func(db *Database) {
+			events = append(events, "invoke")
+		}),
+		dshot.UsingContainer(dshot.New()),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := app.RunContext(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"invoke", "start", "stop"}
+	if len(events) != 3 || events[0] != want[0] || events[1] != want[1] || events[2] != want[2] {
+		t.Errorf("Expected %v, got %v", want, events)
+	}
+}
+
+func TestApp_InvokeError(t *testing.T) {
+	errInit := errors.New("init failed")
+
+	app := dshot.NewApp(
+		dshot.UsingContainer(dshot.New()),
+		dshot.WithInvoke(func() error { return errInit }),
+	)
+
+	if err := app.Start(context.Background()); !errors.Is(err, errInit) {
+		t.Errorf("Expected invoke error, got %v", err)
+	}
+}
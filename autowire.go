@@ -2,13 +2,14 @@ package dshot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"slices"
-	"time"
 )
 
-// primitiveKinds lists types that cannot be auto-resolved
+// primitiveKinds lists types that cannot be auto-resolved. Channels are resolved only
+// when their type is registered, see isUnresolvable.
 var primitiveKinds = []reflect.Kind{
 	reflect.Bool,
 	reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
@@ -20,10 +21,27 @@ var primitiveKinds = []reflect.Kind{
 	reflect.Chan,
 }
 
+var (
+	errorType   = reflect.TypeFor[error]()
+	cleanupType = reflect.TypeFor[func()]()
+)
+
 func isPrimitive(kind reflect.Kind) bool {
 	return slices.Contains(primitiveKinds, kind)
 }
 
+// isUnresolvable reports whether parameters of kind are rejected without a lookup.
+// Channels are looked up, e.g. a chan Event pipeline created by a factory, and can be
+// injected as <-chan Event or chan<- Event.
+func isUnresolvable(kind reflect.Kind) bool {
+	return kind != reflect.Chan && isPrimitive(kind)
+}
+
+// isCleanupFactory reports whether a factory returns (T, func(), error), wire-style
+func isCleanupFactory(fnType reflect.Type) bool {
+	return fnType.NumOut() == 3 && fnType.Out(1) == cleanupType && fnType.Out(2) == errorType
+}
+
 // BindAutoFactory creates a registration with a factory that auto-wires dependencies.
 // Dependencies are resolved from the specified container (or default if not provided).
 //
@@ -35,19 +53,15 @@ func isPrimitive(kind reflect.Kind) bool {
 //	    }),
 //	)
 func BindAutoFactory[T any](token *Token[T], factory any, containers ...*Container) Registration[T] {
-	c := defaultContainer
-	if len(containers) > 0 && containers[0] != nil {
-		c = containers[0]
-	}
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "BindAutoFactory")
 	return buildAutoFactory(token, factory, Singleton, false, c)
 }
 
 // BindAutoPrototype is like BindAutoFactory but with Prototype lifecycle
 func BindAutoPrototype[T any](token *Token[T], factory any, containers ...*Container) Registration[T] {
-	c := defaultContainer
-	if len(containers) > 0 && containers[0] != nil {
-		c = containers[0]
-	}
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "BindAutoPrototype")
 	return buildAutoFactory(token, factory, Prototype, false, c)
 }
 
@@ -56,6 +70,38 @@ func BindAutoSingleton[T any](token *Token[T], factory any, containers ...*Conta
 	return BindAutoFactory(token, factory, containers...)
 }
 
+// BindAutoScoped is like BindAutoFactory but with Scoped lifecycle:
+// each scope resolving the token gets its own instance.
+func BindAutoScoped[T any](token *Token[T], factory any, containers ...*Container) Registration[T] {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "BindAutoScoped")
+	return buildAutoFactory(token, factory, Scoped, false, c)
+}
+
+// BindAutoFactoryErr is like BindAutoFactory for factories returning (T, error).
+// The factory error is returned by GetE/ResolveE (and panics from Get/Resolve) instead of
+// crashing inside the factory. A failed singleton is retried on the next resolution.
+//
+// Example:
+//
+//	container.Register(
+//	    container.BindAutoFactoryErr(dbToken, func(cfg *Config) (*sql.DB, error) {
+//	        return sql.Open("postgres", cfg.DSN)
+//	    }),
+//	)
+func BindAutoFactoryErr[T any](token *Token[T], factory any, containers ...*Container) Registration[T] {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "BindAutoFactoryErr")
+	return buildAutoFactory(token, factory, Singleton, true, c)
+}
+
+// BindAutoPrototypeErr is like BindAutoFactoryErr but with Prototype lifecycle
+func BindAutoPrototypeErr[T any](token *Token[T], factory any, containers ...*Container) Registration[T] {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "BindAutoPrototypeErr")
+	return buildAutoFactory(token, factory, Prototype, true, c)
+}
+
 // ProvideAutoFactory registers a singleton factory that auto-wires dependencies without requiring a token.
 // Dependencies are resolved from the container at the time of factory invocation.
 //
@@ -65,10 +111,8 @@ func BindAutoSingleton[T any](token *Token[T], factory any, containers ...*Conta
 //	    return NewRepository(db, logger)
 //	})
 func ProvideAutoFactory(factory any, containers ...*Container) {
-	c := defaultContainer
-	if len(containers) > 0 && containers[0] != nil {
-		c = containers[0]
-	}
+	c := containerOrGlobal(containers)
+	checkGlobalRegistration(c, "ProvideAutoFactory")
 	c.provideAutoFactoryWithLifecycle(factory, Singleton, false)
 }
 
@@ -86,7 +130,7 @@ func ProvideAutoFactory(factory any, containers ...*Container) {
 //	    },
 //	)
 func ProvideAutoFactories(items ...any) {
-	c := defaultContainer
+	var c *Container
 
 	if len(items) > 1 && items[len(items)-1] != nil {
 		if cont, ok := items[len(items)-1].(*Container); ok {
@@ -95,6 +139,10 @@ func ProvideAutoFactories(items ...any) {
 		}
 	}
 
+	if c == nil {
+		c = global()
+	}
+	checkGlobalRegistration(c, "ProvideAutoFactories")
 	for _, factory := range items {
 		c.provideAutoFactoryWithLifecycle(factory, Singleton, false)
 	}
@@ -109,10 +157,8 @@ func ProvideAutoFactories(items ...any) {
 //	    return NewRequest(db)
 //	})
 func ProvideAutoPrototype(factory any, containers ...*Container) {
-	c := defaultContainer
-	if len(containers) > 0 && containers[0] != nil {
-		c = containers[0]
-	}
+	c := containerOrGlobal(containers)
+	checkGlobalRegistration(c, "ProvideAutoPrototype")
 	c.provideAutoFactoryWithLifecycle(factory, Prototype, false)
 }
 
@@ -121,6 +167,48 @@ func ProvideAutoSingleton(factory any, containers ...*Container) {
 	ProvideAutoFactory(factory, containers...)
 }
 
+// ProvideAutoScoped registers an auto-wired factory with Scoped lifecycle: one instance is
+// constructed per scope (e.g. per request) and cached in that scope rather than in the
+// registering container. The factory resolves its dependencies from the scope, and the
+// instance is disposed when the scope is closed.
+//
+// Example:
+//
+//	container.ProvideAutoScoped(func(db *sql.DB, req *RequestContext) *UnitOfWork {
+//	    return NewUnitOfWork(db, req)
+//	})
+//
+//	reqScope := container.NewScoped(container.Default())
+//	uow := container.MustResolve[*UnitOfWork](reqScope) // Same instance for the whole request
+func ProvideAutoScoped(factory any, containers ...*Container) {
+	c := containerOrGlobal(containers)
+	checkGlobalRegistration(c, "ProvideAutoScoped")
+	c.provideAutoFactoryWithLifecycle(factory, Scoped, false)
+}
+
+// ProvideAutoFactoryErr is like ProvideAutoFactory for factories returning (T, error).
+// The factory error is surfaced to the caller of ResolveE/GetE rather than swallowed into a panic.
+//
+// Example:
+//
+//	container.ProvideAutoFactoryErr(func(cfg *Config) (*sql.DB, error) {
+//	    return sql.Open("postgres", cfg.DSN)
+//	})
+//
+//	db, err := container.ResolveE[*sql.DB]()
+func ProvideAutoFactoryErr(factory any, containers ...*Container) {
+	c := containerOrGlobal(containers)
+	checkGlobalRegistration(c, "ProvideAutoFactoryErr")
+	c.provideAutoFactoryWithLifecycle(factory, Singleton, true)
+}
+
+// ProvideAutoPrototypeErr is like ProvideAutoPrototype for factories returning (T, error)
+func ProvideAutoPrototypeErr(factory any, containers ...*Container) {
+	c := containerOrGlobal(containers)
+	checkGlobalRegistration(c, "ProvideAutoPrototypeErr")
+	c.provideAutoFactoryWithLifecycle(factory, Prototype, true)
+}
+
 // Wrap takes a factory function that returns a handler function and wraps it with dependency injection.
 // The factory is called once with injected dependencies, and returns the actual handler.
 //
@@ -139,10 +227,8 @@ func ProvideAutoSingleton(factory any, containers ...*Container) {
 //	handler := container.Wrap(makeHandler)
 //	// handler is now: func(ctx context.Context, event MyEvent) error
 func Wrap[T, Arg any](factory func(Arg) T, containers ...*Container) T {
-	c := defaultContainer
-	if len(containers) > 0 && containers[0] != nil {
-		c = containers[0]
-	}
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "Wrap")
 
 	fnValue := reflect.ValueOf(factory)
 	fnType := fnValue.Type()
@@ -166,7 +252,7 @@ func Wrap[T, Arg any](factory func(Arg) T, containers ...*Container) T {
 
 	for i := 0; i < numIn; i++ {
 		paramType := fnType.In(i)
-		arg, err := resolveParameter(c, paramType, numIn)
+		arg, err := resolveParameter(c, paramType, numIn, nil)
 		if err != nil {
 			panic(fmt.Sprintf("Wrap: factory parameter %d (%s): %v", i, paramType, err))
 		}
@@ -175,31 +261,69 @@ func Wrap[T, Arg any](factory func(Arg) T, containers ...*Container) T {
 
 	// Call the factory to get the handler
 	results := fnValue.Call(args)
-	handler := results[0].Interface().(T)
-
-	return handler
+	return mustTyped(typedResult[T](results[0].Interface(), "Wrap: factory"))
 }
 
 // Invoke calls a function, automatically resolving its dependencies from the specified container.
 func Invoke(fn any, containers ...*Container) []any {
-	c := defaultContainer
-	if len(containers) > 0 && containers[0] != nil {
-		c = containers[0]
+	results, err := pickContainer(containers, "Invoke").invoke(fn, nil)
+	if err != nil {
+		panic(err)
 	}
+	return results
+}
 
-	fnValue := reflect.ValueOf(fn)
-	fnType := fnValue.Type()
+// InvokeDisposable is like Invoke but also returns a disposer that closes every
+// io.Closer constructed for the call: prototype dependencies and auto-injected
+// parameter structs. Singletons are owned by the container and are not closed.
+//
+// Example:
+//
+//	results, dispose := container.InvokeDisposable(func(conn *Conn) error {
+//	    return conn.Ping()
+//	})
+//	defer dispose()
+func InvokeDisposable(fn any, containers ...*Container) ([]any, func() error) {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "InvokeDisposable")
+
+	r := newTrackingResolution()
+	defer func() {
+		// Don't leak what was already constructed if resolution or the call panics
+		if rec := recover(); rec != nil {
+			_ = r.dispose()
+			panic(rec)
+		}
+	}()
 
-	if fnType.Kind() != reflect.Func {
-		panic("Invoke: argument must be a function")
+	results, err := c.invoke(fn, r)
+	if err != nil {
+		panic(err)
 	}
 
+	return results, r.dispose
+}
+
+// CallDisposable is a type-safe version of InvokeDisposable that returns T.
+func CallDisposable[T any](fn any, containers ...*Container) (T, func() error) {
+	results, dispose := InvokeDisposable(fn, containers...)
+	return mustTyped(typedResult[T](results[0], "CallDisposable: function")), dispose
+}
+
+// invoke calls fn with parameters resolved from the container
+func (c *Container) invoke(fn any, r *resolution) ([]any, error) {
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func {
+		return nil, errors.New("Invoke: argument must be a function")
+	}
+	fnType := fnValue.Type()
+
 	args := make([]reflect.Value, fnType.NumIn())
 	for i := 0; i < fnType.NumIn(); i++ {
 		paramType := fnType.In(i)
-		arg, err := resolveParameter(c, paramType, fnType.NumIn())
+		arg, err := resolveParameter(c, paramType, fnType.NumIn(), r)
 		if err != nil {
-			panic(fmt.Sprintf("Invoke: parameter %d (%s): %v", i, paramType, err))
+			return nil, withStep(err, paramStep(i, paramType))
 		}
 		args[i] = arg
 	}
@@ -211,7 +335,7 @@ func Invoke(fn any, containers ...*Container) []any {
 		out[i] = result.Interface()
 	}
 
-	return out
+	return out, nil
 }
 
 // Call is a type-safe version of Invoke that returns T.
@@ -223,7 +347,7 @@ func Invoke(fn any, containers ...*Container) []any {
 //	})
 func Call[T any](fn any, containers ...*Container) T {
 	results := Invoke(fn, containers...)
-	return results[0].(T)
+	return mustTyped(typedResult[T](results[0], "Call: function"))
 }
 
 // CallErr is a type-safe version that handles functions returning (T, error).
@@ -234,20 +358,25 @@ func Call[T any](fn any, containers ...*Container) T {
 //	    return NewService(db)
 //	})
 func CallErr[T any](fn any, containers ...*Container) (T, error) {
-	results := Invoke(fn, containers...)
+	return callErr[T](Invoke(fn, containers...))
+}
 
+// callErr converts the results of Invoke or InvokeVia for CallErr and CallErrVia
+func callErr[T any](results []any) (T, error) {
 	var zero T
 	if len(results) != 2 {
 		return zero, fmt.Errorf("CallErr: function must return (T, error)")
 	}
 
-	val := results[0].(T)
+	val, err := typedResult[T](results[0], "CallErr: function")
+	if err != nil {
+		return zero, err
+	}
 	if results[1] == nil {
 		return val, nil
 	}
 
-	err := results[1].(error)
-	return val, err
+	return val, results[1].(error)
 }
 
 // CallContext calls a context-aware function with the provided context.
@@ -258,10 +387,8 @@ func CallErr[T any](fn any, containers ...*Container) (T, error) {
 //	    return NewServiceWithContext(ctx, db)
 //	})
 func CallContext[T any](ctx context.Context, fn any, containers ...*Container) T {
-	c := defaultContainer
-	if len(containers) > 0 && containers[0] != nil {
-		c = containers[0]
-	}
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "CallContext")
 
 	fnValue := reflect.ValueOf(fn)
 	fnType := fnValue.Type()
@@ -284,15 +411,15 @@ func CallContext[T any](ctx context.Context, fn any, containers ...*Container) T
 
 	for i := 1; i < fnType.NumIn(); i++ {
 		paramType := fnType.In(i)
-		arg, err := resolveParameter(c, paramType, fnType.NumIn())
+		arg, err := resolveParameter(c, paramType, fnType.NumIn(), newContextResolution(ctx))
 		if err != nil {
-			panic(fmt.Sprintf("CallContext: parameter %d (%s): %v", i, paramType, err))
+			panic(fmt.Sprintf("CallContext: %v", withStep(err, paramStep(i, paramType))))
 		}
 		args[i] = arg
 	}
 
 	results := fnValue.Call(args)
-	return results[0].Interface().(T)
+	return mustTyped(typedResult[T](results[0].Interface(), "CallContext: function"))
 }
 
 // CallContextErr calls a context-aware function that returns (T, error).
@@ -303,10 +430,8 @@ func CallContext[T any](ctx context.Context, fn any, containers ...*Container) T
 //	    return InitService(ctx, db)
 //	})
 func CallContextErr[T any](ctx context.Context, fn any, containers ...*Container) (T, error) {
-	c := defaultContainer
-	if len(containers) > 0 && containers[0] != nil {
-		c = containers[0]
-	}
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "CallContextErr")
 
 	fnValue := reflect.ValueOf(fn)
 	fnType := fnValue.Type()
@@ -329,9 +454,9 @@ func CallContextErr[T any](ctx context.Context, fn any, containers ...*Container
 
 	for i := 1; i < fnType.NumIn(); i++ {
 		paramType := fnType.In(i)
-		arg, err := resolveParameter(c, paramType, fnType.NumIn())
+		arg, err := resolveParameter(c, paramType, fnType.NumIn(), newContextResolution(ctx))
 		if err != nil {
-			panic(fmt.Sprintf("CallContextErr: parameter %d (%s): %v", i, paramType, err))
+			panic(fmt.Sprintf("CallContextErr: %v", withStep(err, paramStep(i, paramType))))
 		}
 		args[i] = arg
 	}
@@ -343,7 +468,10 @@ func CallContextErr[T any](ctx context.Context, fn any, containers ...*Container
 		return zero, fmt.Errorf("function must return (T, error)")
 	}
 
-	val := results[0].Interface().(T)
+	val, err := typedResult[T](results[0].Interface(), "CallContextErr: function")
+	if err != nil {
+		return zero, err
+	}
 	if results[1].IsNil() {
 		return val, nil
 	}
@@ -353,12 +481,7 @@ func CallContextErr[T any](ctx context.Context, fn any, containers ...*Container
 
 // Inject populates a struct's fields by resolving them from the specified container.
 func Inject(target any, containers ...*Container) {
-	c := defaultContainer
-	if len(containers) > 0 && containers[0] != nil {
-		c = containers[0]
-	}
-
-	c.Inject(target)
+	pickContainer(containers, "Inject").Inject(target)
 }
 
 // Build creates an instance by injecting dependencies into the provided constructor.
@@ -367,18 +490,39 @@ func Build[T any](constructor any, containers ...*Container) T {
 }
 
 // resolveParameter resolves a single parameter by type from the specified container
-func resolveParameter(c *Container, paramType reflect.Type, numIn int) (reflect.Value, error) {
+func resolveParameter(c *Container, paramType reflect.Type, numIn int, r *resolution) (reflect.Value, error) {
+	if paramType == contextType {
+		return reflect.ValueOf(r.context(c)), nil
+	}
+
+	if isGroup(paramType) {
+		return c.resolveGroup(paramType, r)
+	}
+	if isKeyedGroup(paramType) {
+		return c.resolveKeyedGroup(paramType, r)
+	}
+	if isInStruct(paramType) {
+		argValue := reflect.New(paramType)
+		if err := c.inject(argValue.Interface(), r); err != nil {
+			return reflect.Value{}, err
+		}
+		return argValue.Elem(), nil
+	}
+
 	isPtr := paramType.Kind() == reflect.Ptr
 	searchType := paramType
 	if isPtr {
 		searchType = paramType.Elem()
 	}
 
-	if isPrimitive(searchType.Kind()) {
+	if isUnresolvable(searchType.Kind()) {
 		return reflect.Value{}, fmt.Errorf("cannot auto-resolve primitive type %s", paramType)
 	}
 
-	val, ok := c.Resolve(paramType)
+	val, ok, err := c.resolveType(paramType, r)
+	if err != nil {
+		return reflect.Value{}, err
+	}
 	if ok {
 		return reflect.ValueOf(val), nil
 	}
@@ -386,12 +530,15 @@ func resolveParameter(c *Container, paramType reflect.Type, numIn int) (reflect.
 	if numIn == 1 && searchType.Kind() == reflect.Struct {
 		argValue := reflect.New(searchType)
 
-		c.Inject(argValue.Interface())
+		if err := c.inject(argValue.Interface(), r); err != nil {
+			return reflect.Value{}, err
+		}
+		r.track(argValue.Interface())
 
 		return argValue.Elem(), nil
 	}
 
-	return reflect.Value{}, fmt.Errorf("no registration found for type %s", paramType)
+	return reflect.Value{}, c.notFound(paramType)
 }
 
 // buildAutoFactory is the internal implementation for auto-wiring factories
@@ -409,8 +556,7 @@ func buildAutoFactory[T any](
 		panic("factory must be a function")
 	}
 
-	var zero T
-	expectedType := reflect.TypeOf(zero)
+	expectedType := reflect.TypeFor[T]()
 
 	if withError {
 		if fnType.NumOut() != 2 {
@@ -428,8 +574,8 @@ func buildAutoFactory[T any](
 			)
 		}
 	} else {
-		if fnType.NumOut() != 1 {
-			panic("factory must return exactly one value")
+		if fnType.NumOut() != 1 && !isCleanupFactory(fnType) {
+			panic("factory must return exactly one value or (T, func(), error)")
 		}
 		if fnType.Out(0) != expectedType {
 			panic(
@@ -441,14 +587,16 @@ func buildAutoFactory[T any](
 		}
 	}
 
-	wrappedFactory := func() T {
-		return resolveAndCall[T](container, fnValue, fnType, withError, token.key)
+	wrappedFactory := func(r *resolution) (T, error) {
+		return resolveAndCall[T](r.in(container), fnValue, fnType, withError, token.key, r)
 	}
 
 	return Registration[T]{
-		token:     token,
-		factory:   wrappedFactory,
-		lifecycle: lifecycle,
+		token:      token,
+		factory:    wrappedFactory,
+		lifecycle:  lifecycle,
+		wiring:     newWiring(container, fnType),
+		ownCleanup: isCleanupFactory(fnType),
 	}
 }
 
@@ -459,35 +607,43 @@ func resolveAndCall[T any](
 	fnType reflect.Type,
 	withError bool,
 	tokenKey string,
-) T {
+	r *resolution,
+) (T, error) {
+	var zero T
 	numIn := fnType.NumIn()
 	args := make([]reflect.Value, numIn)
 
 	for i := 0; i < fnType.NumIn(); i++ {
 		paramType := fnType.In(i)
-		arg, err := resolveParameter(c, paramType, numIn)
+		arg, err := resolveParameter(c, paramType, numIn, r)
 		if err != nil {
-			panic(
-				fmt.Sprintf(
-					"auto-wire factory[%v]: parameter %d (%s): %v",
-					tokenKey, i, paramType, err,
-				),
-			)
+			return zero, withStep(err, paramStep(i, paramType))
 		}
 		args[i] = arg
 	}
 
 	results := fnValue.Call(args)
 
+	if isCleanupFactory(fnType) {
+		if !results[2].IsNil() {
+			err := results[2].Interface().(error)
+			return zero, fmt.Errorf("factory[%v] returned error: %w", tokenKey, err)
+		}
+		if !results[1].IsNil() {
+			r.trackCleanup(c, results[1].Interface().(func()))
+		}
+		return results[0].Interface().(T), nil
+	}
+
 	if withError {
 		if !results[1].IsNil() {
 			err := results[1].Interface().(error)
-			panic(fmt.Sprintf("factory[%v] returned error: %v", tokenKey, err))
+			return zero, fmt.Errorf("factory[%v] returned error: %w", tokenKey, err)
 		}
-		return results[0].Interface().(T)
+		return results[0].Interface().(T), nil
 	}
 
-	return results[0].Interface().(T)
+	return results[0].Interface().(T), nil
 }
 
 // provideAutoFactoryWithLifecycle is the internal implementation for auto-wiring factories without tokens
@@ -499,6 +655,11 @@ func (c *Container) provideAutoFactoryWithLifecycle(factory any, lifecycle Lifec
 		panic("factory must be a function")
 	}
 
+	if !withError && isMultiOutput(fnType) {
+		c.provideMultiOutput(fnValue, fnType, lifecycle)
+		return
+	}
+
 	var returnType reflect.Type
 	if withError {
 		if fnType.NumOut() != 2 {
@@ -509,29 +670,33 @@ func (c *Container) provideAutoFactoryWithLifecycle(factory any, lifecycle Lifec
 		}
 		returnType = fnType.Out(0)
 	} else {
-		if fnType.NumOut() != 1 {
-			panic("factory must return exactly one value")
+		if fnType.NumOut() != 1 && !isCleanupFactory(fnType) {
+			panic("factory must return exactly one value or (T, func(), error)")
 		}
 		returnType = fnType.Out(0)
 	}
 
 	token := &tokenKey{
-		key: fmt.Sprintf("__provided__%s_%d", returnType.String(), time.Now().UnixNano()),
+		key: providedKey(returnType),
 	}
 
-	wrappedFactory := func() any {
-		return resolveAndCall[any](c, fnValue, fnType, withError, token.key)
+	wrappedFactory := func(r *resolution) (any, error) {
+		return resolveAndCall[any](r.in(c), fnValue, fnType, withError, token.key, r)
 	}
 
 	e := &entry{
-		factory:   wrappedFactory,
-		lifecycle: lifecycle,
-		depType:   returnType,
+		factory:    wrappedFactory,
+		lifecycle:  lifecycle,
+		depType:    returnType,
+		wiring:     newWiring(c, fnType),
+		ownCleanup: isCleanupFactory(fnType),
 	}
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.registry[token] = e
-	c.typeRegistry[returnType] = append(c.typeRegistry[returnType], e)
+	c.addEntry(token, e)
+	if isOutStruct(returnType) {
+		c.addOutEntries(e)
+	}
 }
@@ -0,0 +1,72 @@
+package dshot
+
+import (
+	"reflect"
+	"slices"
+)
+
+// ProvideBulk registers many values at once (type-based registration).
+// The container is locked once and type indexing is deferred until the first resolution,
+// which keeps startup fast for containers with thousands of registrations.
+//
+// Example:
+//
+//	c.ProvideBulk(cfg, logger, metrics, tracer)
+func (c *Container) ProvideBulk(values ...any) {
+	entries := make([]*entry, len(values))
+	tokens := make([]*tokenKey, len(values))
+
+	for i, value := range values {
+		typ := reflect.TypeOf(value)
+		if typ == nil {
+			panic("ProvideBulk: cannot register nil value")
+		}
+
+		tokens[i] = &tokenKey{key: providedKey(typ)}
+		entries[i] = &entry{
+			value:     value,
+			lifecycle: Singleton,
+			depType:   typ,
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.beginBulk(len(values))
+	defer c.endBulk()
+
+	for i, e := range entries {
+		c.addEntry(tokens[i], e)
+	}
+}
+
+// RegisterBulk is like Register but defers type indexing until the first resolution.
+// Use it for large batches of token-based registrations at startup.
+func (c *Container) RegisterBulk(registrations ...registration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.beginBulk(len(registrations))
+	defer c.endBulk()
+
+	for _, reg := range registrations {
+		reg.registerTo(c)
+	}
+}
+
+// beginBulk prepares the container for n registrations. Callers must hold c.mu.
+func (c *Container) beginBulk(n int) {
+	c.checkWritable("register")
+
+	if len(c.registry) == 0 {
+		c.registry = make(map[any]*entry, n)
+	}
+	c.entries = slices.Grow(c.entries, n)
+	c.deferIndex = true
+}
+
+// endBulk ends a bulk registration. Callers must hold c.mu.
+func (c *Container) endBulk() {
+	c.deferIndex = false
+}
@@ -0,0 +1,46 @@
+package dshot_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestProvideBulk(t *testing.T) {
+	c := dshot.New()
+	c.ProvideBulk(&Service{Name: "bulk"}, &Database{ConnectionString: "localhost"})
+
+	if svc := dshot.MustResolve[*Service](c); svc.Name != "bulk" {
+		t.Errorf("Expected 'bulk', got '%s'", svc.Name)
+	}
+
+	// Regular registrations after a bulk one are indexed in order
+	c.Provide(&Repository{})
+	if len(dshot.ResolveAll[*Database](c)) != 1 {
+		t.Error("Expected one database")
+	}
+	if _, ok := dshot.Resolve[*Repository](c); !ok {
+		t.Error("Repository should resolve")
+	}
+}
+
+func TestRegisterBulk(t *testing.T) {
+	c := dshot.New()
+
+	tokens := make([]*dshot.Token[*Service], 100)
+	regs := make([]dshot.AnyRegistration, 0, 100)
+	for i := range tokens {
+		tokens[i] = dshot.NewToken[*Service](fmt.Sprintf("svc-%d", i))
+		regs = append(regs, dshot.Bind(tokens[i], &Service{Name: tokens[i].String()}))
+	}
+	c.RegisterBulk(regs...)
+
+	if got := len(dshot.ResolveAll[*Service](c)); got != 100 {
+		t.Errorf("Expected 100 services, got %d", got)
+	}
+
+	if svc := dshot.Get(tokens[42], c); svc.Name != "svc-42" {
+		t.Errorf("Expected 'svc-42', got '%s'", svc.Name)
+	}
+}
@@ -0,0 +1,33 @@
+package dshot
+
+import (
+	"math/rand/v2"
+)
+
+// Canary routes percent of the scopes resolving the token to the canary registration and
+// the others to stable, for gradual rollouts at the wiring level. The variant is drawn
+// once per scope, so every resolution within a request sees the same implementation;
+// resolutions from the root container draw once for the root. Each variant keeps its own
+// lifecycle. Both registrations must use the same token. It is Variants with a random
+// selector; see ServedVariant and VariantUsage for which variant served what.
+//
+// Example:
+//
+//	c.Register(container.Canary(5,
+//	    container.BindAutoFactory(pricingToken, NewPricing),
+//	    container.BindAutoFactory(pricingToken, NewPricingV2),
+//	))
+func Canary[T any](percent float64, stable, canary Registration[T]) Registration[T] {
+	if percent < 0 || percent > 100 {
+		panic("Canary: percent must be between 0 and 100")
+	}
+
+	selector := VariantSelectorFunc(func(*Container, []string) string {
+		if rand.Float64()*100 < percent {
+			return "canary"
+		}
+		return "stable"
+	})
+
+	return Variants(selector, Variant[T]{"stable", stable}, Variant[T]{"canary", canary})
+}
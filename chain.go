@@ -0,0 +1,48 @@
+package dshot
+
+import (
+	"context"
+)
+
+type chainCtxKey struct{}
+
+// context returns the context passed to factories taking a context.Context parameter:
+// the context of the *Ctx or CallContext call that started the resolution (or the base
+// context of c, see WithBaseContext), carrying the resolution chain for CurrentChain. Every factory
+// run for that call receives it, however deeply nested.
+func (r *resolution) context(c *Container) context.Context {
+	ctx := context.Background()
+	if c.baseCtx != nil {
+		ctx = c.baseCtx
+	}
+	if r != nil && r.ctx != nil {
+		ctx = r.ctx
+	}
+
+	var chain []string
+	if r != nil {
+		chain = make([]string, len(r.chain))
+		for i, e := range r.chain {
+			chain[i] = e.label()
+		}
+	}
+
+	return context.WithValue(ctx, chainCtxKey{}, chain)
+}
+
+// CurrentChain returns the registrations being resolved when called from a factory that
+// takes a context.Context parameter, outermost first; the last one is the factory's own.
+// Types are shown for type-based registrations and token names otherwise. Factories
+// resolved through GetCtx, ResolveCtx, CallContext and the other *Ctx helpers receive a
+// context derived from the caller's.
+//
+// Example:
+//
+//	container.ProvideAutoFactory(func(ctx context.Context, cfg *Config) *Client {
+//	    logger.Info("building client", "chain", container.CurrentChain(ctx))
+//	    return NewClient(cfg)
+//	})
+func CurrentChain(ctx context.Context) []string {
+	chain, _ := ctx.Value(chainCtxKey{}).([]string)
+	return chain
+}
@@ -0,0 +1,95 @@
+package dshot_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type traceKey struct{}
+
+func TestCurrentChain(t *testing.T) {
+	c := dshot.New()
+	var chain []string
+	var trace any
+
+	dshot.ProvideAutoFactory(func(ctx context.Context) *Database {
+		chain = dshot.CurrentChain(ctx)
+		trace = ctx.Value(traceKey{})
+		return &Database{}
+	}, c)
+	dshot.ProvideAutoFactory(func(db *Database) *Repository {
+		return &Repository{DB: db}
+	}, c)
+
+	ctx := dshot.WithContainer(context.WithValue(context.Background(), traceKey{}, "trace-1"), c)
+	dshot.MustResolveCtx[*Repository](ctx)
+
+	want := "*dshot_test.Repository -> *dshot_test.Database"
+	if got := strings.Join(chain, " -> "); got != want {
+		t.Errorf("Expected chain %q, got %q", want, got)
+	}
+	if trace != "trace-1" {
+		t.Errorf("Expected factory context derived from the caller's, got %v", trace)
+	}
+}
+
+func TestCallContext_PropagatesToNestedFactories(t *testing.T) {
+	c := dshot.New()
+	var trace any
+
+	dshot.ProvideAutoPrototype(func(ctx context.Context) *Database {
+		trace = ctx.Value(traceKey{})
+		return &Database{}
+	}, c)
+	dshot.ProvideAutoPrototype(func(db *Database) *Repository {
+		return &Repository{DB: db}
+	}, c)
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-2")
+	dshot.CallContext[*Repository](ctx, func(ctx context.Context, repo *Repository) *Repository {
+		return repo
+	}, c)
+
+	if trace != "trace-2" {
+		t.Errorf("Expected nested factory to receive the call context, got %v", trace)
+	}
+}
+
+func TestResolveAllCtx_PropagatesContext(t *testing.T) {
+	c := dshot.New()
+	var traces []any
+
+	dshot.ProvideAutoPrototype(func(ctx context.Context) *Database {
+		traces = append(traces, ctx.Value(traceKey{}))
+		return &Database{}
+	}, c)
+
+	ctx := context.WithValue(dshot.WithContainer(context.Background(), c), traceKey{}, "trace-3")
+	dshot.ResolveAllCtx[*Database](ctx)
+
+	if len(traces) != 1 || traces[0] != "trace-3" {
+		t.Errorf("Expected the factory to receive the context, got %v", traces)
+	}
+}
+
+func TestWithBaseContext(t *testing.T) {
+	base := context.WithValue(context.Background(), traceKey{}, "base")
+	c := dshot.New(dshot.WithBaseContext(base))
+	var traces []any
+
+	dshot.ProvideAutoPrototype(func(ctx context.Context) *Database {
+		traces = append(traces, ctx.Value(traceKey{}))
+		return &Database{}
+	}, c)
+
+	dshot.MustResolve[*Database](dshot.NewScoped(c))
+	ctx := dshot.WithContainer(context.WithValue(context.Background(), traceKey{}, "call"), c)
+	dshot.MustResolveCtx[*Database](ctx)
+
+	if len(traces) != 2 || traces[0] != "base" || traces[1] != "call" {
+		t.Errorf("Expected base context without a call context, got %v", traces)
+	}
+}
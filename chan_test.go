@@ -0,0 +1,45 @@
+package dshot_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type pipelineEvent struct {
+	ID int
+}
+
+func TestInject_RegisteredChannels(t *testing.T) {
+	c := dshot.New()
+	dshot.ProvideAutoFactory(func() chan pipelineEvent {
+		return make(chan pipelineEvent, 1)
+	}, c)
+
+	dshot.Invoke(func(out chan<- pipelineEvent) {
+		out <- pipelineEvent{ID: 7}
+	}, c)
+	got := dshot.Call[pipelineEvent](func(in <-chan pipelineEvent) pipelineEvent {
+		return <-in
+	}, c)
+	if got.ID != 7 {
+		t.Errorf("Expected the event sent through the shared channel, got %+v", got)
+	}
+	dshot.ProvideAutoFactory(func(in <-chan pipelineEvent) *Service {
+		return &Service{Name: "consumer"}
+	}, c)
+	if err := c.Validate(); err != nil {
+		t.Errorf("Expected registered channels to validate, got %v", err)
+	}
+
+	_, err := dshot.InvokeE(func(chan string) {}, c)
+	if !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unregistered channel, got %v", err)
+	}
+	_, err = dshot.InvokeE(func(int) {}, c)
+	if err == nil || !strings.Contains(err.Error(), "primitive") {
+		t.Errorf("Expected other primitives still rejected, got %v", err)
+	}
+}
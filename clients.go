@@ -0,0 +1,102 @@
+package dshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// ClientDescriptor describes a remote service client for RegisterClients
+type ClientDescriptor[Opt any] struct {
+	Name    string // Token name, for picking between clients of the same type; optional
+	Target  string // Address passed to the dial function
+	Options []Opt  // Dial options, e.g. grpc.DialOption values
+	New     any    // Client constructor taking the connection: func(Conn) Client or func(Conn) (Client, error)
+}
+
+// RegisterClients registers a singleton client per descriptor, replacing near-identical
+// client factories with data. On first resolution the connection is dialed with
+// dial(Target, Options...) and passed to New; the client is resolvable by its type, and
+// by Name through `dshot:"name=..."` fields. Connections implementing io.Closer are
+// closed with the container, after the clients built on them.
+//
+// Example:
+//
+//	container.RegisterClients(c, grpc.NewClient,
+//	    container.ClientDescriptor[grpc.DialOption]{
+//	        Target:  "users:443",
+//	        Options: []grpc.DialOption{grpc.WithTransportCredentials(creds)},
+//	        New:     userpb.NewUserServiceClient,
+//	    },
+//	    container.ClientDescriptor[grpc.DialOption]{
+//	        Target:  "orders:443",
+//	        Options: []grpc.DialOption{grpc.WithTransportCredentials(creds)},
+//	        New:     orderpb.NewOrderServiceClient,
+//	    },
+//	)
+func RegisterClients[Conn, Opt any](
+	c *Container,
+	dial func(target string, opts ...Opt) (Conn, error),
+	clients ...ClientDescriptor[Opt],
+) {
+	if c == nil {
+		c = global()
+	}
+	checkGlobalRegistration(c, "RegisterClients")
+
+	connType := reflect.TypeFor[Conn]()
+
+	entries := make([]*entry, len(clients))
+	tokens := make([]any, len(clients))
+
+	for i, desc := range clients {
+		if desc.New == nil {
+			panic(fmt.Sprintf("RegisterClients: New for %q cannot be nil", desc.Target))
+		}
+		fnValue := reflect.ValueOf(desc.New)
+		fnType := fnValue.Type()
+		if fnType.Kind() != reflect.Func || fnType.NumIn() != 1 || !connType.AssignableTo(fnType.In(0)) {
+			panic(fmt.Sprintf("RegisterClients: New for %q must be a function taking %s", desc.Target, connType))
+		}
+		withError := fnType.NumOut() == 2 && fnType.Out(1) == errorType
+		if fnType.NumOut() != 1 && !withError {
+			panic(fmt.Sprintf("RegisterClients: New for %q must return Client or (Client, error)", desc.Target))
+		}
+
+		clientType := fnType.Out(0)
+		tokens[i] = &tokenKey{key: providedKey(clientType)}
+		if desc.Name != "" {
+			tokens[i] = &tokenKey{key: desc.Name}
+		}
+
+		entries[i] = &entry{
+			factory: func(*resolution) (any, error) {
+				conn, err := dial(desc.Target, desc.Options...)
+				if err != nil {
+					return nil, fmt.Errorf("dial %s: %w", desc.Target, err)
+				}
+				if closer, ok := any(conn).(io.Closer); ok {
+					c.addDisposer(func(context.Context) error {
+						return closer.Close()
+					})
+				}
+
+				results := fnValue.Call([]reflect.Value{reflect.ValueOf(conn)})
+				if withError && !results[1].IsNil() {
+					return nil, fmt.Errorf("client %s: %w", desc.Target, results[1].Interface().(error))
+				}
+				return results[0].Interface(), nil
+			},
+			lifecycle: Singleton,
+			depType:   clientType,
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, e := range entries {
+		c.addEntry(tokens[i], e)
+	}
+}
@@ -0,0 +1,106 @@
+package dshot_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type fakeConn struct {
+	target string
+	opts   []string
+	closed bool
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+type usersClient struct{ conn *fakeConn }
+
+type ordersClient struct{ conn *fakeConn }
+
+func TestRegisterClients(t *testing.T) {
+	c := dshot.New()
+	dials := 0
+	dial := func(target string, opts ...string) (*fakeConn, error) {
+		dials++
+		if target == "" {
+			return nil, errors.New("empty target")
+		}
+		return &fakeConn{target: target, opts: opts}, nil
+	}
+
+	dshot.RegisterClients(c, dial,
+		dshot.ClientDescriptor[string]{
+			Target:  "users:443",
+			Options: []string{"tls"},
+			New:     func(conn *fakeConn) *usersClient { return &usersClient{conn: conn} },
+		},
+		dshot.ClientDescriptor[string]{
+			Target: "orders:443",
+			New:    func(conn *fakeConn) (*ordersClient, error) { return &ordersClient{conn: conn}, nil },
+		},
+	)
+
+	if dials != 0 {
+		t.Errorf("Expected connections to be dialed lazily, got %d dials", dials)
+	}
+
+	users := dshot.MustResolve[*usersClient](c)
+	orders := dshot.MustResolve[*ordersClient](c)
+	if users.conn.target != "users:443" || len(users.conn.opts) != 1 || orders.conn.target != "orders:443" {
+		t.Errorf("Expected clients on their own connections, got %+v and %+v", users.conn, orders.conn)
+	}
+
+	dshot.MustResolve[*usersClient](c)
+	if dials != 2 {
+		t.Errorf("Expected one dial per client, got %d", dials)
+	}
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+	if !users.conn.closed || !orders.conn.closed {
+		t.Error("Expected connections closed with the container")
+	}
+}
+
+func TestRegisterClients_Named(t *testing.T) {
+	c := dshot.New()
+	dial := func(target string, _ ...string) (*fakeConn, error) {
+		return &fakeConn{target: target}, nil
+	}
+	newClient := func(conn *fakeConn) *usersClient { return &usersClient{conn: conn} }
+
+	dshot.RegisterClients(c, dial,
+		dshot.ClientDescriptor[string]{Name: "users-eu", Target: "users.eu:443", New: newClient},
+		dshot.ClientDescriptor[string]{Name: "users-us", Target: "users.us:443", New: newClient},
+	)
+
+	var deps struct {
+		US *usersClient `dshot:"name=users-us"`
+	}
+	c.Inject(&deps)
+	if deps.US.conn.target != "users.us:443" {
+		t.Errorf("Expected US client, got %q", deps.US.conn.target)
+	}
+}
+
+func TestRegisterClients_NilNewPanics(t *testing.T) {
+	dial := func(target string, _ ...string) (*fakeConn, error) {
+		return &fakeConn{target: target}, nil
+	}
+
+	defer func() {
+		if msg := fmt.Sprint(recover()); !strings.Contains(msg, `New for "users:443" cannot be nil`) {
+			t.Errorf("Expected a clear panic for a nil New, got %q", msg)
+		}
+	}()
+	dshot.RegisterClients(dshot.New(), dial, dshot.ClientDescriptor[string]{Target: "users:443"})
+}
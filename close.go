@@ -0,0 +1,200 @@
+package dshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Disposable is implemented by values that need a context-aware shutdown.
+// Singletons built by a container factory are disposed when the container is closed.
+type Disposable interface {
+	Dispose(ctx context.Context) error
+}
+
+// addCleanup records a cleanup function to run when the container is closed
+func (c *Container) addCleanup(fn func()) {
+	c.addDisposer(func(context.Context) error {
+		fn()
+		return nil
+	})
+}
+
+// addDisposer records a disposal function to run when the container is closed
+func (c *Container) addDisposer(fn func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cleanups = append(c.cleanups, fn)
+}
+
+// trackDisposal schedules disposal of a singleton built by the factory of e.
+// Values are disposed with the registration's OnClose function if set, else through
+// Disposable or io.Closer. Factories returning their own cleanup function are skipped.
+func (c *Container) trackDisposal(e *entry, val any) {
+	switch {
+	case e.closer != nil:
+		c.addDisposer(func(ctx context.Context) error {
+			return e.closer(ctx, val)
+		})
+	case e.ownCleanup:
+	default:
+		switch v := val.(type) {
+		case Disposable:
+			c.addDisposer(v.Dispose)
+		case io.Closer:
+			c.addDisposer(func(context.Context) error {
+				return v.Close()
+			})
+		}
+	}
+}
+
+// warnUnownedCleanup reports, once per registration, that the cleanup returned by the
+// prototype factory of e was dropped because it was built outside a scope
+func (c *Container) warnUnownedCleanup(e *entry) {
+	if e.unowned.Swap(true) || !c.warnEnabled() {
+		return
+	}
+	c.warn(Warning{
+		Kind: WarnUnownedCleanup,
+		Message: fmt.Sprintf(
+			"Prototype %s returned a cleanup outside a scope, it will not run; resolve it through NewScoped or InvokeDisposable",
+			e.label(),
+		),
+		Attrs: []slog.Attr{slog.String("token", e.label())},
+	})
+}
+
+// Close disposes what this container's factories created, in reverse creation order,
+// so dependents are torn down before their dependencies. It runs the cleanup functions
+// returned by factories and disposes singletons implementing Disposable or io.Closer
+// (or registered with OnClose). Errors are joined; a failing disposal does not stop the others.
+// It does not close the parent container.
+//
+// Factories can also return their own cleanup as (T, func(), error):
+//
+//	container.ProvideAutoFactory(func(cfg *Config) (*sql.DB, func(), error) {
+//	    db, err := sql.Open("postgres", cfg.DSN)
+//	    if err != nil {
+//	        return nil, nil, err
+//	    }
+//	    return db, func() { db.Close() }, nil
+//	})
+//	defer c.Close(ctx)
+func (c *Container) Close(ctx context.Context) error {
+	if c.parent != nil && !c.view {
+		c.closed.Store(true)
+	}
+	c.releaseOpen()
+
+	return c.runCleanups(ctx)
+}
+
+// runCleanups runs the pending disposals in reverse creation order
+func (c *Container) runCleanups(ctx context.Context) error {
+	c.mu.Lock()
+	cleanups := c.cleanups
+	c.cleanups = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			// Keep the remaining cleanups so a later Close can finish the job
+			c.mu.Lock()
+			c.cleanups = append(cleanups[:i+1], c.cleanups...)
+			c.mu.Unlock()
+			return errors.Join(append(errs, err)...)
+		}
+		if err := cleanups[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ClearEvent describes a cleared container, see OnClear
+type ClearEvent struct {
+	Registrations int   // Number of registrations removed
+	Disposed      bool  // Built instances were disposed, see ClearAndDispose
+	Err           error // Disposal errors, joined
+}
+
+// ClearAndDispose disposes what the container's factories created, as Close does, then
+// removes all its registrations, so tests and hot-reload flows can rebuild the graph
+// without leaking connections. Registrations are removed even if a disposal fails; the
+// errors are returned and reported to OnClear callbacks.
+//
+// Example:
+//
+//	if err := c.ClearAndDispose(ctx); err != nil {
+//	    log.Printf("reload: %v", err)
+//	}
+//	c.RegisterModule("app", AppModule(newConfig))
+func (c *Container) ClearAndDispose(ctx context.Context) error {
+	c.checkWritable("ClearAndDispose")
+
+	err := c.runCleanups(ctx)
+	c.notifyClear(ClearEvent{Registrations: c.clear("ClearAndDispose"), Disposed: true, Err: err})
+
+	return err
+}
+
+// OnClear registers a callback notified after each Clear or ClearAndDispose, e.g. to
+// reset caches derived from the container. Callbacks are kept by the clear.
+//
+// Example:
+//
+//	c.OnClear(func(ev container.ClearEvent) {
+//	    slog.Info("container cleared", "registrations", ev.Registrations, "error", ev.Err)
+//	})
+func (c *Container) OnClear(fn func(ClearEvent)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clearHooks = append(c.clearHooks, fn)
+}
+
+// notifyClear calls the OnClear callbacks
+func (c *Container) notifyClear(ev ClearEvent) {
+	c.mu.RLock()
+	hooks := c.clearHooks
+	c.mu.RUnlock()
+
+	for _, fn := range hooks {
+		fn(ev)
+	}
+}
+
+// OnScopeClose registers a callback that runs when the container is disposed or closed,
+// after the instances created since the registration and before those created earlier.
+//
+// Example:
+//
+//	reqScope := container.NewScoped(app, "request")
+//	reqScope.OnScopeClose(func(ctx context.Context) error {
+//	    return auditLog.Flush(ctx)
+//	})
+func (c *Container) OnScopeClose(fn func(ctx context.Context) error) {
+	c.addDisposer(fn)
+}
+
+// Dispose releases what a scope created: Scoped instances, prototypes resolved through
+// the scope, singletons registered in it and OnScopeClose callbacks, in reverse creation
+// order. Singletons owned by parent containers are left untouched. It is Close under the
+// name of the Disposable interface, so a scope can itself be disposed as a dependency.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    reqScope := container.NewScoped(app, "request")
+//	    defer reqScope.Dispose(r.Context())
+//	    // ...
+//	}
+func (c *Container) Dispose(ctx context.Context) error {
+	return c.Close(ctx)
+}
@@ -0,0 +1,209 @@
+package dshot_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestClose_RunsFactoryCleanupsInReverseOrder(t *testing.T) {
+	c := dshot.New()
+	var order []string
+
+	c.Provide(&Database{ConnectionString: "localhost"})
+	dshot.ProvideAutoFactory(func(db *Database) (*Repository, func(), error) {
+		return &Repository{DB: db}, func() { order = append(order, "repository") }, nil
+	}, c)
+	dshot.ProvideAutoFactory(func(repo *Repository) (*ComplexService, func(), error) {
+		return &ComplexService{Repo: repo}, func() { order = append(order, "service") }, nil
+	}, c)
+
+	if _, ok := c.Resolve(reflect.TypeOf((*ComplexService)(nil))); !ok {
+		t.Fatal("Failed to resolve service")
+	}
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "service" || order[1] != "repository" {
+		t.Errorf("Expected cleanups in reverse order, got %v", order)
+	}
+}
+
+func TestClose_CleanupsRunOnce(t *testing.T) {
+	c := dshot.New()
+	calls := 0
+
+	dshot.ProvideAutoFactory(func() (*Service, func(), error) {
+		return &Service{}, func() { calls++ }, nil
+	}, c)
+	c.Resolve(reflect.TypeOf((*Service)(nil)))
+
+	c.Close(context.Background())
+	c.Close(context.Background())
+
+	if calls != 1 {
+		t.Errorf("Expected cleanup to run once, got %d", calls)
+	}
+}
+
+type disposablePool struct {
+	order *[]string
+}
+
+func (p *disposablePool) Dispose(ctx context.Context) error {
+	*p.order = append(*p.order, "pool")
+	return nil
+}
+
+func TestClose_DisposesSingletonsInReverseOrder(t *testing.T) {
+	c := dshot.New()
+	var order []string
+
+	c.ProvideFactory(func() *disposablePool {
+		return &disposablePool{order: &order}
+	})
+	dshot.ProvideAutoFactory(func(p *disposablePool) *closableConn {
+		return &closableConn{}
+	}, c)
+	token := dshot.NewToken[*Service]("svc")
+	c.Register(
+		dshot.BindAutoFactory(token, func(conn *closableConn) *Service {
+			return &Service{Name: "svc"}
+		}, c).OnClose(func(ctx context.Context, s *Service) error {
+			order = append(order, "service:"+s.Name)
+			return nil
+		}),
+	)
+
+	dshot.Get(token, c)
+	conn := dshot.MustResolve[*closableConn](c)
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !conn.closed {
+		t.Error("Expected io.Closer singleton to be closed")
+	}
+	if len(order) != 2 || order[0] != "service:svc" || order[1] != "pool" {
+		t.Errorf("Expected dependents disposed first, got %v", order)
+	}
+}
+
+func TestDispose_ClosesScopeCreatedInstances(t *testing.T) {
+	app := dshot.New()
+	var order []string
+
+	dshot.ProvideAutoFactory(func() *disposablePool {
+		return &disposablePool{order: &order}
+	}, app)
+	dshot.ProvideAutoPrototype(func(pool *disposablePool) (*Repository, func(), error) {
+		return &Repository{}, func() { order = append(order, "repository") }, nil
+	}, app)
+
+	scope := dshot.NewScoped(app, "request")
+	scope.OnScopeClose(func(ctx context.Context) error {
+		order = append(order, "callback")
+		return nil
+	})
+	dshot.MustResolve[*Repository](scope)
+
+	if err := scope.Dispose(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(order, []string{"repository", "callback"}) {
+		t.Errorf("Expected scope instances disposed without the parent singleton, got %v", order)
+	}
+
+	app.Close(context.Background())
+	if !reflect.DeepEqual(order, []string{"repository", "callback", "pool"}) {
+		t.Errorf("Expected parent singleton disposed by its container, got %v", order)
+	}
+}
+
+func TestClearAndDispose(t *testing.T) {
+	c := dshot.New()
+
+	closed := 0
+	dshot.ProvideAutoFactory(func() (*Database, func(), error) {
+		return &Database{}, func() { closed++ }, nil
+	}, c)
+	dshot.MustResolve[*Database](c)
+
+	var events []dshot.ClearEvent
+	c.OnClear(func(ev dshot.ClearEvent) {
+		events = append(events, ev)
+	})
+
+	if err := c.ClearAndDispose(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if closed != 1 {
+		t.Errorf("Expected the cleanup run by the clear, got %d runs", closed)
+	}
+	if _, ok := dshot.Resolve[*Database](c); ok {
+		t.Error("Expected the registration removed")
+	}
+
+	c.Provide(&Service{})
+	c.Clear()
+	if err := c.Close(context.Background()); err != nil || closed != 1 {
+		t.Errorf("Expected the cleanup not run twice, got %d runs (%v)", closed, err)
+	}
+
+	if len(events) != 2 || !events[0].Disposed || events[0].Registrations != 1 || events[1].Disposed {
+		t.Errorf("Expected a disposing then a plain clear event, got %+v", events)
+	}
+}
+
+func TestDispose_LongLivedChildrenKeepNoPrototypes(t *testing.T) {
+	app := dshot.New()
+	child := dshot.New(dshot.WithParent(app))
+	dshot.ProvideAutoPrototype(func() *closableConn { return &closableConn{} }, app)
+
+	conn := dshot.MustResolve[*closableConn](child)
+	if err := child.Close(context.Background()); err != nil || conn.closed {
+		t.Errorf("Expected prototypes of a long-lived child left to the caller, got closed=%v (%v)", conn.closed, err)
+	}
+
+	scope := dshot.NewScoped(child)
+	conn = dshot.MustResolve[*closableConn](scope)
+	if err := scope.Dispose(context.Background()); err != nil || !conn.closed {
+		t.Errorf("Expected prototypes of a scope disposed with it, got closed=%v (%v)", conn.closed, err)
+	}
+}
+
+func TestPrototypeCleanups_NotKeptByLongLivedContainers(t *testing.T) {
+	var warnings []dshot.Warning
+	app := dshot.New(dshot.WithWarningHandler(func(w dshot.Warning) {
+		warnings = append(warnings, w)
+	}))
+	child := dshot.New(dshot.WithParent(app))
+
+	cleanups := 0
+	dshot.ProvideAutoPrototype(func() (*Repository, func(), error) {
+		return &Repository{}, func() { cleanups++ }, nil
+	}, app)
+
+	for range 3 {
+		dshot.MustResolve[*Repository](app)
+		dshot.MustResolve[*Repository](child)
+	}
+	child.Close(context.Background())
+	app.Close(context.Background())
+	if cleanups != 0 {
+		t.Errorf("Expected unowned cleanups dropped, got %d runs", cleanups)
+	}
+	if len(warnings) != 1 || warnings[0].Kind != dshot.WarnUnownedCleanup {
+		t.Errorf("Expected a single unowned cleanup warning, got %+v", warnings)
+	}
+
+	_, dispose := dshot.InvokeDisposable(func(repo *Repository) {}, app)
+	if err := dispose(); err != nil || cleanups != 1 {
+		t.Errorf("Expected the call to run the cleanup, got %d runs (%v)", cleanups, err)
+	}
+}
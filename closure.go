@@ -0,0 +1,81 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// BindClosure replaces the function stored in *fnPtr (typically a package-level function
+// variable) with one that calls impl. The leading parameters of impl are dependencies
+// resolved from the container (or default if not provided) on each call; the remaining
+// parameters and the results must match the function type of *fnPtr.
+// This eases migrating legacy code that calls package-level functions.
+//
+// Example:
+//
+//	var SendEmail = func(to, body string) error { ... }
+//
+//	container.BindClosure(&SendEmail, func(m *Mailer, to, body string) error {
+//	    return m.Send(to, body)
+//	})
+func BindClosure(fnPtr any, impl any, containers ...*Container) {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "BindClosure")
+
+	ptrValue := reflect.ValueOf(fnPtr)
+	if ptrValue.Kind() != reflect.Ptr || ptrValue.IsNil() || ptrValue.Elem().Kind() != reflect.Func {
+		panic("BindClosure: fnPtr must be a non-nil pointer to a function variable")
+	}
+	fnType := ptrValue.Elem().Type()
+
+	implValue := reflect.ValueOf(impl)
+	if implValue.Kind() != reflect.Func {
+		panic("BindClosure: impl must be a function")
+	}
+	implType := implValue.Type()
+
+	if implType.IsVariadic() != fnType.IsVariadic() {
+		panic("BindClosure: impl and the function variable must both be variadic or neither")
+	}
+
+	numDeps := implType.NumIn() - fnType.NumIn()
+	if numDeps < 0 {
+		panic(fmt.Sprintf("BindClosure: impl %s has fewer parameters than %s", implType, fnType))
+	}
+	for i := 0; i < fnType.NumIn(); i++ {
+		if implType.In(numDeps+i) != fnType.In(i) {
+			panic(fmt.Sprintf("BindClosure: impl %s does not end with the parameters of %s", implType, fnType))
+		}
+	}
+	if implType.NumOut() != fnType.NumOut() {
+		panic(fmt.Sprintf("BindClosure: impl %s does not return the results of %s", implType, fnType))
+	}
+	for i := 0; i < fnType.NumOut(); i++ {
+		if implType.Out(i) != fnType.Out(i) {
+			panic(fmt.Sprintf("BindClosure: impl %s does not return the results of %s", implType, fnType))
+		}
+	}
+
+	closure := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		in := make([]reflect.Value, 0, implType.NumIn())
+		for i := 0; i < numDeps; i++ {
+			depType := implType.In(i)
+			val, ok, err := c.resolveType(depType, nil)
+			if err != nil {
+				panic(withStep(err, paramStep(i, depType)))
+			}
+			if !ok {
+				panic(withStep(ErrNotFound, paramStep(i, depType)))
+			}
+			in = append(in, reflect.ValueOf(val))
+		}
+		in = append(in, args...)
+
+		if implType.IsVariadic() {
+			return implValue.CallSlice(in)
+		}
+		return implValue.Call(in)
+	})
+
+	ptrValue.Elem().Set(closure)
+}
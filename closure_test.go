@@ -0,0 +1,38 @@
+package dshot_test
+
+import (
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestBindClosure(t *testing.T) {
+	c := dshot.New()
+
+	describe := func(prefix string) string {
+		return prefix + ": legacy"
+	}
+
+	dshot.BindClosure(&describe, func(db *Database, prefix string) string {
+		return prefix + ": " + db.ConnectionString
+	}, c)
+
+	// Dependencies are resolved when the function is called
+	c.Provide(&Database{ConnectionString: "localhost"})
+
+	if got := describe("db"); got != "db: localhost" {
+		t.Errorf("Expected 'db: localhost', got '%s'", got)
+	}
+}
+
+func TestBindClosure_SignatureMismatch(t *testing.T) {
+	var fn func(string) error
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for mismatched signature")
+		}
+	}()
+
+	dshot.BindClosure(&fn, func(db *Database, n int) error { return nil }, dshot.New())
+}
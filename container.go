@@ -1,28 +1,119 @@
 package dshot
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"reflect"
+	"runtime"
 	"sync"
-
-	"github.com/overdevelop/dshot/internal/logger"
+	"sync/atomic"
+	"time"
+	"weak"
 )
 
+var containerType = reflect.TypeFor[*Container]()
+
 // Lifecycle determines how a factory-based dependency is instantiated
 type Lifecycle int
 
 const (
 	Singleton Lifecycle = iota
 	Prototype
+	Scoped // One instance per scope, see ProvideAutoScoped
 )
 
+// String returns the lifecycle name, e.g. "Singleton"
+func (l Lifecycle) String() string {
+	switch l {
+	case Singleton:
+		return "Singleton"
+	case Prototype:
+		return "Prototype"
+	case Scoped:
+		return "Scoped"
+	default:
+		return fmt.Sprintf("Lifecycle(%d)", int(l))
+	}
+}
+
 // Container holds a registry of dependencies
 type Container struct {
 	registry     map[any]*entry
 	typeRegistry map[reflect.Type][]*entry
-	parent       *Container // Parent container for scoped lookups
-	mu           sync.RWMutex
+	entries      []*entry // All entries in registration order
+	indexed      int      // Number of entries added to typeRegistry
+	deferIndex   bool     // Set during bulk registration
+	pendingIndex atomic.Bool
+	parent       *Container                        // Parent container for scoped lookups
+	cleanups     []func(ctx context.Context) error // Factory cleanups and singleton disposers, in creation order
+	clearHooks   []func(ClearEvent)                // Notified when the container is cleared, see OnClear
+	txScope      bool                              // Set on scopes created by RunInTx
+	ephemeral    bool                              // Created by NewScoped, disposes the prototypes resolved through it
+	afterCommit  []func(ctx context.Context) error
+	values       map[any]any // Request-local values, see SetValue
+	name         string      // Scope name, see ScopeInfo
+	namespace    string      // Isolation namespace, see WithNamespace
+	depth        int         // Number of parents
+	createdAt    time.Time
+
+	maxScopeDepth atomic.Int64 // Maximum scope nesting, 0 for unlimited
+	createdAtSite string       // Caller of NewScoped, recorded when maxScopeDepth is set
+
+	openScopes atomic.Int64 // Scopes of the global container not yet closed, see trackOpen
+	open       atomic.Bool  // Counted in the global container's openScopes
+	closed     atomic.Bool
+
+	view     bool        // Transparent child sharing its parent's scope identity
+	readOnly bool        // Set on views returned by ReadOnly
+	frozen   atomic.Bool // Registrations are immutable, see Freeze
+
+	allow map[reflect.Type]bool // Types resolvable from the parent, nil for all (see NewRestricted)
+
+	silent     bool            // Suppresses diagnostic warnings, see WithSilentDiagnostics
+	strict     bool            // Disables similar-type resolution, see WithStrictTypes
+	nilGuard   bool            // Rejects nil values and factory results, see WithNilGuard
+	sites      bool            // Records registration sites, see WithRegistrationSites
+	profiles   []string        // Active profiles, see WithActiveProfiles
+	drift      DriftPolicy     // Handling of token type drift, see WithTypeDrift
+	logger     *slog.Logger    // Diagnostics logger, see WithLogger
+	duplicates DuplicatePolicy // Handling of registrations made twice, see WithDuplicatePolicy
+	baseCtx    context.Context // Context for factories resolved without one, see WithBaseContext
+	snapshots  SnapshotStore   // Persists Snapshotter singletons, see WithSnapshots
+
+	postConstructHook func(ctx context.Context, v any) error // See WithPostConstruct
+
+	modules       map[string]bool           // Names of registered modules, see RegisterModule
+	lazyModules   []*lazyModule             // Modules waiting for a resolution miss, see RegisterLazyModule
+	loadingModule string                    // Module whose registrations are being made
+	exports       map[string]*moduleExports // Visible registrations of modules calling Export
+	resolvers     []Resolver                // Consulted after lazy modules, see AddResolver
+	resolverMu    sync.Mutex                // Serializes resolver instantiation
+
+	lifecycleHooks *LifecycleHooks        // Created on first use, see Start
+	degradations   map[*entry]Degradation // Latest fallback use per entry, see Degradations
+	interceptors   []Interceptor          // Wrap factory calls, see AddInterceptor
+	scoped         map[*entry]*scopedSlot // Instances of Scoped registrations in this scope
+	variantPicks   map[*entry]int         // Variant serving this scope per registration, see Variants
+
+	children     []weak.Pointer[Container] // Scopes created by NewScoped, for not-found hints
+	liveChildren int                       // Number of children after the last pruning
+
+	warming *warmupFlight // Warmup in progress, see Warmup
+
+	replacing bool // Set while Replace or Override registers, see replaceEntry
+
+	overridden atomic.Pointer[sync.Map] // Parent entries rebuilt in this scope (map[*entry]bool), see OverrideToken
+
+	warnings atomic.Pointer[func(Warning)] // Receives diagnostic warnings, see SetWarningHandler
+
+	failFast *failFast // Validation run on first resolution, see WithFailFast
+
+	ready     chan struct{} // Closed when Start completes, nil without WithReadinessGate
+	readyOnce sync.Once
+
+	mu sync.RWMutex
 }
 
 // New creates a new isolated container instance.
@@ -33,17 +124,31 @@ type Container struct {
 //	c := container.New()
 //	c.Provide(&Config{...})
 //	config := container.MustResolve[*Config](c)
-func New() *Container {
-	return &Container{
+func New(opts ...Option) *Container {
+	c := &Container{
 		registry:     make(map[any]*entry),
 		typeRegistry: make(map[reflect.Type][]*entry),
 		parent:       nil,
+		name:         "root",
+		createdAt:    time.Now(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.parent != nil {
+		c.name = "child"
+		c.attach()
 	}
+
+	return c
 }
 
 // NewScoped creates a new container that falls back to a parent container.
 // Registrations are local to this scope, but lookups check parent if not found locally.
 // Useful for request-scoped dependencies.
+// An optional name identifies the scope in ScopeInfo and diagnostics.
 //
 // Example:
 //
@@ -59,15 +164,44 @@ func New() *Container {
 //	    reqCtx := container.MustResolve[*RequestContext](reqContainer)
 //	    config := container.MustResolve[*Config](reqContainer) // Falls back to parent
 //	}
-func NewScoped(parent *Container) *Container {
+func NewScoped(parent *Container, name ...string) *Container {
 	if parent == nil {
 		panic("NewScoped: parent container cannot be nil")
 	}
 
-	return &Container{
+	scopeName := "scope"
+	if len(name) > 0 && name[0] != "" {
+		scopeName = name[0]
+	}
+
+	scope := &Container{
 		registry:     make(map[any]*entry),
 		typeRegistry: make(map[reflect.Type][]*entry),
 		parent:       parent,
+		name:         scopeName,
+		createdAt:    time.Now(),
+		ephemeral:    true,
+	}
+	scope.inherit(parent)
+	scope.attach()
+	if scope.root() == defaultContainer {
+		scope.trackOpen()
+	}
+
+	return scope
+}
+
+// attach links a new child container to its parent
+func (c *Container) attach() {
+	c.checkNamespace()
+	c.depth = c.parent.depth + 1
+	c.parent.addChild(c)
+
+	if maxDepth := c.maxScopeDepth.Load(); maxDepth > 0 {
+		c.createdAtSite = callerSite()
+		if int64(c.depth) > maxDepth {
+			panic(newScopeDepthError(c))
+		}
 	}
 }
 
@@ -79,7 +213,7 @@ func (c *Container) Provide(value any) {
 	}
 
 	token := &tokenKey{
-		key: fmt.Sprintf("__provided__%s", typ.String()),
+		key: providedKey(typ),
 	}
 
 	e := &entry{
@@ -91,8 +225,7 @@ func (c *Container) Provide(value any) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.registry[token] = e
-	c.typeRegistry[typ] = append(c.typeRegistry[typ], e)
+	c.addEntry(token, e)
 }
 
 // ProvideFactory registers a singleton factory function without a token.
@@ -115,18 +248,36 @@ func (c *Container) Register(registrations ...registration) {
 	}
 }
 
-// getEntry retrieves an entry, checking parent if not found locally
-func (c *Container) getEntry(token any) (*entry, bool) {
-	c.mu.RLock()
+// getEntry retrieves an entry, checking parent if not found locally.
+// Pending lazy modules providing the token are loaded until it is found; the error is
+// that of a module failing to load.
+func (c *Container) getEntry(token any) (*entry, bool, error) {
+	for {
+		if e, ok := c.lookupEntry(token); ok {
+			return e, true, nil
+		}
+		if loaded, err := c.loadModuleForToken(token); !loaded || err != nil {
+			return nil, false, err
+		}
+	}
+}
+
+// lookupEntry retrieves an already registered entry, checking parent if not found locally
+func (c *Container) lookupEntry(token any) (*entry, bool) {
+	locked := c.readLock()
 	e, ok := c.registry[token]
-	c.mu.RUnlock()
+	c.readUnlock(locked)
 
 	if ok {
 		return e, true
 	}
 
 	if c.parent != nil {
-		return c.parent.getEntry(token)
+		e, ok := c.parent.lookupEntry(token)
+		if ok && !c.allowsFromParent(e.depType) {
+			return nil, false
+		}
+		return e, ok
 	}
 
 	return nil, false
@@ -139,53 +290,151 @@ func (c *Container) Get(token any) any {
 		panic("cannot get with nil token")
 	}
 
-	e, ok := c.getEntry(token)
+	e, ok, err := c.getEntry(token)
+	if err != nil {
+		panic(err)
+	}
 	if !ok {
-		panic(fmt.Sprintf("dependency not found: %v", token))
+		panic(c.tokenNotFound(token).Error())
 	}
 
-	return e.resolve()
+	val, err := c.resolveEntry(e, nil)
+	if err != nil {
+		panic(withStep(err, tokenStep(token)))
+	}
+
+	return val
 }
 
 // Resolve attempts to find a dependency by type.
 // Falls back to the parent container if this is a scoped container.
 func (c *Container) Resolve(targetType reflect.Type) (any, bool) {
-	c.mu.RLock()
-	if entries, ok := c.typeRegistry[targetType]; ok && len(entries) > 0 {
-		c.mu.RUnlock()
-		if len(entries) > 1 {
-			panic(
-				fmt.Errorf(
-					"multiple candidates found for type %s: found %d registrations",
-					targetType.String(),
-					len(entries),
-				),
-			)
+	val, ok, err := c.resolveType(targetType, nil)
+	if err != nil {
+		panic(withStep(err, targetType.String()))
+	}
+	return val, ok
+}
+
+// resolveType is Resolve with resolution state threaded through nested factories.
+// It reports ok=false if nothing is registered for targetType, and an error if a
+// registration was found but could not be resolved. Pending lazy modules providing
+// targetType are loaded until it is found, then resolvers are asked to instantiate it.
+func (c *Container) resolveType(targetType reflect.Type, r *resolution) (any, bool, error) {
+	if elem, ok := optionalElem(targetType); ok {
+		val, err := c.resolveOptional(targetType, elem, r)
+		return val, err == nil, err
+	}
+
+	for {
+		val, ok, err := c.lookupType(targetType, r)
+		if ok || err != nil {
+			return val, ok, err
+		}
+		loaded, err := c.loadModuleForType(targetType)
+		if err != nil {
+			return nil, false, err
 		}
-		return entries[0].resolve(), true
+		if !loaded {
+			break
+		}
+	}
+
+	if !c.instantiate(targetType) {
+		return nil, false, nil
 	}
-	c.mu.RUnlock()
 
-	return c.findSingleEntry(targetType)
+	return c.lookupType(targetType, r)
 }
 
-// findSingleEntry scans registry for a single matching entry
-func (c *Container) findSingleEntry(targetType reflect.Type) (any, bool) {
+// lookupType resolves targetType from already registered entries. The resolving
+// container, its ScopeInfo and its LifecycleHooks are provided unless registered, the
+// hooks only where sharesHooks allows.
+func (c *Container) lookupType(targetType reflect.Type, r *resolution) (any, bool, error) {
+	cand, ok, err := c.findEntry(targetType, r.requester())
+	if err != nil {
+		return nil, false, err
+	}
+	if ok {
+		return cand.resolve(c, targetType, r)
+	}
+
+	switch targetType {
+	case containerType:
+		return c, true, nil
+	case scopeInfoType:
+		return c.ScopeInfo(), true, nil
+	case lifecycleHooksType:
+		if c.sharesHooks() {
+			return c.hooks(), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// findEntry selects the entry targetType resolves to for the factory of requester (nil
+// outside of any factory), without instantiating it. Registrations private to another
+// module are skipped; if nothing else matches, the error reports the private one.
+func (c *Container) findEntry(targetType reflect.Type, requester *entry) (candidate, bool, error) {
+	c.ensureIndexed()
+
+	var found, hidden *entry
+	count := 0
+
+	locked := c.readLock()
+	for _, e := range c.typeRegistry[targetType] {
+		if !e.visibleTo(requester) {
+			hidden = e
+			continue
+		}
+		found = e
+		count++
+	}
+	c.readUnlock(locked)
+
+	if count > 1 {
+		return candidate{}, false, fmt.Errorf(
+			"%w for type %s: found %d registrations",
+			ErrAmbiguous,
+			targetType.String(),
+			count,
+		)
+	}
+	if found != nil {
+		return candidate{e: found}, true, nil
+	}
+
+	cand, ok, err := c.findSingleEntry(targetType, requester)
+	if !ok && err == nil && hidden != nil {
+		return candidate{}, false, privateError(hidden)
+	}
+	return cand, ok, err
+}
+
+// findSingleEntry scans registry for a single matching entry visible to requester
+func (c *Container) findSingleEntry(targetType reflect.Type, requester *entry) (candidate, bool, error) {
 	var exactMatch *entry
 	var similarMatch *entry
+	var hidden *entry
 
-	c.mu.RLock()
-	for _, e := range c.registry {
+	locked := c.readLock()
+	for _, e := range c.entries {
 		valType := e.depType
 
+		if !e.visibleTo(requester) {
+			if hidden == nil && c.isExactMatch(targetType, valType) {
+				hidden = e
+			}
+			continue
+		}
+
 		if c.isExactMatch(targetType, valType) {
 			if exactMatch != nil {
-				c.mu.RUnlock()
-				panic(
-					fmt.Errorf(
-						"multiple candidates found for type %s in registry",
-						targetType.String(),
-					),
+				c.readUnlock(locked)
+				return candidate{}, false, fmt.Errorf(
+					"%w for type %s in registry",
+					ErrAmbiguous,
+					targetType.String(),
 				)
 			}
 			exactMatch = e
@@ -193,109 +442,176 @@ func (c *Container) findSingleEntry(targetType reflect.Type) (any, bool) {
 			similarMatch = e
 		}
 	}
-	c.mu.RUnlock()
+	c.readUnlock(locked)
 
 	if exactMatch != nil {
-		return exactMatch.resolve(), true
+		return candidate{e: exactMatch}, true, nil
 	}
 
-	if c.parent != nil {
-		if val, ok := c.parent.findSingleEntry(targetType); ok {
-			return val, true
+	if c.parent != nil && c.allowsFromParent(targetType) {
+		if cand, ok, err := c.parent.findSingleEntry(targetType, requester); ok || err != nil {
+			return cand, ok, err
 		}
 	}
+	if hidden != nil {
+		return candidate{}, false, privateError(hidden)
+	}
 
 	if similarMatch != nil {
-		logger.Warn(
-			fmt.Sprintf(
-				"No exact match for type %s, using similar type. "+
-					"Consider registering the exact type.",
+		if c.strict {
+			return candidate{}, false, fmt.Errorf(
+				"%w for type %s: %w (only %s is registered)",
+				ErrNotFound,
 				targetType,
-			),
-			slog.String("targetType", targetType.String()),
-		)
-		return c.resolveAndConvert(targetType, similarMatch, true)
+				ErrSimilarType,
+				similarMatch.depType,
+			)
+		}
+		if c.warnEnabled() {
+			c.warn(Warning{
+				Kind: WarnSimilarType,
+				Message: fmt.Sprintf(
+					"No exact match for type %s, using similar type. "+
+						"Consider registering the exact type.",
+					targetType,
+				),
+				Attrs: []slog.Attr{slog.String("targetType", targetType.String())},
+			})
+		}
+		return candidate{e: similarMatch, similar: true}, true, nil
 	}
 
-	return nil, false
+	return candidate{}, false, nil
 }
 
 // ResolveAll returns all registered values of type T.
 // Includes values from parent containers.
 func (c *Container) ResolveAll(targetType reflect.Type) []any {
-	seen := make(map[*entry]bool)
+	return c.resolveCandidates(targetType, c.candidates(targetType, nil), nil)
+}
 
-	c.mu.RLock()
-	capacity := len(c.typeRegistry[targetType])
-	c.mu.RUnlock()
+// resolveCandidates instantiates candidates of targetType in order
+func (c *Container) resolveCandidates(targetType reflect.Type, candidates []candidate, r *resolution) []any {
+	ordered := make([]orderedValue, 0, len(candidates))
+	for _, cand := range candidates {
+		resolved, ok, err := cand.resolve(c, targetType, r)
+		if err != nil {
+			panic(err)
+		}
+		if ok {
+			ordered = append(ordered, orderedValue{cand.e, resolved})
+		}
+	}
+	return sortedValues(ordered)
+}
 
-	results := make([]any, 0, capacity+4)
+// candidate is an entry matching a requested type, possibly through pointer conversion
+type candidate struct {
+	e       *entry
+	similar bool
+}
 
-	c.mu.RLock()
-	if typeEntries, ok := c.typeRegistry[targetType]; ok {
-		for _, e := range typeEntries {
-			if !seen[e] {
-				seen[e] = true
-				results = append(results, e.resolve())
+// resolve instantiates the candidate and converts it to the target type if needed
+func (cand candidate) resolve(c *Container, targetType reflect.Type, r *resolution) (any, bool, error) {
+	return c.resolveAndConvert(targetType, cand.e, cand.similar, r)
+}
+
+// candidates lists all entries matching targetType in this container and its parents
+// visible to requester (nil outside of any factory), without instantiating them
+func (c *Container) candidates(targetType reflect.Type, requester *entry) []candidate {
+	return c.findCandidates(targetType, true, requester)
+}
+
+// findCandidates lists entries matching targetType visible to requester, including parents
+// if withParents is set
+func (c *Container) findCandidates(targetType reflect.Type, withParents bool, requester *entry) []candidate {
+	seen := make(map[*entry]bool)
+
+	c.ensureIndexed()
+
+	locked := c.readLock()
+	typeEntries := c.typeRegistry[targetType]
+	results := make([]candidate, 0, len(typeEntries)+4)
+	for _, e := range typeEntries {
+		if !seen[e] {
+			seen[e] = true
+			if e.visibleTo(requester) {
+				results = append(results, candidate{e: e})
 			}
 		}
 	}
-	c.mu.RUnlock()
+	c.readUnlock(locked)
 
-	c.collectEntriesDirectly(targetType, seen, &results)
+	c.collectCandidates(targetType, seen, &results, withParents, requester)
 
 	return results
 }
 
-// collectEntriesDirectly scans the registry and appends resolved values directly to results
-func (c *Container) collectEntriesDirectly(targetType reflect.Type, seen map[*entry]bool, results *[]any) {
+// collectCandidates scans the registry and appends matching entries to results
+func (c *Container) collectCandidates(
+	targetType reflect.Type,
+	seen map[*entry]bool,
+	results *[]candidate,
+	withParents bool,
+	requester *entry,
+) {
 	var similarEntries []*entry
 	hasExactMatch := false
 
-	c.mu.RLock()
-	for _, e := range c.registry {
+	locked := c.readLock()
+	for _, e := range c.entries {
 		if seen[e] {
 			continue
 		}
+		if !e.visibleTo(requester) {
+			seen[e] = true
+			continue
+		}
 		valType := e.depType
 
 		if c.isExactMatch(targetType, valType) {
 			seen[e] = true
-			*results = append(*results, e.resolve())
+			*results = append(*results, candidate{e: e})
 			hasExactMatch = true
-		} else if c.isSimilarType(targetType, valType) {
+		} else if !c.strict && c.isSimilarType(targetType, valType) {
 			similarEntries = append(similarEntries, e)
 			seen[e] = true
 		}
 	}
-	c.mu.RUnlock()
+	c.readUnlock(locked)
 
-	if c.parent != nil {
-		c.parent.collectEntriesDirectly(targetType, seen, results)
+	if withParents && c.parent != nil && c.allowsFromParent(targetType) {
+		c.parent.collectCandidates(targetType, seen, results, true, requester)
 	}
 
 	if !hasExactMatch && len(similarEntries) > 0 {
-		logger.Warn(
-			fmt.Sprintf(
-				"No exact match for type %s, using %d similar type(s). "+
-					"Consider registering the exact type.",
-				targetType,
-				len(similarEntries),
-			),
-			slog.String("targetType", targetType.String()),
-			slog.Int("similarEntries", len(similarEntries)),
-		)
+		if c.warnEnabled() {
+			c.warn(Warning{
+				Kind: WarnSimilarType,
+				Message: fmt.Sprintf(
+					"No exact match for type %s, using %d similar type(s). "+
+						"Consider registering the exact type.",
+					targetType,
+					len(similarEntries),
+				),
+				Attrs: []slog.Attr{
+					slog.String("targetType", targetType.String()),
+					slog.Int("similarEntries", len(similarEntries)),
+				},
+			})
+		}
 
 		for _, e := range similarEntries {
-			if resolved, ok := c.resolveAndConvert(targetType, e, true); ok {
-				*results = append(*results, resolved)
-			}
+			*results = append(*results, candidate{e: e, similar: true})
 		}
 	}
 }
 
 // isExactMatch checks if valType exactly matches or is assignable to targetType
 func (c *Container) isExactMatch(targetType, valType reflect.Type) bool {
+	if valType == nil {
+		return false
+	}
 	if targetType.Kind() == reflect.Interface {
 		return valType.Implements(targetType)
 	}
@@ -304,7 +620,7 @@ func (c *Container) isExactMatch(targetType, valType reflect.Type) bool {
 
 // isSimilarType checks if valType is a similar type (pointer mismatch)
 func (c *Container) isSimilarType(targetType, valType reflect.Type) bool {
-	if targetType == valType {
+	if valType == nil || targetType == valType {
 		return false
 	}
 
@@ -320,13 +636,33 @@ func (c *Container) isSimilarType(targetType, valType reflect.Type) bool {
 }
 
 // resolveAndConvert resolves an entry and converts it to the target type if needed
-func (c *Container) resolveAndConvert(targetType reflect.Type, e *entry, needsConversion bool) (any, bool) {
-	resolved := e.resolve()
+func (c *Container) resolveAndConvert(
+	targetType reflect.Type,
+	e *entry,
+	needsConversion bool,
+	r *resolution,
+) (any, bool, error) {
+	if needsConversion {
+		if err := checkLockCopy(e.depType, targetType); err != nil {
+			return nil, false, err
+		}
+	}
+
+	resolved, err := c.resolveEntry(e, r)
+	if err != nil {
+		return nil, false, err
+	}
 
 	if !needsConversion {
-		return resolved, true
+		return resolved, true, nil
 	}
 
+	val, ok := c.convert(targetType, resolved)
+	return val, ok, nil
+}
+
+// convert converts a resolved value to a similar target type (pointer mismatch)
+func (c *Container) convert(targetType reflect.Type, resolved any) (any, bool) {
 	resolvedVal := reflect.ValueOf(resolved)
 	resolvedType := resolvedVal.Type()
 
@@ -336,11 +672,16 @@ func (c *Container) resolveAndConvert(targetType reflect.Type, e *entry, needsCo
 
 	if targetType.Kind() == reflect.Ptr && resolvedType.Kind() != reflect.Ptr {
 		if targetType.Elem() != resolvedType {
-			logger.Warn(
-				fmt.Sprintf("Type mismatch: cannot convert %s to %s", resolvedType, targetType),
-				slog.String("resolvedType", resolvedType.String()),
-				slog.String("targetType", targetType.String()),
-			)
+			if c.warnEnabled() {
+				c.warn(Warning{
+					Kind:    WarnTypeMismatch,
+					Message: fmt.Sprintf("Type mismatch: cannot convert %s to %s", resolvedType, targetType),
+					Attrs: []slog.Attr{
+						slog.String("resolvedType", resolvedType.String()),
+						slog.String("targetType", targetType.String()),
+					},
+				})
+			}
 			return nil, false
 		}
 
@@ -356,11 +697,16 @@ func (c *Container) resolveAndConvert(targetType reflect.Type, e *entry, needsCo
 
 	if targetType.Kind() != reflect.Ptr && resolvedType.Kind() == reflect.Ptr {
 		if resolvedType.Elem() != targetType {
-			logger.Warn(
-				fmt.Sprintf("Type mismatch: cannot convert %s to %s", resolvedType, targetType),
-				slog.String("resolvedType", resolvedType.String()),
-				slog.String("targetType", targetType.String()),
-			)
+			if c.warnEnabled() {
+				c.warn(Warning{
+					Kind:    WarnTypeMismatch,
+					Message: fmt.Sprintf("Type mismatch: cannot convert %s to %s", resolvedType, targetType),
+					Attrs: []slog.Attr{
+						slog.String("resolvedType", resolvedType.String()),
+						slog.String("targetType", targetType.String()),
+					},
+				})
+			}
 			return nil, false
 		}
 
@@ -376,19 +722,38 @@ func (c *Container) resolveAndConvert(targetType reflect.Type, e *entry, needsCo
 }
 
 // Inject populates a struct's fields by resolving them from the container.
+// A dshot tag controls each field: `dshot:"-"` skips it, `dshot:"optional"` leaves it
+// zero when nothing is registered and `dshot:"token=primary-db"` resolves the token of
+// that name instead of the field type.
+//
+// Example:
+//
+//	type Handler struct {
+//	    DB     *sql.DB `dshot:"token=primary-db"`
+//	    Tracer *Tracer `dshot:"optional"`
+//	    cache  *Cache  // Unexported fields are left alone
+//	    Stats  *Stats  `dshot:"-"`
+//	}
+//	c.Inject(&h)
 func (c *Container) Inject(target any) {
-	targetValue := reflect.ValueOf(target)
-	targetType := targetValue.Type()
+	if err := c.InjectE(target); err != nil {
+		panic("Inject: " + err.Error())
+	}
+}
 
-	if targetType.Kind() != reflect.Ptr {
-		panic("Inject: target must be a pointer to a struct")
+// inject is Inject with resolution state threaded through nested factories
+func (c *Container) inject(target any, r *resolution) error {
+	target, unexported := unwrapInjectTarget(target)
+	targetValue := reflect.ValueOf(target)
+	if !targetValue.IsValid() || targetValue.Kind() != reflect.Ptr {
+		return errors.New("Inject: target must be a pointer to a struct")
 	}
 
-	targetType = targetType.Elem()
+	targetType := targetValue.Type().Elem()
 	targetValue = targetValue.Elem()
 
 	if targetType.Kind() != reflect.Struct {
-		panic("Inject: target must be a pointer to a struct")
+		return errors.New("Inject: target must be a pointer to a struct")
 	}
 
 	for i := 0; i < targetType.NumField(); i++ {
@@ -396,41 +761,118 @@ func (c *Container) Inject(target any) {
 		fieldValue := targetValue.Field(i)
 
 		if !fieldValue.CanSet() {
+			if !unexported || field.IsExported() {
+				continue
+			}
+			if err := c.injectUnexported(field, fieldValue, r); err != nil {
+				return withStep(err, fieldStep(field))
+			}
+			continue
+		}
+
+		if isMarker(field.Type) {
+			continue
+		}
+
+		tag, err := parseInjectTag(field)
+		if err != nil {
+			return withStep(err, fieldStep(field))
+		}
+		if tag.skip {
+			continue
+		}
+		if tag.name != "" {
+			val, err := c.resolveNamed(tag.name, field.Type, tag.optional, r)
+			if err != nil {
+				return withStep(err, fieldStep(field))
+			}
+			fieldValue.Set(val)
+			continue
+		}
+
+		if isKeyedGroup(field.Type) {
+			val, err := c.resolveKeyedGroup(field.Type, r)
+			if err != nil {
+				return withStep(err, fieldStep(field))
+			}
+			fieldValue.Set(val)
 			continue
 		}
 
-		if val, ok := c.Resolve(field.Type); ok {
+		val, ok, err := c.resolveType(field.Type, r)
+		if err != nil {
+			return withStep(err, fieldStep(field))
+		}
+		if ok {
 			fieldValue.Set(reflect.ValueOf(val))
 			continue
 		}
 
 		if field.Type.Kind() == reflect.Struct {
 			newStruct := reflect.New(field.Type)
-			c.Inject(newStruct.Interface())
+			nested := newStruct.Interface()
+			if unexported {
+				nested = IncludeUnexported(nested)
+			}
+			if err := c.inject(nested, r); err != nil {
+				return withStep(err, fieldStep(field))
+			}
 			fieldValue.Set(newStruct.Elem())
 			continue
 		}
 
-		panic(
-			fmt.Sprintf(
-				"Inject: could not resolve field %s (%s) in struct %s",
-				field.Name, field.Type, targetType.Name(),
-			),
-		)
+		if tag.optional {
+			continue
+		}
+
+		return withStep(c.notFound(field.Type), fieldStep(field))
 	}
+
+	return nil
 }
 
-// Clear removes all dependencies from this container (does not affect parent)
+// Clear removes all dependencies from this container (does not affect parent).
+// Instances already built are not disposed until the container is closed; use
+// ClearAndDispose to dispose them now. OnClear callbacks are notified.
 func (c *Container) Clear() {
+	c.notifyClear(ClearEvent{Registrations: c.clear("Clear")})
+}
+
+// clear removes the registrations, returning how many there were. op names the
+// operation in the panic if c is not writable.
+func (c *Container) clear(op string) int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.checkWritable(op)
+
+	removed := len(c.entries)
 	c.registry = make(map[any]*entry)
 	c.typeRegistry = make(map[reflect.Type][]*entry)
+	c.entries = nil
+	c.indexed = 0
+	c.pendingIndex.Store(false)
+	c.modules = nil
+	c.exports = nil
+	c.lazyModules = nil
+	c.scoped = nil
+
+	return removed
 }
 
-// Parent returns the parent container, or nil if this is a root container
+// Parent returns the parent container, or nil if this is a root container.
+// The parent of a read-only view is itself a read-only view. Restricted containers
+// (see NewRestricted) return nil, which would otherwise escape their allowlist.
 func (c *Container) Parent() *Container {
+	if c.allow != nil {
+		return nil
+	}
+	if c.readOnly {
+		if c.parent.parent == nil || c.parent.allow != nil {
+			return nil
+		}
+		return c.parent.parent.ReadOnly()
+	}
 	return c.parent
 }
 
@@ -448,13 +890,13 @@ func (c *Container) provideFactoryWithLifecycle(factory any, lifecycle Lifecycle
 
 	returnType := fnType.Out(0)
 	token := &tokenKey{
-		key: fmt.Sprintf("__provided__%s", returnType.String()),
+		key: providedKey(returnType),
 	}
 
 	e := &entry{
-		factory: func() any {
+		factory: func(*resolution) (any, error) {
 			results := fnValue.Call(nil)
-			return results[0].Interface()
+			return results[0].Interface(), nil
 		},
 		lifecycle: lifecycle,
 		depType:   returnType,
@@ -463,6 +905,72 @@ func (c *Container) provideFactoryWithLifecycle(factory any, lifecycle Lifecycle
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.addEntry(token, e)
+}
+
+// addEntry indexes an entry under its token and type. Callers must hold c.mu.
+func (c *Container) addEntry(token any, e *entry) {
+	c.checkWritable("register")
+
+	e.token = token
+	e.owner = c
+	e.module = c.loadingModule
+	e.hidden.Store(e.private && e.module != "")
+	e.registeredAt = time.Now()
+	if c.sites || c.nilGuard {
+		pcs := make([]uintptr, 8)
+		e.sitePCs = pcs[:runtime.Callers(2, pcs)]
+	}
+	if c.nilGuard {
+		c.guardRegistration(e)
+	}
+
+	if c.replacing || c.duplicates == DuplicateReplace {
+		if c.replaceEntry(token, e) {
+			return
+		}
+	} else if c.duplicates == DuplicateError {
+		if prev := c.duplicateOf(token); prev != nil {
+			if site := prev.registrationSite(); site != "" {
+				panic(fmt.Sprintf("%s is already registered (at %s)", prev.label(), site))
+			}
+			panic(fmt.Sprintf("%s is already registered", prev.label()))
+		}
+	}
+
 	c.registry[token] = e
-	c.typeRegistry[returnType] = append(c.typeRegistry[returnType], e)
+	c.entries = append(c.entries, e)
+
+	if c.deferIndex || c.indexed < len(c.entries)-1 {
+		c.pendingIndex.Store(true)
+		return
+	}
+	c.indexEntry(e)
+	c.indexed++
+}
+
+// indexEntry adds an entry to the type index. Callers must hold c.mu.
+func (c *Container) indexEntry(e *entry) {
+	if e.depType != nil {
+		c.typeRegistry[e.depType] = append(c.typeRegistry[e.depType], e)
+	}
+	for _, alias := range e.aliases {
+		c.typeRegistry[alias] = append(c.typeRegistry[alias], e)
+	}
+}
+
+// ensureIndexed indexes entries whose indexing was deferred by a bulk registration
+func (c *Container) ensureIndexed() {
+	if !c.pendingIndex.Load() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range c.entries[c.indexed:] {
+		c.indexEntry(e)
+	}
+	c.indexed = len(c.entries)
+	c.pendingIndex.Store(false)
 }
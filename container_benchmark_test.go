@@ -182,3 +182,25 @@ func BenchmarkToken_CreationWithName(b *testing.B) {
 		dshot.NewToken[*Service]("named-token")
 	}
 }
+
+func BenchmarkResolve_SimilarType(b *testing.B) {
+	c := dshot.New()
+	c.Provide(Service{Name: "Benchmark"})
+	typ := reflect.TypeOf((*Service)(nil))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Resolve(typ)
+	}
+}
+
+func BenchmarkResolve_SimilarTypeSilent(b *testing.B) {
+	c := dshot.New(dshot.WithSilentDiagnostics())
+	c.Provide(Service{Name: "Benchmark"})
+	typ := reflect.TypeOf((*Service)(nil))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Resolve(typ)
+	}
+}
@@ -35,7 +35,9 @@ func FromContext(ctx context.Context) *Container {
 	if c, ok := ctx.Value(containerCtxKey{}).(*Container); ok {
 		return c
 	}
-	return defaultContainer
+	c := global()
+	checkGlobalUse(c, "FromContext")
+	return c
 }
 
 // GetCtx retrieves a value by token from the container in context.
@@ -45,7 +47,21 @@ func FromContext(ctx context.Context) *Container {
 //
 //	broker := container.GetCtx[*Broker](ctx, brokerToken)
 func GetCtx[T any](ctx context.Context, token *Token[T]) T {
-	return FromContext(ctx).Get(token).(T)
+	c := FromContext(ctx)
+	e, ok, err := c.getEntry(token)
+	if err != nil {
+		panic(err)
+	}
+	if !ok {
+		panic(c.tokenNotFound(token).Error())
+	}
+
+	val, err := c.resolveEntry(e, newContextResolution(ctx))
+	if err != nil {
+		panic(withStep(err, tokenStep(token)))
+	}
+
+	return mustTyped(typedByToken[T](val, c, token))
 }
 
 // FindCtx retrieves a value by token from the container in context.
@@ -58,12 +74,19 @@ func GetCtx[T any](ctx context.Context, token *Token[T]) T {
 //	}
 func FindCtx[T any](ctx context.Context, token *Token[T]) (T, bool) {
 	c := FromContext(ctx)
-	e, ok := c.getEntry(token)
+	e, ok, err := c.getEntry(token)
+	if err != nil {
+		panic(err)
+	}
 	if !ok {
 		var zero T
 		return zero, false
 	}
-	return e.resolve().(T), true
+	val, err := c.resolveEntry(e, newContextResolution(ctx))
+	if err != nil {
+		panic(withStep(err, tokenStep(token)))
+	}
+	return mustTyped(typedByToken[T](val, c, token)), true
 }
 
 // ResolveCtx attempts to find a dependency by type from the container in context.
@@ -82,12 +105,15 @@ func ResolveCtx[T any](ctx context.Context) (T, bool) {
 	}
 
 	c := FromContext(ctx)
-	val, ok := c.Resolve(targetType)
+	val, ok, err := c.resolveType(targetType, newContextResolution(ctx))
+	if err != nil {
+		panic(withStep(err, targetType.String()))
+	}
 	if !ok {
 		return zero, false
 	}
 
-	return val.(T), true
+	return mustTyped(typedByType[T](val, c)), true
 }
 
 // MustResolveCtx resolves by type from the container in context and panics if not found.
@@ -121,11 +147,11 @@ func ResolveAllCtx[T any](ctx context.Context) []T {
 	}
 
 	c := FromContext(ctx)
-	results := c.ResolveAll(targetType)
+	results := c.resolveCandidates(targetType, c.candidates(targetType, nil), newContextResolution(ctx))
 
 	typed := make([]T, len(results))
 	for i, val := range results {
-		typed[i] = val.(T)
+		typed[i] = mustTyped(typedByType[T](val, c))
 	}
 
 	return typed
@@ -142,7 +168,9 @@ func ResolveAllCtx[T any](ctx context.Context) []T {
 //	var deps Dependencies
 //	container.InjectCtx(ctx, &deps)
 func InjectCtx(ctx context.Context, target any) {
-	FromContext(ctx).Inject(target)
+	if err := FromContext(ctx).inject(target, newContextResolution(ctx)); err != nil {
+		panic("Inject: " + err.Error())
+	}
 }
 
 // CallCtx calls a function, resolving its dependencies from the container in context.
@@ -153,7 +181,11 @@ func InjectCtx(ctx context.Context, target any) {
 //	    return NewService(config, reqCtx)
 //	})
 func CallCtx[T any](ctx context.Context, fn any) T {
-	return Call[T](fn, FromContext(ctx))
+	results, err := FromContext(ctx).invoke(fn, newContextResolution(ctx))
+	if err != nil {
+		panic(err)
+	}
+	return mustTyped(typedResult[T](results[0], "CallCtx: function"))
 }
 
 // CallCtxErr calls a function that returns (T, error), resolving from context.
@@ -164,7 +196,36 @@ func CallCtx[T any](ctx context.Context, fn any) T {
 //	    return NewService(config)
 //	})
 func CallCtxErr[T any](ctx context.Context, fn any) (T, error) {
-	return CallErr[T](fn, FromContext(ctx))
+	var zero T
+	results, err := FromContext(ctx).invoke(fn, newContextResolution(ctx))
+	if err != nil {
+		panic(err)
+	}
+	if len(results) != 2 {
+		return zero, fmt.Errorf("CallCtxErr: function must return (T, error)")
+	}
+
+	val, err := typedResult[T](results[0], "CallCtxErr: function")
+	if err != nil {
+		return zero, err
+	}
+	if results[1] == nil {
+		return val, nil
+	}
+	return val, results[1].(error)
+}
+
+// InvokeCtxE calls fn, resolving its parameters from the container in context, and returns
+// its results. context.Context parameters receive ctx. Resolution failures are returned
+// instead of panicking.
+//
+// Example:
+//
+//	results, err := container.InvokeCtxE(ctx, func(ctx context.Context, repo *Repo) error {
+//	    return repo.Ping(ctx)
+//	})
+func InvokeCtxE(ctx context.Context, fn any) ([]any, error) {
+	return FromContext(ctx).invoke(fn, newContextResolution(ctx))
 }
 
 // BuildCtx creates an instance by injecting dependencies from the container in context.
@@ -179,5 +240,5 @@ func CallCtxErr[T any](ctx context.Context, fn any) (T, error) {
 //	    return &Service{config: deps.Config, reqCtx: deps.ReqCtx}
 //	})
 func BuildCtx[T any](ctx context.Context, constructor any) T {
-	return Build[T](constructor, FromContext(ctx))
+	return CallCtx[T](ctx, constructor)
 }
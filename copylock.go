@@ -0,0 +1,75 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var lockerType = reflect.TypeFor[sync.Locker]()
+
+// lockTypes caches, per type, the path to a lock it holds by value, "" for none
+var lockTypes sync.Map // map[reflect.Type]string
+
+// checkLockCopy rejects a similar-type conversion between *T and T when T holds a lock by
+// value (sync.Mutex, sync.WaitGroup, atomic.Int64, ...): the conversion would copy T, and
+// a copied lock no longer guards the original's state.
+func checkLockCopy(registeredType, targetType reflect.Type) error {
+	valueType := targetType
+	if valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	if path := lockPath(valueType); path != "" {
+		return fmt.Errorf(
+			"cannot resolve %s from the %s registration: converting would copy %s, which holds a lock (%s); register %s instead",
+			targetType, registeredType, valueType, path, targetType,
+		)
+	}
+
+	return nil
+}
+
+// lockPath returns the field path of a lock held by value in t, as go vet's copylocks
+// check detects them: a type whose pointer has Lock and Unlock methods
+func lockPath(t reflect.Type) string {
+	if path, ok := lockTypes.Load(t); ok {
+		return path.(string)
+	}
+
+	var path string
+	if fields, lock := findLock(t, 0); lock != nil {
+		path = lock.String()
+		if len(fields) > 0 {
+			path = "field " + strings.Join(fields, ".") + " " + path
+		}
+	}
+
+	lockTypes.Store(t, path)
+	return path
+}
+
+// findLock returns the lock type held by value in t and the fields leading to it
+func findLock(t reflect.Type, depth int) ([]string, reflect.Type) {
+	if depth > 16 {
+		return nil, nil
+	}
+	if t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(lockerType) {
+		return nil, t
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if fields, lock := findLock(field.Type, depth+1); lock != nil {
+				return append([]string{field.Name}, fields...), lock
+			}
+		}
+	case reflect.Array:
+		return findLock(t.Elem(), depth+1)
+	}
+
+	return nil, nil
+}
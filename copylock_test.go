@@ -0,0 +1,41 @@
+package dshot_test
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type guardedCounter struct {
+	mu sync.Mutex
+	n  int
+}
+
+type atomicStats struct {
+	Hits struct {
+		count atomic.Int64
+	}
+}
+
+func TestSimilarType_RefusesCopyingLocks(t *testing.T) {
+	c := dshot.New(dshot.WithSilentDiagnostics())
+	c.Provide(&guardedCounter{})
+	c.Provide(atomicStats{})
+
+	_, err := dshot.ResolveE[guardedCounter](c)
+	if err == nil || !strings.Contains(err.Error(), "holds a lock (field mu sync.Mutex)") {
+		t.Errorf("Expected the lock copy refused, got %v", err)
+	}
+	_, err = dshot.ResolveE[*atomicStats](c)
+	if err == nil || !strings.Contains(err.Error(), "field Hits.count.") {
+		t.Errorf("Expected the atomic copy refused, got %v", err)
+	}
+
+	c.Provide(Database{})
+	if _, err := dshot.ResolveE[*Database](c); err != nil {
+		t.Errorf("Expected lock-free types still converted, got %v", err)
+	}
+}
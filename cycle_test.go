@@ -0,0 +1,36 @@
+package dshot_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type cycleA struct{ B *cycleB }
+type cycleB struct{ A *cycleA }
+
+func TestCircularDependency_Singleton(t *testing.T) {
+	c := dshot.New()
+	dshot.ProvideAutoFactory(func(b *cycleB) *cycleA { return &cycleA{B: b} }, c)
+	dshot.ProvideAutoFactory(func(a *cycleA) *cycleB { return &cycleB{A: a} }, c)
+
+	_, err := dshot.ResolveE[*cycleA](c)
+	if !errors.Is(err, dshot.ErrCircularDependency) {
+		t.Fatalf("Expected ErrCircularDependency, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "*dshot_test.cycleA -> *dshot_test.cycleB -> *dshot_test.cycleA") {
+		t.Errorf("Expected cycle path in error, got %v", err)
+	}
+}
+
+func TestCircularDependency_Prototype(t *testing.T) {
+	c := dshot.New()
+	dshot.ProvideAutoPrototype(func(b *cycleB) *cycleA { return &cycleA{B: b} }, c)
+	dshot.ProvideAutoPrototype(func(a *cycleA) *cycleB { return &cycleB{A: a} }, c)
+
+	if _, err := dshot.ResolveE[*cycleB](c); !errors.Is(err, dshot.ErrCircularDependency) {
+		t.Fatalf("Expected ErrCircularDependency, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Decorate wraps the registration of token with decorator, a func(inner T, deps...) T or
+// func(inner T, deps...) (T, error). The decorator receives the value built by the original
+// factory (or the bound value) and returns the value resolved in its place; its other
+// parameters are auto-wired from the registering container. Decorators stack in the order
+// they are added, the first one wrapping the original value. The token must be registered
+// in the container itself, and decorators must be added before it is first resolved.
+//
+// Example:
+//
+//	container.Register(container.BindAutoFactory(repoToken, NewPostgresRepository))
+//	container.Decorate(repoToken, func(inner Repository, cache *Cache) Repository {
+//	    return &CachedRepository{inner: inner, cache: cache}
+//	})
+//	container.Decorate(repoToken, func(inner Repository, log *slog.Logger) Repository {
+//	    return &LoggedRepository{inner: inner, log: log} // Wraps the cached repository
+//	})
+func Decorate[T any](token *Token[T], decorator any, containers ...*Container) {
+	c := containerOrGlobal(containers)
+	checkGlobalRegistration(c, "Decorate")
+	c.checkWritable("Decorate")
+
+	fnValue := reflect.ValueOf(decorator)
+	fnType := fnValue.Type()
+	targetType := reflect.TypeFor[T]()
+
+	if fnType.Kind() != reflect.Func {
+		panic("Decorate: decorator must be a function")
+	}
+	if fnType.NumIn() == 0 || fnType.In(0) != targetType {
+		panic(fmt.Sprintf("Decorate: decorator must take the decorated %v as its first parameter", targetType))
+	}
+	withError := fnType.NumOut() == 2 && fnType.Out(1) == errorType
+	if fnType.NumOut() != 1 && !withError || fnType.Out(0) != targetType {
+		panic(fmt.Sprintf("Decorate: decorator must return %v or (%v, error)", targetType, targetType))
+	}
+
+	e, ok, err := c.getEntry(token)
+	if err != nil {
+		panic(fmt.Sprintf("Decorate: %v", err))
+	}
+	if !ok || e.owner != c {
+		panic(fmt.Sprintf("Decorate: token %q is not registered in this container", token.key))
+	}
+
+	// Not holding c.mu while locking e.mu: resolution locks them the other way around
+	func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.checkWritable("Decorate")
+	}()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.done {
+		panic(fmt.Sprintf("Decorate: token %q has already been resolved", token.key))
+	}
+
+	inner := e.factory
+	if inner == nil {
+		value := e.value
+		inner = func(*resolution) (any, error) {
+			return value, nil
+		}
+	}
+
+	e.factory = func(r *resolution) (any, error) {
+		val, err := inner(r)
+		if err != nil {
+			return nil, err
+		}
+		return callDecorator(r.in(c), fnValue, fnType, val, withError, token.key, r)
+	}
+	e.wiring = decoratedWiring(c, e.wiring, fnType)
+}
+
+// callDecorator resolves the dependencies of a decorator and calls it with the inner value
+func callDecorator(
+	c *Container,
+	fnValue reflect.Value,
+	fnType reflect.Type,
+	inner any,
+	withError bool,
+	tokenKey string,
+	r *resolution,
+) (any, error) {
+	numIn := fnType.NumIn()
+	args := make([]reflect.Value, numIn)
+	args[0] = reflect.ValueOf(inner)
+	if inner == nil {
+		args[0] = reflect.Zero(fnType.In(0))
+	}
+
+	for i := 1; i < numIn; i++ {
+		paramType := fnType.In(i)
+		arg, err := resolveParameter(c, paramType, numIn, r)
+		if err != nil {
+			return nil, withStep(err, paramStep(i, paramType))
+		}
+		args[i] = arg
+	}
+
+	results := fnValue.Call(args)
+
+	if withError && !results[1].IsNil() {
+		err := results[1].Interface().(error)
+		return nil, fmt.Errorf("decorator[%v] returned error: %w", tokenKey, err)
+	}
+
+	return results[0].Interface(), nil
+}
+
+// decoratedWiring adds the dependencies of a decorator to the wiring checked by Validate
+func decoratedWiring(c *Container, w *wiring, fnType reflect.Type) *wiring {
+	if w != nil && w.c != c {
+		return w
+	}
+
+	decorated := &wiring{c: c}
+	if w != nil {
+		decorated.params = append(decorated.params, w.params...)
+	}
+	for i := 1; i < fnType.NumIn(); i++ {
+		decorated.params = append(decorated.params, fnType.In(i))
+	}
+
+	return decorated
+}
@@ -0,0 +1,86 @@
+package dshot_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/overdevelop/dshot"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type greeting string
+
+func (g greeting) Greet() string { return string(g) }
+
+func TestDecorate_StacksInRegistrationOrder(t *testing.T) {
+	c := dshot.New()
+	token := dshot.NewToken[*Service]("service")
+	c.Provide(&Database{ConnectionString: "log"})
+
+	c.Register(dshot.BindAutoFactory(token, func() *Service { return &Service{Name: "hello"} }, c))
+	dshot.Decorate(token, func(inner *Service) *Service {
+		return &Service{Name: "cache(" + inner.Name + ")"}
+	}, c)
+	dshot.Decorate(token, func(inner *Service, db *Database) *Service {
+		return &Service{Name: db.ConnectionString + "(" + inner.Name + ")"}
+	}, c)
+
+	if got := dshot.Get(token, c).Name; got != "log(cache(hello))" {
+		t.Errorf("Expected decorators applied in order, got %q", got)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
+
+func TestDecorate_BoundValueAndErrors(t *testing.T) {
+	c := dshot.New()
+	token := dshot.NewToken[greeter]("greeter")
+	c.Register(dshot.Bind[greeter](token, greeting("hi")))
+
+	dshot.Decorate(token, func(inner greeter) (greeter, error) {
+		return nil, errors.New("no cache")
+	}, c)
+
+	_, err := dshot.GetE(token, c)
+	if err == nil || !strings.Contains(err.Error(), "no cache") {
+		t.Fatalf("Expected the decorator error, got %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected a panic for a token registered in the parent")
+		}
+	}()
+	dshot.Decorate(token, func(inner greeter) greeter { return inner }, dshot.NewScoped(c))
+}
+
+func TestDecorate_ConcurrentWithResolution(t *testing.T) {
+	c := dshot.New()
+	token := dshot.NewToken[*Service]("service")
+	c.Provide(&Database{})
+
+	building := make(chan struct{})
+	c.Register(dshot.BindAutoFactory(token, func() *Service {
+		close(building)
+		time.Sleep(10 * time.Millisecond) // Let Decorate wait for the entry
+		dshot.MustResolve[*Database](c)
+		return &Service{}
+	}, c))
+
+	go dshot.Get(token, c)
+	<-building
+
+	defer func() {
+		if msg := fmt.Sprint(recover()); !strings.Contains(msg, "already been resolved") {
+			t.Errorf("Expected Decorate to wait for the build, got %q", msg)
+		}
+	}()
+	dshot.Decorate(token, func(inner *Service) *Service { return inner }, c)
+}
@@ -0,0 +1,54 @@
+package dshot
+
+import (
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Degradation records that a registration is served by its fallback factory
+type Degradation struct {
+	Token string
+	Type  reflect.Type
+	Err   error // Error of the primary factory
+	At    time.Time
+}
+
+// recordDegradation records the latest primary failure of e
+func (c *Container) recordDegradation(e *entry, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.degradations == nil {
+		c.degradations = make(map[*entry]Degradation)
+	}
+	c.degradations[e] = Degradation{
+		Token: tokenName(e.token),
+		Type:  e.depType,
+		Err:   err,
+		At:    time.Now(),
+	}
+}
+
+// Degradations lists the registrations of this container that were constructed by their
+// fallback factory, most recent first, for health checks and status pages.
+//
+// Example:
+//
+//	for _, d := range c.Degradations() {
+//	    health.Warn(d.Token, d.Err)
+//	}
+func (c *Container) Degradations() []Degradation {
+	c.mu.RLock()
+	result := make([]Degradation, 0, len(c.degradations))
+	for _, d := range c.degradations {
+		result = append(result, d)
+	}
+	c.mu.RUnlock()
+
+	slices.SortFunc(result, func(a, b Degradation) int {
+		return b.At.Compare(a.At)
+	})
+
+	return result
+}
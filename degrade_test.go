@@ -0,0 +1,54 @@
+package dshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestFallback_OnFactoryError(t *testing.T) {
+	c := dshot.New()
+	errDown := errors.New("redis unreachable")
+	token := dshot.NewToken[*Service]("cache")
+
+	c.Register(
+		dshot.BindAutoFactoryErr(token, func() (*Service, error) {
+			return nil, errDown
+		}, c).Fallback(func() *Service {
+			return &Service{Name: "memory"}
+		}),
+	)
+
+	if svc := dshot.Get(token, c); svc.Name != "memory" {
+		t.Errorf("Expected fallback value, got '%s'", svc.Name)
+	}
+
+	degradations := c.Degradations()
+	if len(degradations) != 1 {
+		t.Fatalf("Expected 1 degradation, got %d", len(degradations))
+	}
+	if degradations[0].Token != "cache" || !errors.Is(degradations[0].Err, errDown) {
+		t.Errorf("Unexpected degradation: %+v", degradations[0])
+	}
+}
+
+func TestFallback_OnPanicAndBothFailing(t *testing.T) {
+	c := dshot.New()
+	token := dshot.NewToken[*Service]("svc")
+
+	c.Register(
+		dshot.BindAutoFactory(token, func() *Service {
+			panic("boom")
+		}, c).Fallback(func() (*Service, error) {
+			return nil, errors.New("fallback failed too")
+		}),
+	)
+
+	if _, err := dshot.GetE(token, c); err == nil {
+		t.Fatal("Expected error when primary and fallback fail")
+	}
+	if len(c.Degradations()) != 0 {
+		t.Error("Failed fallbacks should not be recorded as degradations")
+	}
+}
@@ -0,0 +1,101 @@
+package dshot
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+// DriftPolicy decides what happens when the value stored for a token is not of the token's
+// type, e.g. after an interceptor or a replacement substituted another implementation
+type DriftPolicy int
+
+const (
+	// DriftFail reports the drift: Get panics and GetE returns a *TypeMismatchError
+	DriftFail DriftPolicy = iota
+
+	// DriftConvert converts a value drifted between T and *T, as similar-type resolution
+	// does, logging a diagnostic warning. Other drifts fail as with DriftFail.
+	DriftConvert
+)
+
+// WithTypeDrift sets how token resolutions handle a stored value whose type drifted from
+// the token's type. Validate reports drifts the policy would not resolve. Scopes inherit
+// the policy.
+//
+// Example:
+//
+//	c := container.New(container.WithTypeDrift(container.DriftConvert))
+func WithTypeDrift(policy DriftPolicy) Option {
+	return func(c *Container) {
+		c.drift = policy
+	}
+}
+
+// typed is implemented by *Token[T], reporting T
+type typed interface {
+	valueType() reflect.Type
+}
+
+func (t *Token[T]) valueType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// convertDrift converts val, resolved from e, to want if the drift policy allows it
+func (e *entry) convertDrift(want reflect.Type, val any) (any, bool) {
+	if e.owner == nil || e.owner.drift != DriftConvert || val == nil {
+		return nil, false
+	}
+
+	got := reflect.TypeOf(val)
+	if !isSimilarKind(want, got) || checkLockCopy(got, want) != nil {
+		return nil, false
+	}
+
+	converted, ok := e.owner.convert(want, val)
+	if !ok {
+		return nil, false
+	}
+
+	if e.owner.warnEnabled() {
+		e.owner.warn(Warning{
+			Kind:    WarnTypeDrift,
+			Message: fmt.Sprintf("%s stores %s, converted to %s", e.label(), got, want),
+			Attrs: []slog.Attr{
+				slog.String("token", e.label()),
+				slog.String("storedType", got.String()),
+				slog.String("tokenType", want.String()),
+			},
+		})
+	}
+	return converted, true
+}
+
+// isSimilarKind reports whether a and b differ only by one level of pointer
+func isSimilarKind(a, b reflect.Type) bool {
+	return a.Kind() == reflect.Ptr && a.Elem() == b || b.Kind() == reflect.Ptr && b.Elem() == a
+}
+
+// drift returns the mismatch between the token type of e and the type it stores, or nil
+func (e *entry) drift() *TypeMismatchError {
+	tok, ok := e.token.(typed)
+	if !ok {
+		return nil
+	}
+	want := tok.valueType()
+
+	// Not locking e.mu, which a factory calling Validate may hold
+	got := e.depType
+	if built := e.builtType.Load(); built != nil {
+		got = *built
+	}
+
+	if got == nil || got.AssignableTo(want) {
+		return nil
+	}
+	if e.owner != nil && e.owner.drift == DriftConvert && isSimilarKind(want, got) && checkLockCopy(got, want) == nil {
+		return nil
+	}
+
+	return &TypeMismatchError{Want: want, Got: got, Source: e.label(), Site: e.registrationSite()}
+}
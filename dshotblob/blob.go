@@ -0,0 +1,160 @@
+// Package dshotblob registers blob-store clients for named buckets wired by dshot.
+//
+// The package does not depend on a storage SDK: the application supplies Config.Open,
+// adapting its S3, GCS or Azure client to Store for one bucket. Business code depends on
+// Store, resolved by bucket name through Bucket or a `dshot:"name=..."` field tag. With a
+// single bucket configured, Store also resolves by type.
+package dshotblob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Store is a blob store bound to one bucket
+type Store interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Put(ctx context.Context, key string, body io.Reader) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Pinger is implemented by stores able to check that their bucket is reachable,
+// e.g. through a HeadBucket request
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// BucketConfig describes a named bucket
+type BucketConfig struct {
+	Name     string // Name the store is registered under, see Bucket
+	Bucket   string // Bucket name in the storage service
+	Region   string
+	Endpoint string            // Custom endpoint, e.g. for MinIO or LocalStack
+	Options  map[string]string // Provider-specific settings
+}
+
+// Config configures the blob-store module
+type Config struct {
+	Buckets []BucketConfig
+
+	// Open creates the store of a bucket. It is called once per bucket, on first use.
+	// Stores implementing io.Closer are closed with the container.
+	Open func(ctx context.Context, bucket BucketConfig) (Store, error)
+
+	// CheckOnStart makes Start fail unless every bucket is reachable, see Stores.Check
+	CheckOnStart bool
+}
+
+// buckets caches the token of each bucket name, so every Bucket call returns the same one
+var buckets sync.Map // map[string]*dshot.Token[Store]
+
+// Bucket returns the token the store of the named bucket is registered under. Its name,
+// "blob.<name>", can also be used in field tags.
+//
+// Example:
+//
+//	avatars := container.Get(dshotblob.Bucket("avatars"), c)
+//
+//	type ProfileService struct {
+//	    Avatars dshotblob.Store `dshot:"name=blob.avatars"`
+//	}
+func Bucket(name string) *dshot.Token[Store] {
+	if token, ok := buckets.Load(name); ok {
+		return token.(*dshot.Token[Store])
+	}
+	token, _ := buckets.LoadOrStore(name, dshot.NewToken[Store]("blob."+name))
+	return token.(*dshot.Token[Store])
+}
+
+// Module registers a Store per configured bucket and a *Stores giving access to all of
+// them.
+//
+// Example:
+//
+//	c.RegisterModule("blob", dshotblob.Module(dshotblob.Config{
+//	    Buckets: cfg.Buckets,
+//	    Open: func(ctx context.Context, b dshotblob.BucketConfig) (dshotblob.Store, error) {
+//	        return newS3Store(ctx, b) // Adapts an s3.Client to dshotblob.Store
+//	    },
+//	    CheckOnStart: true,
+//	}))
+func Module(cfg Config) dshot.Module {
+	if cfg.Open == nil {
+		panic("dshotblob.Module: Config.Open is required")
+	}
+
+	names := make([]string, len(cfg.Buckets))
+	for i, b := range cfg.Buckets {
+		names[i] = b.Name
+	}
+
+	return func(c *dshot.Container) {
+		for _, b := range cfg.Buckets {
+			c.Register(dshot.BindAutoFactoryErr(Bucket(b.Name), func(ctx context.Context) (Store, error) {
+				store, err := cfg.Open(ctx, b)
+				if err != nil {
+					return nil, fmt.Errorf("dshotblob: open bucket %s: %w", b.Name, err)
+				}
+				return store, nil
+			}, c))
+		}
+
+		dshot.ProvideAutoFactory(func(root *dshot.Container) *Stores {
+			return &Stores{root: root, names: names}
+		}, c)
+
+		// Appended now rather than by the factory, so the check runs even if nothing
+		// resolves *Stores before Start
+		if cfg.CheckOnStart {
+			dshot.Invoke(func(lc *dshot.LifecycleHooks) {
+				lc.Append(dshot.Hook{Name: "dshotblob", OnStart: func(ctx context.Context) error {
+					s, err := dshot.ResolveE[*Stores](c)
+					if err != nil {
+						return err
+					}
+					return s.Check(ctx)
+				}})
+			}, c)
+		}
+	}
+}
+
+// Stores gives access to the stores of the configured buckets
+type Stores struct {
+	root  *dshot.Container
+	names []string
+}
+
+// Names returns the configured bucket names
+func (s *Stores) Names() []string {
+	return s.names
+}
+
+// Bucket returns the store of the named bucket
+func (s *Stores) Bucket(name string) (Store, error) {
+	return dshot.GetE(Bucket(name), s.root)
+}
+
+// Check opens every bucket and pings the stores implementing Pinger, returning the
+// failures joined
+func (s *Stores) Check(ctx context.Context) error {
+	var errs []error
+	for _, name := range s.names {
+		store, err := s.Bucket(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if p, ok := store.(Pinger); ok {
+			if err := p.Ping(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("dshotblob: bucket %s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
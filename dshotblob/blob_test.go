@@ -0,0 +1,115 @@
+package dshotblob_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshotblob"
+)
+
+type memStore struct {
+	bucket  string
+	objects map[string][]byte
+	down    bool
+	closed  bool
+}
+
+func (m *memStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.objects[key])), nil
+}
+
+func (m *memStore) Put(_ context.Context, key string, body io.Reader) error {
+	data, err := io.ReadAll(body)
+	m.objects[key] = data
+	return err
+}
+
+func (m *memStore) Delete(_ context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memStore) Ping(context.Context) error {
+	if m.down {
+		return errors.New("unreachable")
+	}
+	return nil
+}
+
+func (m *memStore) Close() error {
+	m.closed = true
+	return nil
+}
+
+type profileService struct {
+	Avatars dshotblob.Store `dshot:"name=blob.avatars"`
+	Uploads dshotblob.Store `dshot:"name=blob.uploads"`
+}
+
+func TestModule(t *testing.T) {
+	opened := map[string]*memStore{}
+
+	c := dshot.New()
+	c.RegisterModule("blob", dshotblob.Module(dshotblob.Config{
+		Buckets: []dshotblob.BucketConfig{
+			{Name: "avatars", Bucket: "prod-avatars"},
+			{Name: "uploads", Bucket: "prod-uploads"},
+		},
+		Open: func(_ context.Context, b dshotblob.BucketConfig) (dshotblob.Store, error) {
+			s := &memStore{bucket: b.Bucket, objects: map[string][]byte{}}
+			opened[b.Name] = s
+			return s, nil
+		},
+	}))
+
+	var svc profileService
+	dshot.Inject(&svc, c)
+
+	if err := svc.Avatars.Put(context.Background(), "u1.png", strings.NewReader("png")); err != nil {
+		t.Fatalf("Unexpected put error: %v", err)
+	}
+	if opened["avatars"].bucket != "prod-avatars" || string(opened["avatars"].objects["u1.png"]) != "png" {
+		t.Errorf("Expected the named field bound to the avatars bucket")
+	}
+	if svc.Uploads != dshotblob.Store(opened["uploads"]) {
+		t.Errorf("Expected the named field bound to the uploads bucket")
+	}
+	if dshot.Get(dshotblob.Bucket("avatars"), c) != svc.Avatars {
+		t.Errorf("Expected Bucket to return the registered token")
+	}
+
+	stores := dshot.MustResolve[*dshotblob.Stores](c)
+	opened["uploads"].down = true
+	if err := stores.Check(context.Background()); err == nil || !strings.Contains(err.Error(), "bucket uploads: unreachable") {
+		t.Errorf("Expected a failed health check for uploads, got %v", err)
+	}
+
+	if err := c.Close(context.Background()); err != nil || !opened["avatars"].closed || !opened["uploads"].closed {
+		t.Errorf("Expected stores closed with the container, got %v", err)
+	}
+}
+
+func TestModule_SingleBucketResolvesByType(t *testing.T) {
+	c := dshot.New()
+	c.RegisterModule("blob", dshotblob.Module(dshotblob.Config{
+		Buckets: []dshotblob.BucketConfig{{Name: "uploads"}},
+		Open: func(context.Context, dshotblob.BucketConfig) (dshotblob.Store, error) {
+			return nil, errors.New("access denied")
+		},
+		CheckOnStart: true,
+	}))
+
+	if _, err := dshot.ResolveE[dshotblob.Store](c); err == nil || !strings.Contains(err.Error(), "open bucket uploads: access denied") {
+		t.Errorf("Expected the open error resolving Store by type, got %v", err)
+	}
+
+	// Nothing resolved *Stores: the check is registered by the module itself
+	if err := c.Start(context.Background()); err == nil {
+		t.Error("Expected Start to fail the health check")
+	}
+}
@@ -0,0 +1,113 @@
+// Package dshotcodec resolves encoders and decoders by content type from codecs
+// registered in the container.
+//
+// Codecs are registrations declaring the content type they handle as a capability.
+// *Codecs looks them up by the Content-Type or Accept value of a request, tolerating
+// parameters and structured syntax suffixes, and caches what it resolved, so transports
+// need no switch over content types.
+package dshotcodec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Capability is the capability holding the content type a codec handles
+const Capability = "content-type"
+
+// Codec encodes and decodes values in one content type
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// Register returns a registration of codec for contentType.
+//
+// Example:
+//
+//	c.Register(dshotcodec.Register("application/msgpack", msgpackCodec{}))
+func Register(contentType string, codec Codec) dshot.Registration[Codec] {
+	token := dshot.NewToken[Codec]("dshotcodec." + contentType)
+	return dshot.Bind(token, codec).WithCapability(Capability, contentType)
+}
+
+// Module registers *Codecs along with JSON and XML codecs from the standard library.
+// Codecs registered for the same content type before the module take precedence.
+//
+// Example:
+//
+//	c.RegisterModule("codecs", dshotcodec.Module())
+//	codec, err := container.MustResolve[*dshotcodec.Codecs](c).Lookup(r.Header.Get("Content-Type"))
+func Module() dshot.Module {
+	return func(c *dshot.Container) {
+		c.Register(
+			Register("application/json", jsonCodec{}),
+			Register("application/xml", xmlCodec{}),
+		)
+
+		dshot.ProvideAutoFactory(func(root *dshot.Container) *Codecs {
+			return &Codecs{registry: dshot.NewRegistry[string, Codec](dshot.ByCapability(Capability), root)}
+		}, c)
+	}
+}
+
+// Codecs looks up codecs by content type
+type Codecs struct {
+	registry *dshot.Registry[string, Codec]
+	cache    sync.Map // map[string]Codec, by media type without parameters
+}
+
+// Lookup returns the codec for contentType, e.g. "application/json; charset=utf-8".
+// Parameters are ignored, and a type with a structured syntax suffix such as
+// "application/problem+json" falls back to the codec of "application/json".
+// Misses are not cached, so client-controlled values cannot grow the cache.
+func (c *Codecs) Lookup(contentType string) (Codec, error) {
+	// Keyed by media type, so parameters such as multipart boundaries share one entry
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("dshotcodec: %w", err)
+	}
+	if codec, ok := c.cache.Load(mediaType); ok {
+		return codec.(Codec), nil
+	}
+
+	codec, ok := c.registry.Lookup(mediaType)
+	if !ok {
+		if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+			codec, ok = c.registry.Lookup(mediaType[:strings.IndexByte(mediaType, '/')+1] + mediaType[i+1:])
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("dshotcodec: no codec for %s", mediaType)
+	}
+
+	c.cache.Store(mediaType, codec)
+	return codec, nil
+}
+
+// ContentTypes returns the content types with a registered codec, in registration order
+func (c *Codecs) ContentTypes() []string {
+	return c.registry.Keys()
+}
+
+// Reload picks up codecs registered since the registry was built and clears the cache
+func (c *Codecs) Reload() {
+	c.registry.Reload()
+	c.cache.Clear()
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type xmlCodec struct{}
+
+func (xmlCodec) Marshal(v any) ([]byte, error)      { return xml.Marshal(v) }
+func (xmlCodec) Unmarshal(data []byte, v any) error { return xml.Unmarshal(data, v) }
@@ -0,0 +1,56 @@
+package dshotcodec_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshotcodec"
+)
+
+type csvCodec struct{}
+
+func (csvCodec) Marshal(v any) ([]byte, error) {
+	return []byte(strings.Join(v.([]string), ",")), nil
+}
+
+func (csvCodec) Unmarshal(data []byte, v any) error {
+	*v.(*[]string) = strings.Split(string(data), ",")
+	return nil
+}
+
+func TestCodecs(t *testing.T) {
+	c := dshot.New()
+	c.Register(dshotcodec.Register("text/csv", csvCodec{}))
+	c.RegisterModule("codecs", dshotcodec.Module())
+
+	codecs := dshot.MustResolve[*dshotcodec.Codecs](c)
+	if want := []string{"text/csv", "application/json", "application/xml"}; !slices.Equal(codecs.ContentTypes(), want) {
+		t.Errorf("Expected content types %v, got %v", want, codecs.ContentTypes())
+	}
+
+	csv, err := codecs.Lookup("text/csv; charset=utf-8")
+	if err != nil {
+		t.Fatalf("Unexpected lookup error: %v", err)
+	}
+	if data, _ := csv.Marshal([]string{"a", "b"}); string(data) != "a,b" {
+		t.Errorf("Expected the CSV codec, got %q", data)
+	}
+	if other, err := codecs.Lookup("Text/CSV; header=present"); err != nil || other != csv {
+		t.Errorf("Expected the same codec for other parameters, got %v, %v", other, err)
+	}
+
+	problem, err := codecs.Lookup("application/problem+json")
+	if err != nil {
+		t.Fatalf("Unexpected lookup error: %v", err)
+	}
+	var body struct{ Title string }
+	if err := problem.Unmarshal([]byte(`{"Title":"bad"}`), &body); err != nil || body.Title != "bad" {
+		t.Errorf("Expected the JSON codec for a +json type, got %v, %v", body, err)
+	}
+
+	if _, err := codecs.Lookup("application/octet-stream"); err == nil {
+		t.Error("Expected an error for an unregistered content type")
+	}
+}
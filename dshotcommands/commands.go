@@ -0,0 +1,147 @@
+// Package dshotcommands routes commands to auto-wired handlers registered in the container.
+//
+// Each command type has exactly one handler registration. The *Bus built by the module
+// checks that every declared command has a handler and none has two, so wiring mistakes
+// fail startup instead of the first dispatch.
+package dshotcommands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Group is the group handler registrations belong to
+const Group = "command-handlers"
+
+// ErrNoHandler is returned by Send for a command type without a handler
+var ErrNoHandler = errors.New("no command handler")
+
+// Handler is a command handler registration, see Handle
+type Handler struct {
+	Command reflect.Type // Type of the commands handled
+
+	// Fn is a function taking the command, optionally a context.Context and any registered
+	// dependency, returning (R, error)
+	Fn any
+}
+
+// Handle returns a registration of the handler of commands of type C.
+//
+// Example:
+//
+//	c.Register(
+//	    dshotcommands.Handle[PlaceOrder](func(ctx context.Context, cmd PlaceOrder, repo *OrderRepo) (OrderID, error) {
+//	        return repo.Create(ctx, cmd.Items)
+//	    }),
+//	)
+func Handle[C any](fn any) dshot.Registration[Handler] {
+	commandType := reflect.TypeFor[C]()
+	if err := dshot.CheckFunc(fn, commandType); err != nil {
+		panic("dshotcommands.Handle: " + err.Error())
+	}
+	if fnType := reflect.TypeOf(fn); fnType.NumOut() != 2 || fnType.Out(1) != reflect.TypeFor[error]() {
+		panic("dshotcommands.Handle: handler must return (R, error)")
+	}
+
+	token := dshot.NewToken[Handler]("dshotcommands.handler." + commandType.String())
+	return dshot.Bind(token, Handler{Command: commandType, Fn: fn}).InGroup(Group)
+}
+
+// Command declares a command type whose handler must be registered, see Module
+type Command struct {
+	typ reflect.Type
+}
+
+// Expect declares the command type C
+func Expect[C any]() Command {
+	return Command{typ: reflect.TypeFor[C]()}
+}
+
+// Module registers a *Bus routing commands to their handlers. Resolving it fails if one
+// of the expected commands has no handler or a command has several.
+//
+// Example:
+//
+//	c.RegisterModule("commands", dshotcommands.Module(
+//	    dshotcommands.Expect[PlaceOrder](),
+//	    dshotcommands.Expect[CancelOrder](),
+//	))
+//	app := container.NewApp(container.UsingContainer(c), container.WithInvoke(func(*dshotcommands.Bus) {}))
+func Module(expected ...Command) dshot.Module {
+	return func(c *dshot.Container) {
+		dshot.ProvideAutoFactoryErr(func(root *dshot.Container) (*Bus, error) {
+			handlers, err := dshot.ResolveGroupE[Handler](Group, root)
+			if err != nil {
+				return nil, err
+			}
+			return newBus(handlers, expected)
+		}, c)
+	}
+}
+
+// Bus routes commands to their handlers
+type Bus struct {
+	handlers map[reflect.Type]Handler
+}
+
+func newBus(handlers []Handler, expected []Command) (*Bus, error) {
+	b := &Bus{handlers: make(map[reflect.Type]Handler, len(handlers))}
+
+	var errs []error
+	for _, h := range handlers {
+		if _, exists := b.handlers[h.Command]; exists {
+			errs = append(errs, fmt.Errorf("several handlers for command %s", h.Command))
+			continue
+		}
+		b.handlers[h.Command] = h
+	}
+
+	var missing []string
+	for _, cmd := range expected {
+		if _, ok := b.handlers[cmd.typ]; !ok {
+			missing = append(missing, cmd.typ.String())
+		}
+	}
+	if len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("%w for %s", ErrNoHandler, strings.Join(missing, ", ")))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("dshotcommands: %w", err)
+	}
+	return b, nil
+}
+
+// Send runs the handler of cmd, resolving its dependencies from the container in ctx,
+// and returns its result.
+//
+// Example:
+//
+//	id, err := dshotcommands.Send[OrderID](ctx, bus, PlaceOrder{Items: items})
+func Send[R, C any](ctx context.Context, b *Bus, cmd C) (R, error) {
+	var zero R
+
+	h, ok := b.handlers[reflect.TypeFor[C]()]
+	if !ok {
+		return zero, fmt.Errorf("dshotcommands: %w for %s", ErrNoHandler, reflect.TypeFor[C]())
+	}
+
+	results, err := dshot.InvokeWith(ctx, h.Fn, cmd)
+	if err != nil {
+		return zero, fmt.Errorf("dshotcommands: %s handler: %w", h.Command, err)
+	}
+	if results[1] != nil {
+		return zero, results[1].(error)
+	}
+
+	res, ok := results[0].(R)
+	if !ok && results[0] != nil {
+		return zero, fmt.Errorf("dshotcommands: handler of %s returns %T, not %s", h.Command, results[0], reflect.TypeFor[R]())
+	}
+	return res, nil
+}
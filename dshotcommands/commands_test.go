@@ -0,0 +1,70 @@
+package dshotcommands_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshotcommands"
+)
+
+type placeOrder struct {
+	Items int
+}
+
+type cancelOrder struct {
+	ID string
+}
+
+type orderRepo struct {
+	next int
+}
+
+func TestBus(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&orderRepo{})
+	c.RegisterModule("commands", dshotcommands.Module(dshotcommands.Expect[placeOrder]()))
+	c.Register(
+		dshotcommands.Handle[placeOrder](func(ctx context.Context, cmd placeOrder, repo *orderRepo) (string, error) {
+			if cmd.Items == 0 {
+				return "", errors.New("empty order")
+			}
+			repo.next++
+			return "order-1", nil
+		}),
+	)
+
+	bus := dshot.MustResolve[*dshotcommands.Bus](c)
+	ctx := dshot.WithContainer(context.Background(), c)
+
+	id, err := dshotcommands.Send[string](ctx, bus, placeOrder{Items: 2})
+	if err != nil || id != "order-1" {
+		t.Errorf("Expected the handler result, got %q, %v", id, err)
+	}
+	if _, err := dshotcommands.Send[string](ctx, bus, placeOrder{}); err == nil || err.Error() != "empty order" {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+	if _, err := dshotcommands.Send[string](ctx, bus, cancelOrder{}); !errors.Is(err, dshotcommands.ErrNoHandler) {
+		t.Errorf("Expected ErrNoHandler, got %v", err)
+	}
+}
+
+func TestBus_WiringErrorsFailBuild(t *testing.T) {
+	c := dshot.New()
+	c.RegisterModule("commands", dshotcommands.Module(
+		dshotcommands.Expect[placeOrder](),
+		dshotcommands.Expect[cancelOrder](),
+	))
+	c.Register(
+		dshotcommands.Handle[placeOrder](func(placeOrder) (string, error) { return "a", nil }),
+		dshotcommands.Handle[placeOrder](func(placeOrder) (string, error) { return "b", nil }),
+	)
+
+	_, err := dshot.ResolveE[*dshotcommands.Bus](c)
+	if !errors.Is(err, dshotcommands.ErrNoHandler) || !strings.Contains(err.Error(), "cancelOrder") ||
+		!strings.Contains(err.Error(), "several handlers for command dshotcommands_test.placeOrder") {
+		t.Errorf("Expected missing and duplicate handlers reported, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+// Package dshotevents dispatches in-process events to handlers registered in the container.
+//
+// Handlers are registrations grouped per event type. Dispatch resolves them from the
+// container in the context, typically the request scope, and calls each with the event
+// and its other parameters injected.
+package dshotevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Handler is an event handler registration, see On
+type Handler struct {
+	Event reflect.Type // Type of the events handled
+
+	// Fn is a function taking the event, optionally a context.Context and any registered
+	// dependency, returning an error or nothing
+	Fn any
+}
+
+// handlers numbers On registrations, giving each a unique token name
+var handlers atomic.Int64
+
+// Group returns the group of the handlers of events of type E
+func Group[E any]() string {
+	return "dshotevents:" + reflect.TypeFor[E]().String()
+}
+
+// On returns a registration of a handler for events of type E. Handlers of an event run
+// in registration order.
+//
+// Example:
+//
+//	c.Register(
+//	    dshotevents.On[OrderPlaced](func(ctx context.Context, evt OrderPlaced, mail *Mailer) error {
+//	        return mail.SendConfirmation(ctx, evt.OrderID)
+//	    }),
+//	)
+func On[E any](fn any) dshot.Registration[Handler] {
+	eventType := reflect.TypeFor[E]()
+	if err := dshot.CheckHandler(fn, eventType); err != nil {
+		panic("dshotevents.On: " + err.Error())
+	}
+
+	token := dshot.NewToken[Handler](fmt.Sprintf("dshotevents.handler.%s.%d", eventType, handlers.Add(1)))
+	return dshot.Bind(token, Handler{Event: eventType, Fn: fn}).InGroup(Group[E]())
+}
+
+// Dispatch calls the handlers of evt registered in the container in ctx, or its parents.
+// Every handler runs even if another fails; their errors are joined.
+//
+// Example:
+//
+//	if err := dshotevents.Dispatch(r.Context(), OrderPlaced{OrderID: id}); err != nil {
+//	    log.Error("order placed handlers failed", "err", err)
+//	}
+func Dispatch[E any](ctx context.Context, evt E) error {
+	hs, err := dshot.ResolveGroupE[Handler](Group[E](), dshot.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, h := range hs {
+		if err := dshot.CallHandler(ctx, h.Fn, evt); err != nil {
+			errs = append(errs, fmt.Errorf("dshotevents: %s handler: %w", h.Event, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
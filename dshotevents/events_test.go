@@ -0,0 +1,60 @@
+package dshotevents_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshotevents"
+)
+
+type orderPlaced struct {
+	OrderID string
+}
+
+type userSignedUp struct{}
+
+type requestLog struct {
+	lines []string
+}
+
+func TestDispatch(t *testing.T) {
+	c := dshot.New()
+	dshot.ProvideAutoScoped(func() *requestLog { return &requestLog{} }, c)
+	c.Register(
+		dshotevents.On[orderPlaced](func(evt orderPlaced, log *requestLog) {
+			log.lines = append(log.lines, "invoice "+evt.OrderID)
+		}),
+		dshotevents.On[orderPlaced](func(ctx context.Context, log *requestLog, evt orderPlaced) error {
+			log.lines = append(log.lines, "mail "+evt.OrderID)
+			return errors.New("mailer down")
+		}),
+		dshotevents.On[orderPlaced](func(evt orderPlaced, log *requestLog) {
+			log.lines = append(log.lines, "ship "+evt.OrderID)
+		}),
+		dshotevents.On[userSignedUp](func(userSignedUp) {
+			t.Error("Expected handlers of other events not to run")
+		}),
+	)
+
+	scope := dshot.NewScoped(c, "request")
+	err := dshotevents.Dispatch(dshot.WithContainer(context.Background(), scope), orderPlaced{OrderID: "42"})
+	if err == nil || !strings.Contains(err.Error(), "mailer down") {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+
+	log := dshot.MustResolve[*requestLog](scope)
+	if want := []string{"invoice 42", "mail 42", "ship 42"}; !slices.Equal(log.lines, want) {
+		t.Errorf("Expected handlers run in order with the request scope, got %v", log.lines)
+	}
+}
+
+func TestDispatch_NoHandlers(t *testing.T) {
+	c := dshot.New()
+	if err := dshotevents.Dispatch(dshot.WithContainer(context.Background(), c), userSignedUp{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+// Package dshotkafka runs a Kafka consumer group whose topic handlers are wired by dshot.
+//
+// The package does not depend on a Kafka library: the application registers a Client
+// adapting the library it uses (franz-go, sarama, confluent-kafka-go, ...). The module
+// drives the client from the container's lifecycle hooks, handles every message in its
+// own scope, retries failed messages and leaves the group cleanly on shutdown.
+package dshotkafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Message is a record consumed from Kafka
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   map[string][]byte
+	Time      time.Time
+}
+
+// Client is the consumer group client driven by the module
+type Client interface {
+	// Subscribe joins the consumer group for topics
+	Subscribe(ctx context.Context, topics []string) error
+	// Poll blocks until messages are available or ctx is done
+	Poll(ctx context.Context) ([]*Message, error)
+	// Commit marks messages as processed
+	Commit(ctx context.Context, msgs []*Message) error
+	// Close leaves the consumer group, letting the remaining members rebalance
+	Close() error
+}
+
+// Route binds a topic to a handler. Routes are resolved as a group, so any number of
+// them can be registered independently, see Handle.
+type Route struct {
+	Topic string
+
+	// Handler is a function returning an error (or nothing) whose parameters are resolved
+	// from the message scope: context.Context, *Message and any registered dependency
+	Handler any
+}
+
+// routes numbers route registrations, giving each a unique token name
+var routes atomic.Int64
+
+// Handle registers a handler for topic in c.
+//
+// Example:
+//
+//	dshotkafka.Handle(c, "orders", func(ctx context.Context, msg *dshotkafka.Message, svc *OrderService) error {
+//	    return svc.Process(ctx, msg.Value)
+//	})
+func Handle(c *dshot.Container, topic string, handler any) {
+	if err := dshot.CheckHandler(handler); err != nil {
+		panic("dshotkafka.Handle: " + err.Error())
+	}
+
+	name := fmt.Sprintf("dshotkafka.route.%s.%d", topic, routes.Add(1))
+	c.Register(dshot.Bind(dshot.NewToken[Route](name), Route{Topic: topic, Handler: handler}))
+}
+
+// Config configures the consumer module
+type Config struct {
+	Retries int           // Additional attempts for a failing message
+	Backoff time.Duration // Delay before the first retry, doubled after each attempt
+
+	// OnError is called when a message fails every attempt, e.g. to dead-letter it.
+	// The message is committed afterwards so the partition keeps moving. Messages whose
+	// handling is cancelled on shutdown are neither reported nor committed.
+	OnError func(ctx context.Context, msg *Message, err error)
+
+	// OnCommitError is called when committing handled messages fails. Commit failures
+	// during shutdown are also returned by the stop hook.
+	OnCommitError func(ctx context.Context, msgs []*Message, err error)
+
+	// PollBackoff is the delay after a failed poll (default: one second)
+	PollBackoff time.Duration
+}
+
+// Module registers a *Consumer running every registered Route. The Client must be
+// registered in the container.
+//
+// Example:
+//
+//	c.Provide(newFranzAdapter(brokers, "billing")) // Implements dshotkafka.Client
+//	c.RegisterModule("kafka", dshotkafka.Module(dshotkafka.Config{Retries: 3, Backoff: time.Second}))
+//	app := container.NewApp(container.UsingContainer(c), container.WithInvoke(func(*dshotkafka.Consumer) {}))
+func Module(cfg Config) dshot.Module {
+	if cfg.PollBackoff == 0 {
+		cfg.PollBackoff = time.Second
+	}
+
+	return func(c *dshot.Container) {
+		dshot.ProvideAutoFactory(func(lc *dshot.LifecycleHooks, root *dshot.Container, client Client, routes []Route) *Consumer {
+			consumer := &Consumer{cfg: cfg, root: root, client: client, routes: make(map[string][]any)}
+			for _, r := range routes {
+				consumer.routes[r.Topic] = append(consumer.routes[r.Topic], r.Handler)
+			}
+
+			lc.Append(dshot.Hook{
+				Name:    "dshotkafka",
+				OnStart: consumer.start,
+				OnStop:  consumer.stop,
+			})
+
+			return consumer
+		}, c)
+	}
+}
+
+// Consumer runs the consumer group. It is started and stopped by the container's
+// lifecycle hooks.
+type Consumer struct {
+	cfg    Config
+	root   *dshot.Container
+	client Client
+	routes map[string][]any
+
+	cancelPoll     context.CancelFunc
+	cancelHandlers context.CancelFunc
+	done           chan struct{}
+	stopErr        error // Commit failure after polling stopped, read once done is closed
+}
+
+func (c *Consumer) start(ctx context.Context) error {
+	topics := make([]string, 0, len(c.routes))
+	for topic := range c.routes {
+		topics = append(topics, topic)
+	}
+
+	if err := c.client.Subscribe(ctx, topics); err != nil {
+		return fmt.Errorf("dshotkafka: subscribe: %w", err)
+	}
+
+	pollCtx, cancelPoll := context.WithCancel(context.WithoutCancel(ctx))
+	handlerCtx, cancelHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	c.cancelPoll, c.cancelHandlers = cancelPoll, cancelHandlers
+	c.done = make(chan struct{})
+
+	go c.run(pollCtx, handlerCtx)
+
+	return nil
+}
+
+// stop stops polling, lets the messages being handled finish (or cancels them when ctx
+// is done), commits them and leaves the group
+func (c *Consumer) stop(ctx context.Context) error {
+	if c.done == nil {
+		return nil
+	}
+
+	c.cancelPoll()
+
+	var err error
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		c.cancelHandlers()
+		<-c.done
+		err = ctx.Err()
+	}
+	c.cancelHandlers()
+
+	return errors.Join(err, c.stopErr, c.client.Close())
+}
+
+func (c *Consumer) run(pollCtx, handlerCtx context.Context) {
+	defer close(c.done)
+
+	for {
+		msgs, err := c.client.Poll(pollCtx)
+		if pollCtx.Err() != nil {
+			return
+		}
+		if err != nil {
+			sleep(pollCtx, c.cfg.PollBackoff)
+			continue
+		}
+
+		// Only messages handled to completion are committed; the rest are redelivered
+		handled := 0
+		for _, msg := range msgs {
+			if !c.handle(handlerCtx, msg) {
+				break
+			}
+			handled++
+		}
+
+		if handled > 0 {
+			// Committed even when stopping, so handled messages are not redelivered
+			commitCtx := context.WithoutCancel(handlerCtx)
+			if err := c.client.Commit(commitCtx, msgs[:handled]); err != nil {
+				err = fmt.Errorf("dshotkafka: commit: %w", err)
+				if c.cfg.OnCommitError != nil {
+					c.cfg.OnCommitError(commitCtx, msgs[:handled], err)
+				}
+				if pollCtx.Err() != nil {
+					c.stopErr = err
+				}
+			}
+		}
+		if handled < len(msgs) {
+			return
+		}
+	}
+}
+
+// handle runs the handlers of msg's topic, retrying each until it succeeds or runs out
+// of attempts. It reports false if ctx was cancelled before msg was handled.
+func (c *Consumer) handle(ctx context.Context, msg *Message) bool {
+	for _, handler := range c.routes[msg.Topic] {
+		backoff := c.cfg.Backoff
+		var err error
+
+		for attempt := 0; attempt <= c.cfg.Retries; attempt++ {
+			if attempt > 0 && !sleep(ctx, backoff) {
+				break
+			}
+			backoff *= 2
+
+			if err = c.root.RunHandler(ctx, "kafka-message", handler, msg); err == nil {
+				break
+			}
+		}
+
+		if err != nil && ctx.Err() != nil {
+			return false
+		}
+		if err != nil && c.cfg.OnError != nil {
+			c.cfg.OnError(ctx, msg, err)
+		}
+	}
+
+	return true
+}
+
+// sleep waits for d, reporting false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
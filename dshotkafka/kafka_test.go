@@ -0,0 +1,196 @@
+package dshotkafka_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshotkafka"
+)
+
+// fakeClient delivers queued batches, then blocks until the poll context is done
+type fakeClient struct {
+	mu        sync.Mutex
+	topics    []string
+	batches   [][]*dshotkafka.Message
+	committed []int64
+	commitErr error
+	closed    bool
+}
+
+func (f *fakeClient) Subscribe(_ context.Context, topics []string) error {
+	f.topics = topics
+	return nil
+}
+
+func (f *fakeClient) Poll(ctx context.Context) ([]*dshotkafka.Message, error) {
+	f.mu.Lock()
+	if len(f.batches) > 0 {
+		batch := f.batches[0]
+		f.batches = f.batches[1:]
+		f.mu.Unlock()
+		return batch, nil
+	}
+	f.mu.Unlock()
+
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (f *fakeClient) Commit(_ context.Context, msgs []*dshotkafka.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.commitErr != nil {
+		return f.commitErr
+	}
+	for _, m := range msgs {
+		f.committed = append(f.committed, m.Offset)
+	}
+	return nil
+}
+
+func (f *fakeClient) Close() error {
+	f.closed = true
+	return nil
+}
+
+type orderService struct {
+	mu        sync.Mutex
+	processed []string
+	failures  int
+}
+
+func (s *orderService) Process(value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value == "flaky" && s.failures < 1 {
+		s.failures++
+		return errors.New("temporary")
+	}
+	if value == "poison" {
+		return errors.New("bad payload")
+	}
+	s.processed = append(s.processed, value)
+	return nil
+}
+
+type messageScope struct{ offset int64 }
+
+func TestConsumer(t *testing.T) {
+	client := &fakeClient{batches: [][]*dshotkafka.Message{{
+		{Topic: "orders", Offset: 1, Value: []byte("ok")},
+		{Topic: "orders", Offset: 2, Value: []byte("flaky")},
+		{Topic: "orders", Offset: 3, Value: []byte("poison")},
+	}}}
+	svc := &orderService{}
+	var deadLetters []int64
+	var scopes []int64
+
+	c := dshot.New()
+	c.Provide(client)
+	c.Provide(svc)
+	dshot.ProvideAutoScoped(func(msg *dshotkafka.Message) *messageScope {
+		return &messageScope{offset: msg.Offset}
+	}, c)
+	c.RegisterModule("kafka", dshotkafka.Module(dshotkafka.Config{
+		Retries: 1,
+		OnError: func(_ context.Context, msg *dshotkafka.Message, _ error) {
+			deadLetters = append(deadLetters, msg.Offset)
+		},
+	}))
+
+	dshotkafka.Handle(c, "orders", func(ctx context.Context, msg *dshotkafka.Message, s *orderService, scope *messageScope) error {
+		scopes = append(scopes, scope.offset)
+		return s.Process(string(msg.Value))
+	})
+
+	dshot.MustResolve[*dshotkafka.Consumer](c)
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		client.mu.Lock()
+		n := len(client.committed)
+		client.mu.Unlock()
+		if n == 3 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("Unexpected stop error: %v", err)
+	}
+
+	if !slices.Equal(client.topics, []string{"orders"}) {
+		t.Errorf("Expected subscription to orders, got %v", client.topics)
+	}
+	if !slices.Equal(svc.processed, []string{"ok", "flaky"}) {
+		t.Errorf("Expected ok and flaky (after a retry) processed, got %v", svc.processed)
+	}
+	if !slices.Equal(deadLetters, []int64{3}) {
+		t.Errorf("Expected poison message dead-lettered, got %v", deadLetters)
+	}
+	if !slices.Equal(scopes, []int64{1, 2, 2, 3, 3}) {
+		t.Errorf("Expected a fresh scope per attempt, got %v", scopes)
+	}
+	if !slices.Equal(client.committed, []int64{1, 2, 3}) || !client.closed {
+		t.Errorf("Expected all messages committed and the client closed, got %v", client.committed)
+	}
+}
+
+func TestConsumer_CancelledMessagesAreNotCommitted(t *testing.T) {
+	client := &fakeClient{
+		batches: [][]*dshotkafka.Message{{
+			{Topic: "orders", Offset: 1, Value: []byte("ok")},
+			{Topic: "orders", Offset: 2, Value: []byte("slow")},
+			{Topic: "orders", Offset: 3, Value: []byte("ok")},
+		}},
+		commitErr: errors.New("broker unavailable"),
+	}
+	var deadLetters, commitFailures int
+
+	c := dshot.New()
+	c.Provide(client)
+	c.RegisterModule("kafka", dshotkafka.Module(dshotkafka.Config{
+		OnError: func(context.Context, *dshotkafka.Message, error) { deadLetters++ },
+		OnCommitError: func(_ context.Context, msgs []*dshotkafka.Message, _ error) {
+			if len(msgs) != 1 || msgs[0].Offset != 1 {
+				t.Errorf("Expected only the handled message committed, got %d messages", len(msgs))
+			}
+			commitFailures++
+		},
+	}))
+
+	started := make(chan struct{})
+	dshotkafka.Handle(c, "orders", func(ctx context.Context, msg *dshotkafka.Message) error {
+		if string(msg.Value) == "slow" {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	dshot.MustResolve[*dshotkafka.Consumer](c)
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := c.Stop(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, client.commitErr) {
+		t.Errorf("Expected the stop timeout and the commit failure, got %v", err)
+	}
+	if deadLetters != 0 || commitFailures != 1 {
+		t.Errorf("Expected no dead letters and one commit failure, got %d and %d", deadLetters, commitFailures)
+	}
+}
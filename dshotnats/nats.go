@@ -0,0 +1,189 @@
+// Package dshotnats runs NATS (or AMQP) subscriptions whose handlers are wired by dshot.
+//
+// The package does not depend on a messaging library: the application registers a Conn
+// adapting the one it uses, e.g. a *nats.Conn through QueueSubscribe, or an AMQP channel
+// consuming one queue per subject. Subscriptions are declared as registrations; the
+// module subscribes them on Start, handles every message in its own scope and drains
+// them on Stop.
+package dshotnats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Msg is a message delivered to a subscription
+type Msg struct {
+	Subject string
+	Reply   string
+	Data    []byte
+	Header  map[string][]string
+}
+
+// Conn is the messaging connection driven by the module
+type Conn interface {
+	// QueueSubscribe calls handle for each message on subject. Subscribers sharing a
+	// non-empty queue group split the messages between them.
+	QueueSubscribe(subject, queue string, handle func(*Msg)) (Drainer, error)
+}
+
+// Drainer stops a subscription once the messages already delivered have been handled
+type Drainer interface {
+	Drain() error
+}
+
+// Subscription declares a handler for a subject, see Subscribe
+type Subscription struct {
+	Subject string
+	Queue   string // Queue group, empty for a plain subscription
+
+	// Handler is a function returning an error (or nothing) whose parameters are resolved
+	// from the message scope: context.Context, *Msg and any registered dependency
+	Handler any
+}
+
+// subscriptions numbers Subscribe registrations, giving each a unique token name
+var subscriptions atomic.Int64
+
+// Subscribe returns a registration declaring a subscription.
+//
+// Example:
+//
+//	c.Register(
+//	    dshotnats.Subscribe("orders.created", "billing", func(ctx context.Context, msg *dshotnats.Msg, svc *Billing) error {
+//	        return svc.Invoice(ctx, msg.Data)
+//	    }),
+//	)
+func Subscribe(subject, queue string, handler any) dshot.Registration[Subscription] {
+	if err := dshot.CheckHandler(handler); err != nil {
+		panic("dshotnats.Subscribe: " + err.Error())
+	}
+
+	token := dshot.NewToken[Subscription](fmt.Sprintf("dshotnats.subscription.%s.%d", subject, subscriptions.Add(1)))
+	return dshot.Bind(token, Subscription{Subject: subject, Queue: queue, Handler: handler})
+}
+
+// Config configures the subscriber module
+type Config struct {
+	// OnError is called when a handler fails or panics
+	OnError func(ctx context.Context, msg *Msg, err error)
+}
+
+// Module registers a *Subscriber running every registered Subscription. The Conn must be
+// registered in the container.
+//
+// Example:
+//
+//	c.Provide(natsAdapter{nc}) // Implements dshotnats.Conn
+//	c.RegisterModule("nats", dshotnats.Module(dshotnats.Config{OnError: logFailure}))
+//	app := container.NewApp(container.UsingContainer(c), container.WithInvoke(func(*dshotnats.Subscriber) {}))
+func Module(cfg Config) dshot.Module {
+	return func(c *dshot.Container) {
+		dshot.ProvideAutoFactory(func(lc *dshot.LifecycleHooks, root *dshot.Container, conn Conn, subs []Subscription) *Subscriber {
+			s := &Subscriber{cfg: cfg, root: root, conn: conn, subs: subs}
+
+			lc.Append(dshot.Hook{
+				Name:    "dshotnats",
+				OnStart: s.start,
+				OnStop:  s.stop,
+			})
+
+			return s
+		}, c)
+	}
+}
+
+// Subscriber runs the subscriptions. It is started and stopped by the container's
+// lifecycle hooks.
+type Subscriber struct {
+	cfg  Config
+	root *dshot.Container
+	conn Conn
+	subs []Subscription
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	drainers []Drainer
+
+	mu       sync.Mutex // Orders inFlight.Add before the wait in stop
+	closing  bool       // Set by stop once drained, messages delivered later are dropped
+	inFlight sync.WaitGroup
+}
+
+func (s *Subscriber) start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	s.mu.Lock()
+	s.closing = false
+	s.mu.Unlock()
+
+	for _, sub := range s.subs {
+		d, err := s.conn.QueueSubscribe(sub.Subject, sub.Queue, func(msg *Msg) {
+			s.mu.Lock()
+			if s.closing {
+				s.mu.Unlock()
+				return
+			}
+			s.inFlight.Add(1)
+			s.mu.Unlock()
+
+			defer s.inFlight.Done()
+			s.handle(msg, sub.Handler)
+		})
+		if err != nil {
+			return errors.Join(fmt.Errorf("dshotnats: subscribe %s: %w", sub.Subject, err), s.stop(ctx))
+		}
+		s.drainers = append(s.drainers, d)
+	}
+
+	return nil
+}
+
+// stop drains the subscriptions and waits for the messages being handled, cancelling
+// their context when ctx is done
+func (s *Subscriber) stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, d := range s.drainers {
+		if err := d.Drain(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	s.drainers = nil
+
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+		errs = append(errs, ctx.Err())
+	}
+	s.cancel()
+
+	return errors.Join(errs...)
+}
+
+// handle runs handler in a scope created for the message
+func (s *Subscriber) handle(msg *Msg, handler any) {
+	err := s.root.RunHandler(s.ctx, "nats-message", handler, msg)
+	if err != nil && s.cfg.OnError != nil {
+		s.cfg.OnError(s.ctx, msg, err)
+	}
+}
@@ -0,0 +1,113 @@
+package dshotnats_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshotnats"
+)
+
+type fakeConn struct {
+	mu       sync.Mutex
+	handlers map[string]func(*dshotnats.Msg)
+	queues   []string
+	drained  int
+}
+
+func (f *fakeConn) QueueSubscribe(subject, queue string, handle func(*dshotnats.Msg)) (dshotnats.Drainer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.handlers == nil {
+		f.handlers = make(map[string]func(*dshotnats.Msg))
+	}
+	f.handlers[subject] = handle
+	f.queues = append(f.queues, queue)
+	return f, nil
+}
+
+func (f *fakeConn) Drain() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drained++
+	return nil
+}
+
+func (f *fakeConn) publish(subject string, data string) {
+	f.mu.Lock()
+	handle := f.handlers[subject]
+	f.mu.Unlock()
+	handle(&dshotnats.Msg{Subject: subject, Data: []byte(data)})
+}
+
+type billing struct {
+	invoices []string
+}
+
+type requestID struct{ subject string }
+
+func TestSubscriber(t *testing.T) {
+	conn := &fakeConn{}
+	svc := &billing{}
+	var failures []string
+	var closed []string
+
+	c := dshot.New()
+	c.Provide(conn)
+	c.Provide(svc)
+	dshot.ProvideAutoScoped(func(msg *dshotnats.Msg) *requestID {
+		return &requestID{subject: msg.Subject}
+	}, c)
+	c.RegisterModule("nats", dshotnats.Module(dshotnats.Config{
+		OnError: func(_ context.Context, msg *dshotnats.Msg, err error) {
+			failures = append(failures, string(msg.Data)+": "+err.Error())
+		},
+	}))
+	c.Register(
+		dshotnats.Subscribe("orders.created", "billing", func(ctx context.Context, msg *dshotnats.Msg, b *billing, id *requestID) error {
+			dshot.FromContext(ctx).OnScopeClose(func(context.Context) error {
+				closed = append(closed, id.subject)
+				return nil
+			})
+			if string(msg.Data) == "bad" {
+				return errors.New("rejected")
+			}
+			b.invoices = append(b.invoices, string(msg.Data))
+			return nil
+		}),
+		dshotnats.Subscribe("orders.audit", "", func(msg *dshotnats.Msg) {
+			panic("audit down")
+		}),
+	)
+
+	dshot.MustResolve[*dshotnats.Subscriber](c)
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+
+	conn.publish("orders.created", "order-1")
+	conn.publish("orders.created", "bad")
+	conn.publish("orders.audit", "order-1")
+
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("Unexpected stop error: %v", err)
+	}
+
+	if !slices.Equal(conn.queues, []string{"billing", ""}) || conn.drained != 2 {
+		t.Errorf("Expected both subscriptions made and drained, got %v, %d", conn.queues, conn.drained)
+	}
+	conn.publish("orders.created", "late")
+	if !slices.Equal(svc.invoices, []string{"order-1"}) {
+		t.Errorf("Expected one invoice and messages after stop dropped, got %v", svc.invoices)
+	}
+	if !slices.Equal(closed, []string{"orders.created", "orders.created"}) {
+		t.Errorf("Expected a scope disposed per message, got %v", closed)
+	}
+	want := []string{"bad: rejected", "order-1: handler panicked: audit down"}
+	if !slices.Equal(failures, want) {
+		t.Errorf("Expected failures %v, got %v", want, failures)
+	}
+}
@@ -0,0 +1,183 @@
+// Package dshotnotify binds a Notifier to the email or SMS provider selected by
+// configuration, typically from the deployment profile or a command-line flag.
+//
+// Only the selected provider is registered, so the dependencies of the others (an SES
+// client in development, say) need not exist. The binding is a token registration that
+// can be wrapped with dshot.Decorate, e.g. to add logging or rate limiting; the module
+// itself decorates it to redirect messages in non-production profiles.
+package dshotnotify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/smtp"
+	"strings"
+	"sync"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Message is a notification to send
+type Message struct {
+	To      []string // Email addresses or phone numbers, depending on the provider
+	Subject string   // Ignored by SMS providers
+	Body    string
+}
+
+// Notifier sends notifications
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Token is the registration of the Notifier, resolvable by type as Notifier
+var Token = dshot.NewToken[Notifier]("dshotnotify.notifier")
+
+// Provider names
+const (
+	Console = "console" // Writes messages to Config.Console
+	SMTP    = "smtp"    // Sends email through Config.SMTP
+	SES     = "ses"     // Sends email through the registered SESClient
+	SMS     = "sms"     // Sends text messages through the registered SMSClient
+)
+
+// SESClient sends email through Amazon SES; adapt an sesv2.Client to it
+type SESClient interface {
+	SendEmail(ctx context.Context, from string, to []string, subject, body string) error
+}
+
+// SMSClient sends text messages, e.g. through SNS or Twilio
+type SMSClient interface {
+	SendSMS(ctx context.Context, to, body string) error
+}
+
+// SMTPConfig configures the SMTP provider
+type SMTPConfig struct {
+	Addr string // host:port
+	Auth smtp.Auth
+}
+
+// Config configures the notification module
+type Config struct {
+	Provider string // One of Console, SMTP, SES and SMS
+	From     string // Sender address for email providers
+
+	Console io.Writer // Output of the console provider
+	SMTP    SMTPConfig
+
+	// RedirectTo, if set, replaces the recipients of every message, so staging
+	// environments never notify real users
+	RedirectTo []string
+}
+
+// Module registers the Notifier of the configured provider.
+//
+// Example:
+//
+//	provider := flag.String("notifier", dshotnotify.Console, "console, smtp, ses or sms")
+//	flag.Parse()
+//
+//	c.Provide(sesAdapter{client}) // Implements dshotnotify.SESClient, only needed for ses
+//	c.RegisterModule("notify", dshotnotify.Module(dshotnotify.Config{
+//	    Provider: *provider,
+//	    From:     "no-reply@example.com",
+//	    Console:  os.Stdout,
+//	}))
+//
+//	n := container.MustResolve[dshotnotify.Notifier](c)
+func Module(cfg Config) dshot.Module {
+	return func(c *dshot.Container) {
+		switch cfg.Provider {
+		case Console:
+			if cfg.Console == nil {
+				panic("dshotnotify.Module: Config.Console is required by the console provider")
+			}
+			c.Register(dshot.BindAutoFactory(Token, func() Notifier {
+				return &consoleNotifier{w: cfg.Console}
+			}, c))
+		case SMTP:
+			c.Register(dshot.BindAutoFactory(Token, func() Notifier {
+				return &smtpNotifier{cfg: cfg.SMTP, from: cfg.From}
+			}, c))
+		case SES:
+			c.Register(dshot.BindAutoFactory(Token, func(client SESClient) Notifier {
+				return &sesNotifier{client: client, from: cfg.From}
+			}, c))
+		case SMS:
+			c.Register(dshot.BindAutoFactory(Token, func(client SMSClient) Notifier {
+				return &smsNotifier{client: client}
+			}, c))
+		default:
+			panic(fmt.Sprintf("dshotnotify.Module: unknown provider %q", cfg.Provider))
+		}
+
+		if len(cfg.RedirectTo) > 0 {
+			dshot.Decorate(Token, func(inner Notifier) Notifier {
+				return &redirectNotifier{inner: inner, to: cfg.RedirectTo}
+			}, c)
+		}
+	}
+}
+
+type consoleNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (n *consoleNotifier) Notify(_ context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.w, "To: %s\nSubject: %s\n\n%s\n", strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+	return err
+}
+
+type smtpNotifier struct {
+	cfg  SMTPConfig
+	from string
+}
+
+func (n *smtpNotifier) Notify(_ context.Context, msg Message) error {
+	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		n.from, strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+	if err := smtp.SendMail(n.cfg.Addr, n.cfg.Auth, n.from, msg.To, []byte(body)); err != nil {
+		return fmt.Errorf("dshotnotify: smtp: %w", err)
+	}
+	return nil
+}
+
+type sesNotifier struct {
+	client SESClient
+	from   string
+}
+
+func (n *sesNotifier) Notify(ctx context.Context, msg Message) error {
+	if err := n.client.SendEmail(ctx, n.from, msg.To, msg.Subject, msg.Body); err != nil {
+		return fmt.Errorf("dshotnotify: ses: %w", err)
+	}
+	return nil
+}
+
+type smsNotifier struct {
+	client SMSClient
+}
+
+func (n *smsNotifier) Notify(ctx context.Context, msg Message) error {
+	for _, to := range msg.To {
+		if err := n.client.SendSMS(ctx, to, msg.Body); err != nil {
+			return fmt.Errorf("dshotnotify: sms to %s: %w", to, err)
+		}
+	}
+	return nil
+}
+
+// redirectNotifier replaces the recipients of the messages it forwards
+type redirectNotifier struct {
+	inner Notifier
+	to    []string
+}
+
+func (n *redirectNotifier) Notify(ctx context.Context, msg Message) error {
+	msg.To = n.to
+	return n.inner.Notify(ctx, msg)
+}
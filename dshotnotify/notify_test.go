@@ -0,0 +1,91 @@
+package dshotnotify_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshotnotify"
+)
+
+type fakeSES struct {
+	sent []string
+}
+
+func (f *fakeSES) SendEmail(_ context.Context, from string, to []string, subject, _ string) error {
+	f.sent = append(f.sent, from+" -> "+strings.Join(to, ",")+": "+subject)
+	return nil
+}
+
+type countingNotifier struct {
+	inner dshotnotify.Notifier
+	count *int
+}
+
+func (n countingNotifier) Notify(ctx context.Context, msg dshotnotify.Message) error {
+	*n.count++
+	return n.inner.Notify(ctx, msg)
+}
+
+func TestModule_ConsoleWithDecorators(t *testing.T) {
+	var out strings.Builder
+	var count int
+
+	c := dshot.New()
+	c.RegisterModule("notify", dshotnotify.Module(dshotnotify.Config{
+		Provider:   dshotnotify.Console,
+		Console:    &out,
+		RedirectTo: []string{"qa@example.com"},
+	}))
+	dshot.Decorate(dshotnotify.Token, func(inner dshotnotify.Notifier) dshotnotify.Notifier {
+		return countingNotifier{inner: inner, count: &count}
+	}, c)
+
+	n := dshot.MustResolve[dshotnotify.Notifier](c)
+	if err := n.Notify(context.Background(), dshotnotify.Message{
+		To: []string{"user@example.com"}, Subject: "Welcome", Body: "Hello",
+	}); err != nil {
+		t.Fatalf("Unexpected notify error: %v", err)
+	}
+
+	want := "To: qa@example.com\nSubject: Welcome\n\nHello\n"
+	if out.String() != want || count != 1 {
+		t.Errorf("Expected redirected message counted once, got %q (%d)", out.String(), count)
+	}
+}
+
+func TestModule_SelectsProvider(t *testing.T) {
+	ses := &fakeSES{}
+
+	c := dshot.New()
+	c.Provide(dshotnotify.SESClient(ses))
+	c.RegisterModule("notify", dshotnotify.Module(dshotnotify.Config{
+		Provider: dshotnotify.SES,
+		From:     "no-reply@example.com",
+	}))
+
+	n := dshot.MustResolve[dshotnotify.Notifier](c)
+	if err := n.Notify(context.Background(), dshotnotify.Message{To: []string{"a@example.com"}, Subject: "Receipt"}); err != nil {
+		t.Fatalf("Unexpected notify error: %v", err)
+	}
+	if len(ses.sent) != 1 || ses.sent[0] != "no-reply@example.com -> a@example.com: Receipt" {
+		t.Errorf("Expected the message sent through SES, got %v", ses.sent)
+	}
+}
+
+func TestModule_OnlySelectedProviderNeedsDependencies(t *testing.T) {
+	c := dshot.New()
+	c.RegisterModule("notify", dshotnotify.Module(dshotnotify.Config{Provider: dshotnotify.SMS}))
+
+	if err := c.Validate(); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected the missing SMSClient reported, got %v", err)
+	}
+
+	c = dshot.New()
+	c.RegisterModule("notify", dshotnotify.Module(dshotnotify.Config{Provider: dshotnotify.SMTP}))
+	if err := c.Validate(); err != nil {
+		t.Errorf("Expected no SES or SMS client needed for smtp, got %v", err)
+	}
+}
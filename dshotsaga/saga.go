@@ -0,0 +1,155 @@
+// Package dshotsaga runs multi-step processes (sagas) whose steps are wired by dshot.
+//
+// A saga is a list of steps, each with an action and an optional compensation. Every
+// execution gets its own scope: steps resolve their dependencies from it, so Scoped
+// registrations hold the state of one execution and are shared by its steps. When a step
+// fails, the compensations of the completed steps run in reverse order.
+package dshotsaga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Step is a step of a saga. Action and Compensate are functions returning an error or
+// nothing, whose parameters are resolved from the execution scope: context.Context,
+// *Execution, the seeds passed to Run and any registered dependency.
+type Step struct {
+	Name       string
+	Action     any
+	Compensate any // Undoes Action once a later step failed, nil if nothing to undo
+}
+
+// Saga is a declared multi-step process
+type Saga struct {
+	name  string
+	steps []Step
+}
+
+// Name returns the name the saga was declared with
+func (s *Saga) Name() string {
+	return s.name
+}
+
+// Execution identifies a running saga; it is registered in the execution scope
+type Execution struct {
+	Saga string
+	Step string // Step running, or compensated
+}
+
+// StepError reports the step of a saga that failed and the compensations that failed
+// in turn
+type StepError struct {
+	Saga           string
+	Step           string
+	Err            error
+	CompensateErrs []error
+}
+
+func (e *StepError) Error() string {
+	msg := fmt.Sprintf("saga %s: step %s: %v", e.Saga, e.Step, e.Err)
+	if len(e.CompensateErrs) > 0 {
+		msg += fmt.Sprintf(" (compensation failed: %v)", errors.Join(e.CompensateErrs...))
+	}
+	return msg
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// sagas caches the token of each saga name, so every Token call returns the same one
+var sagas sync.Map // map[string]*dshot.Token[*Saga]
+
+// Token returns the token the saga of the given name is registered under
+func Token(name string) *dshot.Token[*Saga] {
+	if token, ok := sagas.Load(name); ok {
+		return token.(*dshot.Token[*Saga])
+	}
+	token, _ := sagas.LoadOrStore(name, dshot.NewToken[*Saga]("dshotsaga."+name))
+	return token.(*dshot.Token[*Saga])
+}
+
+// Define returns a registration declaring a saga.
+//
+// Example:
+//
+//	c.Register(dshotsaga.Define("checkout",
+//	    dshotsaga.Step{Name: "reserve", Action: (*Inventory).Reserve, Compensate: (*Inventory).Release},
+//	    dshotsaga.Step{Name: "charge", Action: func(ctx context.Context, order *Order, p *Payments) error {
+//	        return p.Charge(ctx, order)
+//	    }},
+//	))
+func Define(name string, steps ...Step) dshot.Registration[*Saga] {
+	for _, step := range steps {
+		checkFunc(name, step.Name, step.Action, false)
+		checkFunc(name, step.Name, step.Compensate, true)
+	}
+	return dshot.Bind(Token(name), &Saga{name: name, steps: steps})
+}
+
+// Run executes the saga declared under name in a scope of c, in which the seeds are
+// provided, typically the input of the process. It returns a *StepError if a step failed.
+//
+// Example:
+//
+//	err := dshotsaga.Run(ctx, c, "checkout", &Order{ID: id})
+func Run(ctx context.Context, c *dshot.Container, name string, seeds ...any) error {
+	s, err := dshot.GetE(Token(name), c)
+	if err != nil {
+		return err
+	}
+	return s.Run(ctx, c, seeds...)
+}
+
+// Run executes the saga in a scope of c, see the Run function
+func (s *Saga) Run(ctx context.Context, c *dshot.Container, seeds ...any) (err error) {
+	scope := dshot.NewScoped(c, "saga:"+s.name)
+	exec := &Execution{Saga: s.name}
+	scope.Provide(exec)
+	for _, seed := range seeds {
+		scope.Provide(seed)
+	}
+	defer func() {
+		err = errors.Join(err, scope.Dispose(context.WithoutCancel(ctx)))
+	}()
+
+	ctx = dshot.WithContainer(ctx, scope)
+
+	for i, step := range s.steps {
+		exec.Step = step.Name
+		stepErr := dshot.CallHandler(ctx, step.Action)
+		if stepErr == nil {
+			continue
+		}
+
+		failure := &StepError{Saga: s.name, Step: step.Name, Err: stepErr}
+		for j := i - 1; j >= 0; j-- {
+			done := s.steps[j]
+			if done.Compensate == nil {
+				continue
+			}
+			exec.Step = done.Name
+			if err := dshot.CallHandler(ctx, done.Compensate); err != nil {
+				failure.CompensateErrs = append(failure.CompensateErrs, fmt.Errorf("%s: %w", done.Name, err))
+			}
+		}
+		return failure
+	}
+
+	return nil
+}
+
+// checkFunc panics unless fn is a step function, or nil when allowed
+func checkFunc(saga, step string, fn any, optional bool) {
+	if fn == nil && optional {
+		return
+	}
+	if err := dshot.CheckHandler(fn); err != nil {
+		panic(fmt.Sprintf("dshotsaga.Define: %s step %s: %v", saga, step, err))
+	}
+}
@@ -0,0 +1,74 @@
+package dshotsaga_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshotsaga"
+)
+
+type order struct {
+	ID     string
+	Amount int
+}
+
+// reservation is per-execution state shared by the steps of one run
+type reservation struct {
+	id string
+}
+
+type journal struct {
+	entries []string
+}
+
+func TestRun(t *testing.T) {
+	c := dshot.New()
+	j := &journal{}
+	c.Provide(j)
+	dshot.ProvideAutoScoped(func(o *order) *reservation { return &reservation{id: "r-" + o.ID} }, c)
+
+	c.Register(dshotsaga.Define("checkout",
+		dshotsaga.Step{
+			Name: "reserve",
+			Action: func(r *reservation, j *journal) {
+				j.entries = append(j.entries, "reserve "+r.id)
+			},
+			Compensate: func(exec *dshotsaga.Execution, r *reservation, j *journal) {
+				j.entries = append(j.entries, "release "+r.id+" in "+exec.Step)
+			},
+		},
+		dshotsaga.Step{
+			Name: "notify",
+			Action: func(j *journal) {
+				j.entries = append(j.entries, "notify")
+			},
+		},
+		dshotsaga.Step{
+			Name: "charge",
+			Action: func(ctx context.Context, o *order) error {
+				if o.Amount > 100 {
+					return errors.New("card declined")
+				}
+				return nil
+			},
+		},
+	))
+
+	if err := dshotsaga.Run(context.Background(), c, "checkout", &order{ID: "1", Amount: 10}); err != nil {
+		t.Fatalf("Unexpected saga error: %v", err)
+	}
+
+	err := dshotsaga.Run(context.Background(), c, "checkout", &order{ID: "2", Amount: 500})
+	var stepErr *dshotsaga.StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "charge" || stepErr.Err.Error() != "card declined" {
+		t.Fatalf("Expected the charge step to fail, got %v", err)
+	}
+
+	want := []string{"reserve r-1", "notify", "reserve r-2", "notify", "release r-2 in reserve"}
+	if !slices.Equal(j.entries, want) {
+		t.Errorf("Expected %v, got %v", want, j.entries)
+	}
+}
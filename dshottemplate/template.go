@@ -0,0 +1,116 @@
+// Package dshottemplate assembles an html/template FuncMap from helpers contributed by
+// feature modules through the container.
+//
+// Each feature registers a template.FuncMap in the Group group, either as is with Funcs
+// or from an auto-wired factory whose helpers close over injected dependencies. The
+// module merges them when the container starts.
+package dshottemplate
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"sync"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Group is the group template helper registrations belong to
+const Group = "template-funcs"
+
+// Funcs returns a registration contributing helpers under the name of a feature.
+//
+// Example:
+//
+//	c.Register(dshottemplate.Funcs("money", template.FuncMap{
+//	    "cents": formatCents,
+//	}))
+//
+//	// Helpers needing dependencies come from a factory:
+//	c.Register(container.BindAutoFactory(urlsToken, func(r *Router) template.FuncMap {
+//	    return template.FuncMap{"url": r.URL}
+//	}).InGroup(dshottemplate.Group))
+func Funcs(feature string, funcs template.FuncMap) dshot.Registration[template.FuncMap] {
+	token := dshot.NewToken[template.FuncMap]("dshottemplate.funcs." + feature)
+	return dshot.Bind(token, funcs).InGroup(Group)
+}
+
+// Module registers a *FuncMap merging the helpers of the Group group on Start.
+//
+// Example:
+//
+//	c.RegisterModule("templates", dshottemplate.Module())
+//	container.ProvideAutoFactory(func(funcs *dshottemplate.FuncMap) (*template.Template, error) {
+//	    return funcs.New("pages").ParseFS(pagesFS, "*.html")
+//	}, c)
+func Module() dshot.Module {
+	return func(c *dshot.Container) {
+		dshot.ProvideAutoFactory(func(root *dshot.Container) *FuncMap {
+			return &FuncMap{root: root}
+		}, c)
+
+		// Appended now rather than by the factory, so duplicate helpers fail Start even if
+		// nothing resolved *FuncMap before
+		dshot.Invoke(func(lc *dshot.LifecycleHooks) {
+			lc.Append(dshot.Hook{
+				Name: "dshottemplate",
+				OnStart: func(context.Context) error {
+					f, err := dshot.ResolveE[*FuncMap](c)
+					if err != nil {
+						return err
+					}
+					_, err = f.assemble()
+					return err
+				},
+			})
+		}, c)
+	}
+}
+
+// FuncMap holds the merged template helpers
+type FuncMap struct {
+	root  *dshot.Container
+	once  sync.Once
+	funcs template.FuncMap
+	err   error
+}
+
+// Map returns the merged helpers. They are assembled on Start, or on first use if the
+// container has not been started; Map panics if two contributions define the same name.
+func (f *FuncMap) Map() template.FuncMap {
+	funcs, err := f.assemble()
+	if err != nil {
+		panic(err)
+	}
+	return funcs
+}
+
+// New returns a new template with the merged helpers
+func (f *FuncMap) New(name string) *template.Template {
+	return template.New(name).Funcs(f.Map())
+}
+
+// assemble merges the contributions of the group once
+func (f *FuncMap) assemble() (template.FuncMap, error) {
+	f.once.Do(func() {
+		contributions, err := dshot.ResolveGroupE[template.FuncMap](Group, f.root)
+		if err != nil {
+			f.err = fmt.Errorf("dshottemplate: %w", err)
+			return
+		}
+
+		funcs := make(template.FuncMap)
+		for _, contribution := range contributions {
+			for name, fn := range contribution {
+				if _, exists := funcs[name]; exists {
+					f.err = fmt.Errorf("dshottemplate: template function %q is contributed twice", name)
+					return
+				}
+				funcs[name] = fn
+			}
+		}
+		f.funcs = funcs
+	})
+
+	return f.funcs, f.err
+}
@@ -0,0 +1,56 @@
+package dshottemplate_test
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshottemplate"
+)
+
+type siteConfig struct {
+	BaseURL string
+}
+
+func TestModule(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&siteConfig{BaseURL: "https://example.com"})
+	c.RegisterModule("templates", dshottemplate.Module())
+	c.Register(
+		dshottemplate.Funcs("text", template.FuncMap{"upper": strings.ToUpper}),
+		dshot.BindAutoFactory(dshot.NewToken[template.FuncMap]("urls"), func(cfg *siteConfig) template.FuncMap {
+			return template.FuncMap{"url": func(path string) string { return cfg.BaseURL + path }}
+		}, c).InGroup(dshottemplate.Group),
+		dshot.Bind(dshot.NewToken[template.FuncMap]("unrelated"), template.FuncMap{"upper": strings.ToLower}),
+	)
+
+	funcs := dshot.MustResolve[*dshottemplate.FuncMap](c)
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+
+	tmpl := template.Must(funcs.New("page").Parse(`{{upper "home"}} {{url "/about"}}`))
+	var out strings.Builder
+	if err := tmpl.Execute(&out, nil); err != nil {
+		t.Fatalf("Unexpected execute error: %v", err)
+	}
+	if out.String() != "HOME https://example.com/about" {
+		t.Errorf("Expected helpers from both contributions, got %q", out.String())
+	}
+}
+
+func TestModule_DuplicateNameFailsStart(t *testing.T) {
+	c := dshot.New()
+	c.RegisterModule("templates", dshottemplate.Module())
+	c.Register(
+		dshottemplate.Funcs("a", template.FuncMap{"upper": strings.ToUpper}),
+		dshottemplate.Funcs("b", template.FuncMap{"upper": strings.ToUpper}),
+	)
+
+	// Nothing resolved *FuncMap: the hook is registered by the module itself
+	if err := c.Start(context.Background()); err == nil || !strings.Contains(err.Error(), `"upper" is contributed twice`) {
+		t.Errorf("Expected a duplicate helper error, got %v", err)
+	}
+}
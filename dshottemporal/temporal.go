@@ -0,0 +1,162 @@
+// Package dshottemporal registers Temporal workflows and activities wired by dshot on a worker.
+//
+// The package does not depend on the Temporal SDK: the application registers a Worker
+// adapting its worker.Worker, registering functions under the given names through
+// RegisterWorkflowWithOptions and RegisterActivityWithOptions. Workflows and activities
+// are declared as registrations; every activity execution resolves its dependencies from
+// its own scope, which is disposed when the activity returns.
+package dshottemporal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Worker is the Temporal worker driven by the module
+type Worker interface {
+	RegisterWorkflow(name string, fn any)
+	RegisterActivity(name string, fn any)
+	Start() error
+	Stop()
+}
+
+// WorkflowDef declares a workflow, see Workflow
+type WorkflowDef struct {
+	Name string
+	Fn   any
+}
+
+// ActivityDef declares an activity, see Activity
+type ActivityDef struct {
+	Name string
+
+	// bind returns the activity function registered on the worker, running in scopes of r
+	bind func(r *Runner) any
+}
+
+// Workflow returns a registration declaring a workflow function. Workflows must be
+// deterministic, so they are registered as is and receive no dependencies.
+//
+// Example:
+//
+//	c.Register(dshottemporal.Workflow("OrderWorkflow", OrderWorkflow))
+func Workflow(name string, fn any) dshot.Registration[WorkflowDef] {
+	token := dshot.NewToken[WorkflowDef]("dshottemporal.workflow." + name)
+	return dshot.Bind(token, WorkflowDef{Name: name, Fn: fn})
+}
+
+// Activity returns a registration declaring an activity taking an input of type In.
+// Each execution resolves deps from a scope created for it, in which *Heartbeat is
+// registered: a struct Deps has its exported fields injected, any other type is resolved
+// as is. Scoped registrations built for the execution are disposed when it returns.
+//
+// Example:
+//
+//	type chargeDeps struct {
+//	    Payments *PaymentClient // Scoped, heartbeats while waiting for the provider
+//	}
+//
+//	c.Register(dshottemporal.Activity("Charge", func(ctx context.Context, order Order, deps chargeDeps) (Receipt, error) {
+//	    return deps.Payments.Charge(ctx, order)
+//	}))
+func Activity[In, Out, Deps any](name string, fn func(ctx context.Context, in In, deps Deps) (Out, error)) dshot.Registration[ActivityDef] {
+	token := dshot.NewToken[ActivityDef]("dshottemporal.activity." + name)
+	return dshot.Bind(token, ActivityDef{
+		Name: name,
+		bind: func(r *Runner) any {
+			return func(ctx context.Context, in In) (out Out, err error) {
+				scope := dshot.NewScoped(r.root, "temporal-activity")
+				scope.Provide(&Heartbeat{ctx: ctx, record: r.cfg.Heartbeat})
+				defer func() {
+					err = errors.Join(err, scope.Dispose(context.WithoutCancel(ctx)))
+				}()
+
+				ctx = dshot.WithContainer(ctx, scope)
+				var deps Deps
+				if _, err := dshot.InvokeCtxE(ctx, func(d Deps) { deps = d }); err != nil {
+					return out, fmt.Errorf("dshottemporal: activity %s: %w", name, err)
+				}
+
+				return fn(ctx, in, deps)
+			}
+		},
+	})
+}
+
+// Heartbeat records progress of the running activity, letting long-lived scoped
+// resources report liveness while they block
+type Heartbeat struct {
+	ctx    context.Context
+	record func(ctx context.Context, details ...any)
+}
+
+// Record sends a heartbeat with details. It does nothing without Config.Heartbeat.
+func (h *Heartbeat) Record(details ...any) {
+	if h.record != nil {
+		h.record(h.ctx, details...)
+	}
+}
+
+// Context returns the context of the running activity
+func (h *Heartbeat) Context() context.Context {
+	return h.ctx
+}
+
+// Config configures the worker module
+type Config struct {
+	// Heartbeat records an activity heartbeat, typically activity.RecordHeartbeat
+	Heartbeat func(ctx context.Context, details ...any)
+}
+
+// Module registers a *Runner registering every WorkflowDef and ActivityDef on the
+// Worker, which must be registered in the container. The worker is started and stopped
+// by the container's lifecycle hooks.
+//
+// Example:
+//
+//	c.Provide(workerAdapter{w}) // Implements dshottemporal.Worker
+//	c.RegisterModule("temporal", dshottemporal.Module(dshottemporal.Config{Heartbeat: activity.RecordHeartbeat}))
+//	app := container.NewApp(container.UsingContainer(c), container.WithInvoke(func(*dshottemporal.Runner) {}))
+func Module(cfg Config) dshot.Module {
+	return func(c *dshot.Container) {
+		dshot.ProvideAutoFactory(func(
+			lc *dshot.LifecycleHooks,
+			root *dshot.Container,
+			worker Worker,
+			workflows []WorkflowDef,
+			activities []ActivityDef,
+		) *Runner {
+			r := &Runner{cfg: cfg, root: root, worker: worker}
+
+			for _, wf := range workflows {
+				worker.RegisterWorkflow(wf.Name, wf.Fn)
+			}
+			for _, a := range activities {
+				worker.RegisterActivity(a.Name, a.bind(r))
+			}
+
+			lc.Append(dshot.Hook{
+				Name: "dshottemporal",
+				OnStart: func(context.Context) error {
+					return worker.Start()
+				},
+				OnStop: func(context.Context) error {
+					worker.Stop()
+					return nil
+				},
+			})
+
+			return r
+		}, c)
+	}
+}
+
+// Runner owns the worker the workflows and activities are registered on
+type Runner struct {
+	cfg    Config
+	root   *dshot.Container
+	worker Worker
+}
@@ -0,0 +1,94 @@
+package dshottemporal_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshottemporal"
+)
+
+type fakeWorker struct {
+	workflows  map[string]any
+	activities map[string]any
+	started    bool
+	stopped    bool
+}
+
+func (w *fakeWorker) RegisterWorkflow(name string, fn any) { w.workflows[name] = fn }
+func (w *fakeWorker) RegisterActivity(name string, fn any) { w.activities[name] = fn }
+func (w *fakeWorker) Start() error                         { w.started = true; return nil }
+func (w *fakeWorker) Stop()                                { w.stopped = true }
+
+type payments struct {
+	hb     *dshottemporal.Heartbeat
+	closed bool
+}
+
+func (p *payments) Close() error {
+	p.closed = true
+	return nil
+}
+
+type chargeDeps struct {
+	Payments *payments
+}
+
+func TestModule(t *testing.T) {
+	w := &fakeWorker{workflows: map[string]any{}, activities: map[string]any{}}
+	var beats []any
+	var built []*payments
+
+	c := dshot.New()
+	dshot.ProvideAutoFactory(func() dshottemporal.Worker { return w }, c)
+	dshot.ProvideAutoScoped(func(hb *dshottemporal.Heartbeat) *payments {
+		p := &payments{hb: hb}
+		built = append(built, p)
+		return p
+	}, c)
+	c.RegisterModule("temporal", dshottemporal.Module(dshottemporal.Config{
+		Heartbeat: func(_ context.Context, details ...any) { beats = append(beats, details...) },
+	}))
+	c.Register(
+		dshottemporal.Workflow("OrderWorkflow", func() error { return nil }),
+		dshottemporal.Activity("Charge", func(ctx context.Context, amount int, deps chargeDeps) (string, error) {
+			deps.Payments.hb.Record(amount)
+			if amount < 0 {
+				return "", errors.New("negative amount")
+			}
+			return "receipt", nil
+		}),
+	)
+
+	dshot.MustResolve[*dshottemporal.Runner](c)
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+
+	if _, ok := w.workflows["OrderWorkflow"]; !ok || !w.started {
+		t.Fatalf("Expected the workflow registered and the worker started")
+	}
+	charge, ok := w.activities["Charge"].(func(context.Context, int) (string, error))
+	if !ok {
+		t.Fatalf("Expected a typed activity function, got %T", w.activities["Charge"])
+	}
+
+	if receipt, err := charge(context.Background(), 10); err != nil || receipt != "receipt" {
+		t.Errorf("Expected a receipt, got %q, %v", receipt, err)
+	}
+	if _, err := charge(context.Background(), -1); err == nil {
+		t.Error("Expected the activity error")
+	}
+
+	if len(built) != 2 || built[0] == built[1] || !built[0].closed || !built[1].closed {
+		t.Errorf("Expected a disposed scope per activity execution, got %v", built)
+	}
+	if len(beats) != 2 || beats[0] != 10 || beats[1] != -1 {
+		t.Errorf("Expected heartbeats from scoped resources, got %v", beats)
+	}
+
+	if err := c.Stop(context.Background()); err != nil || !w.stopped {
+		t.Errorf("Expected the worker stopped, got %v", err)
+	}
+}
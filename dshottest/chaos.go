@@ -0,0 +1,128 @@
+// Package dshottest provides helpers for testing applications wired with dshot.
+package dshottest
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/overdevelop/dshot"
+)
+
+// ErrChaos is the default error returned by factories failed by Chaos
+var ErrChaos = errors.New("dshottest: injected failure")
+
+// ChaosOption configures Chaos
+type ChaosOption func(*chaos)
+
+type chaos struct {
+	match      func(dshot.RegistrationInfo) bool
+	failRate   float64
+	failErr    error
+	minLatency time.Duration
+	maxLatency time.Duration
+	seed       uint64
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// Only restricts chaos to registrations accepted by pred (default: all)
+func Only(pred func(dshot.RegistrationInfo) bool) ChaosOption {
+	return func(c *chaos) {
+		c.match = pred
+	}
+}
+
+// Tokens restricts chaos to the registrations with the given token names
+func Tokens(names ...string) ChaosOption {
+	return Only(func(info dshot.RegistrationInfo) bool {
+		for _, name := range names {
+			if info.Token == name {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FailRate makes factories fail with probability p (0 to 1)
+func FailRate(p float64) ChaosOption {
+	return func(c *chaos) {
+		c.failRate = p
+	}
+}
+
+// FailWith sets the error returned by failed factories (default ErrChaos)
+func FailWith(err error) ChaosOption {
+	return func(c *chaos) {
+		c.failErr = err
+	}
+}
+
+// Latency delays factories by a random duration between lo and hi
+func Latency(lo, hi time.Duration) ChaosOption {
+	return func(c *chaos) {
+		c.minLatency = lo
+		c.maxLatency = hi
+	}
+}
+
+// Seed makes the injected failures and latencies reproducible
+func Seed(seed uint64) ChaosOption {
+	return func(c *chaos) {
+		c.seed = seed
+	}
+}
+
+// Chaos injects failures and latency into the factories registered in c, to test
+// application behavior under partial wiring failures and slow cold starts.
+// Injected failures are returned as factory errors, so fallbacks and GetE/ResolveE
+// observe them like real ones.
+//
+// Example:
+//
+//	c := app.NewContainer()
+//	dshottest.Chaos(c,
+//	    dshottest.Tokens("redis-cache"),
+//	    dshottest.FailRate(0.5),
+//	    dshottest.Latency(10*time.Millisecond, 200*time.Millisecond),
+//	    dshottest.Seed(42),
+//	)
+func Chaos(c *dshot.Container, opts ...ChaosOption) {
+	ch := &chaos{
+		failErr: ErrChaos,
+		seed:    uint64(time.Now().UnixNano()),
+	}
+
+	for _, opt := range opts {
+		opt(ch)
+	}
+	ch.rnd = rand.New(rand.NewPCG(ch.seed, ch.seed))
+
+	c.AddInterceptor(ch.intercept)
+}
+
+func (c *chaos) intercept(info dshot.RegistrationInfo, next func() (any, error)) (any, error) {
+	if c.match != nil && !c.match(info) {
+		return next()
+	}
+
+	c.mu.Lock()
+	fail := c.failRate > 0 && c.rnd.Float64() < c.failRate
+	delay := c.minLatency
+	if span := c.maxLatency - c.minLatency; span > 0 {
+		delay += time.Duration(c.rnd.Int64N(int64(span)))
+	}
+	c.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if fail {
+		return nil, c.failErr
+	}
+
+	return next()
+}
@@ -0,0 +1,56 @@
+package dshottest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshottest"
+)
+
+type Cache struct {
+	Kind string
+}
+
+func TestChaos_FailsMatchingRegistrations(t *testing.T) {
+	c := dshot.New()
+	cacheToken := dshot.NewToken[*Cache]("cache")
+	otherToken := dshot.NewToken[*Cache]("other")
+
+	c.Register(
+		dshot.BindAutoFactory(cacheToken, func() *Cache { return &Cache{Kind: "redis"} }, c).
+			Fallback(func() *Cache { return &Cache{Kind: "memory"} }),
+		dshot.BindAutoFactory(otherToken, func() *Cache { return &Cache{Kind: "other"} }, c),
+	)
+
+	dshottest.Chaos(c, dshottest.Tokens("cache"), dshottest.FailRate(1))
+
+	if cache := dshot.Get(cacheToken, c); cache.Kind != "memory" {
+		t.Errorf("Expected fallback under chaos, got '%s'", cache.Kind)
+	}
+	if other := dshot.Get(otherToken, c); other.Kind != "other" {
+		t.Errorf("Unmatched registrations should be unaffected, got '%s'", other.Kind)
+	}
+}
+
+func TestChaos_FailWithAndLatency(t *testing.T) {
+	c := dshot.New()
+	errInjected := errors.New("injected")
+	c.ProvidePrototype(func() *Cache { return &Cache{} })
+
+	dshottest.Chaos(c,
+		dshottest.FailRate(1),
+		dshottest.FailWith(errInjected),
+		dshottest.Latency(5*time.Millisecond, 5*time.Millisecond),
+		dshottest.Seed(1),
+	)
+
+	start := time.Now()
+	if _, err := dshot.ResolveE[*Cache](c); !errors.Is(err, errInjected) {
+		t.Errorf("Expected injected error, got %v", err)
+	}
+	if time.Since(start) < 5*time.Millisecond {
+		t.Error("Expected injected latency")
+	}
+}
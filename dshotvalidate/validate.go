@@ -0,0 +1,169 @@
+// Package dshotvalidate assembles a struct validator from rules contributed by feature
+// modules through the container.
+//
+// Rules are FieldValidator registrations in the Group group. The *Validator built by the
+// module collects them when it is constructed and applies them to struct fields according
+// to their `validate:"rule,rule=param"` tags.
+package dshotvalidate
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/overdevelop/dshot"
+)
+
+// Group is the group rule registrations belong to
+const Group = "validators"
+
+// FieldValidator is a validation rule applied to tagged struct fields
+type FieldValidator interface {
+	// Name is the rule name used in validate tags
+	Name() string
+
+	// Validate checks the value of a field; param is the text after "=" in the tag, if any
+	Validate(value reflect.Value, param string) error
+}
+
+// Rule returns a registration contributing a rule.
+//
+// Example:
+//
+//	c.Register(dshotvalidate.Rule(SKURule{}))
+//
+//	// Rules needing dependencies come from a factory:
+//	c.Register(container.BindAutoFactory(uniqueEmailToken, NewUniqueEmailRule).InGroup(dshotvalidate.Group))
+func Rule(v FieldValidator) dshot.Registration[FieldValidator] {
+	token := dshot.NewToken[FieldValidator]("dshotvalidate.rule." + v.Name())
+	return dshot.Bind(token, v).InGroup(Group)
+}
+
+// Module registers a *Validator built from the rules of the Group group, along with the
+// built-in "required" rule.
+//
+// Example:
+//
+//	c.RegisterModule("validation", dshotvalidate.Module())
+//	v := container.MustResolve[*dshotvalidate.Validator](c)
+//	if err := v.Struct(req); err != nil {
+//	    return badRequest(err)
+//	}
+func Module() dshot.Module {
+	return func(c *dshot.Container) {
+		c.Register(Rule(required{}))
+
+		dshot.ProvideAutoFactoryErr(func(root *dshot.Container) (*Validator, error) {
+			rules, err := dshot.ResolveGroupE[FieldValidator](Group, root)
+			if err != nil {
+				return nil, err
+			}
+			return newValidator(rules)
+		}, c)
+	}
+}
+
+// FieldError is a rule violation of a field
+type FieldError struct {
+	Field string // Field path, e.g. "Address.Zip"
+	Rule  string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Field, e.Rule, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Validator validates structs with the collected rules
+type Validator struct {
+	rules map[string]FieldValidator
+}
+
+func newValidator(rules []FieldValidator) (*Validator, error) {
+	v := &Validator{rules: make(map[string]FieldValidator, len(rules))}
+	for _, rule := range rules {
+		if _, exists := v.rules[rule.Name()]; exists {
+			return nil, fmt.Errorf("dshotvalidate: rule %q is registered twice", rule.Name())
+		}
+		v.rules[rule.Name()] = rule
+	}
+	return v, nil
+}
+
+// Rules returns the names of the available rules, sorted
+func (v *Validator) Rules() []string {
+	return slices.Sorted(maps.Keys(v.rules))
+}
+
+// Struct validates the exported fields of s, a struct or a pointer to one, descending
+// into nested structs. Every violation is reported as a *FieldError joined into the
+// returned error.
+func (v *Validator) Struct(s any) error {
+	val := reflect.ValueOf(s)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return errors.New("dshotvalidate: nil struct")
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("dshotvalidate: %s is not a struct", val.Type())
+	}
+
+	var errs []error
+	v.validateStruct(val, "", &errs)
+	return errors.Join(errs...)
+}
+
+func (v *Validator) validateStruct(val reflect.Value, prefix string, errs *[]error) {
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path := prefix + field.Name
+		fieldVal := val.Field(i)
+
+		if tag, ok := field.Tag.Lookup("validate"); ok {
+			for opt := range strings.SplitSeq(tag, ",") {
+				name, param, _ := strings.Cut(strings.TrimSpace(opt), "=")
+				rule, ok := v.rules[name]
+				if !ok {
+					*errs = append(*errs, &FieldError{Field: path, Rule: name, Err: errors.New("unknown rule")})
+					continue
+				}
+				if err := rule.Validate(fieldVal, param); err != nil {
+					*errs = append(*errs, &FieldError{Field: path, Rule: name, Err: err})
+				}
+			}
+		}
+
+		nested := fieldVal
+		if nested.Kind() == reflect.Ptr && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct {
+			v.validateStruct(nested, path+".", errs)
+		}
+	}
+}
+
+// required rejects zero values
+type required struct{}
+
+func (required) Name() string { return "required" }
+
+func (required) Validate(value reflect.Value, _ string) error {
+	if value.IsZero() {
+		return errors.New("value is required")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package dshotvalidate_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+	"github.com/overdevelop/dshot/dshotvalidate"
+)
+
+type maxLen struct{}
+
+func (maxLen) Name() string { return "max" }
+
+func (maxLen) Validate(value reflect.Value, param string) error {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return err
+	}
+	if value.Len() > n {
+		return fmt.Errorf("longer than %d", n)
+	}
+	return nil
+}
+
+type skuCatalog struct {
+	known map[string]bool
+}
+
+type knownSKU struct {
+	catalog *skuCatalog
+}
+
+func (knownSKU) Name() string { return "sku" }
+
+func (r knownSKU) Validate(value reflect.Value, _ string) error {
+	if !r.catalog.known[value.String()] {
+		return errors.New("unknown SKU")
+	}
+	return nil
+}
+
+type address struct {
+	Zip string `validate:"required,max=5"`
+}
+
+type order struct {
+	SKU     string `validate:"required,sku"`
+	Note    string `validate:"max=10"`
+	Address *address
+}
+
+func TestValidator(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&skuCatalog{known: map[string]bool{"A-1": true}})
+	c.RegisterModule("validation", dshotvalidate.Module())
+	c.Register(
+		dshotvalidate.Rule(maxLen{}),
+		dshot.BindAutoFactory(dshot.NewToken[dshotvalidate.FieldValidator]("sku"), func(catalog *skuCatalog) dshotvalidate.FieldValidator {
+			return knownSKU{catalog: catalog}
+		}, c).InGroup(dshotvalidate.Group),
+	)
+
+	v := dshot.MustResolve[*dshotvalidate.Validator](c)
+	if want := []string{"max", "required", "sku"}; !slices.Equal(v.Rules(), want) {
+		t.Errorf("Expected rules %v, got %v", want, v.Rules())
+	}
+
+	if err := v.Struct(&order{SKU: "A-1", Address: &address{Zip: "12345"}}); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+
+	err := v.Struct(order{SKU: "B-2", Note: "far too long a note", Address: &address{}})
+	var fields []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fe *dshotvalidate.FieldError
+		if errors.As(e, &fe) {
+			fields = append(fields, fe.Field+":"+fe.Rule)
+		}
+	}
+	if want := []string{"SKU:sku", "Note:max", "Address.Zip:required"}; !slices.Equal(fields, want) {
+		t.Errorf("Expected violations %v, got %v", want, fields)
+	}
+}
+
+func TestValidator_DuplicateRuleFailsBuild(t *testing.T) {
+	c := dshot.New()
+	c.RegisterModule("validation", dshotvalidate.Module())
+	c.Register(
+		dshotvalidate.Rule(maxLen{}),
+		dshot.Bind(dshot.NewToken[dshotvalidate.FieldValidator]("other-max"), dshotvalidate.FieldValidator(maxLen{})).InGroup(dshotvalidate.Group),
+	)
+
+	if _, err := dshot.ResolveE[*dshotvalidate.Validator](c); err == nil {
+		t.Error("Expected a duplicate rule error")
+	}
+}
@@ -1,35 +1,257 @@
 package dshot
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type entry struct {
-	value     any
-	factory   func() any
-	depType   reflect.Type
-	lifecycle Lifecycle
-	once      sync.Once
-	mu        sync.Mutex
+	token        any
+	value        any
+	factory      func(r *resolution) (any, error)
+	fallback     func(r *resolution) (any, error) // Used when factory fails, see Registration.Fallback
+	depType      reflect.Type
+	lifecycle    Lifecycle
+	capabilities map[string]string
+	wiring       *wiring // Parameters of auto-wired factories, see Validate
+	owner        *Container
+	module       string // Module that registered the entry
+	registeredAt time.Time
+	closer       func(ctx context.Context, v any) error // Set by Registration.OnClose
+	ownCleanup   bool                                   // Factory returns its own cleanup function
+	aliases      []reflect.Type                         // Interface types it is also indexed under, see As
+	groups       []string                               // Named groups it belongs to, see InGroup
+	private      bool                                   // Resolvable only from its module, see Private
+	hidden       atomic.Bool                            // Private to its module, see isPrivate
+	unowned      atomic.Bool                            // Dropped a prototype cleanup, see trackCleanup
+	order        *int                                   // Position among multiple results, see WithOrder
+	override     bool                                   // Scope-local override, see OverrideToken
+	variants     *variantSet                            // Alternative implementations chosen per scope, see Variants
+	sitePCs      []uintptr                              // Call stack of the registration, see registrationSite
+	done         bool
+	buildTime    time.Duration                // Singleton construction duration, set once done
+	builtType    atomic.Pointer[reflect.Type] // Type of the built singleton, readable without mu
+	built        atomic.Pointer[any]          // The built singleton, read without mu or a chain
+	mu           sync.Mutex
 }
 
-func (e *entry) resolve() any {
+// resolve returns the entry value, running its factory if needed.
+// A failed singleton factory is not cached, so the next resolve retries it.
+func (e *entry) resolve(r *resolution) (any, error) {
+	if e.variants != nil {
+		return e.owner.resolveVariant(e, r)
+	}
 	if e.factory == nil {
-		return e.value
+		return e.value, nil
+	}
+
+	if err := e.awaitReady(r); err != nil {
+		return nil, err
+	}
+	if built := e.built.Load(); built != nil {
+		return *built, nil
+	}
+
+	if e.lifecycle == Scoped && e.owner != nil {
+		// Resolved without a requesting scope: the registering container is the scope
+		return e.owner.resolveScoped(e, r)
+	}
+
+	// Checked before locking, so a singleton depending on itself fails instead of deadlocking
+	inner, err := r.enter(e)
+	if err != nil {
+		return nil, err
 	}
 
 	if e.lifecycle == Prototype {
-		return e.factory()
+		val, err := e.build(inner)
+		if err != nil {
+			return nil, err
+		}
+		r.trackBuilt(e, val)
+		return val, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.done {
+		return e.value, nil
+	}
+
+	// Singletons are owned by the container, not by the resolving call
+	start := time.Now()
+	val, err := e.build(inner.untracked())
+	if err != nil {
+		return nil, err
+	}
+
+	e.value = val
+	e.buildTime = time.Since(start)
+	e.done = true
+	e.built.Store(&val)
+	if val != nil {
+		builtType := reflect.TypeOf(val)
+		e.builtType.Store(&builtType)
+	}
+
+	if e.owner != nil {
+		e.owner.trackDisposal(e, val)
+		e.owner.trackSnapshot(e, val)
+	}
+
+	return e.value, nil
+}
+
+// build runs the factory, then the post-construct hook of the container (see
+// WithPostConstruct)
+func (e *entry) build(r *resolution) (any, error) {
+	val, err := e.construct(r)
+	if err == nil {
+		err = e.guardBuilt(val)
+	}
+	if err != nil || e.owner == nil {
+		return val, err
+	}
+
+	if err := e.owner.postConstruct(r, val); err != nil {
+		return nil, fmt.Errorf("init %s: %w", e.label(), err)
+	}
+
+	return val, nil
+}
+
+// construct runs the factory, switching to the fallback factory if it fails or panics
+func (e *entry) construct(r *resolution) (any, error) {
+	factory := e.restoringFactory()
+	if e.owner != nil {
+		factory = e.owner.intercepted(e, factory)
+	}
+
+	if e.fallback == nil {
+		return factory(r)
+	}
+
+	val, err := recoverFactory(factory, r)
+	if err == nil {
+		return val, nil
+	}
+
+	fallback, fallbackErr := e.fallback(r)
+	if fallbackErr != nil {
+		return nil, errors.Join(err, fallbackErr)
+	}
+
+	if e.owner != nil {
+		e.owner.recordDegradation(e, err)
+	}
+
+	return fallback, nil
+}
+
+// recoverFactory calls factory, converting a panic into an error
+func recoverFactory(factory func(r *resolution) (any, error), r *resolution) (val any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			if pErr, ok := p.(error); ok {
+				err = pErr
+				return
+			}
+			err = fmt.Errorf("%v", p)
+		}
+	}()
+
+	return factory(r)
+}
+
+// invalidate drops the singleton cached by a removed or replaced entry
+func (e *entry) invalidate() {
+	if e.factory == nil {
+		return
 	}
 
-	e.once.Do(
-		func() {
-			e.mu.Lock()
-			defer e.mu.Unlock()
-			e.value = e.factory()
-		},
-	)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.done = false
+	e.value = nil
+	e.built.Store(nil)
+	e.builtType.Store(nil)
+}
 
-	return e.value
+// tryResolve resolves the entry, converting a factory panic into an error
+func (e *entry) tryResolve() (val any, err error) {
+	return e.tryResolveWith(nil)
+}
+
+// tryResolveWith is tryResolve with resolution state threaded through nested factories
+func (e *entry) tryResolveWith(r *resolution) (val any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rErr, ok := r.(error); ok {
+				err = rErr
+				return
+			}
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	return e.resolve(r)
+}
+
+// label names the entry in diagnostics: its type for type-based registrations, else its token
+func (e *entry) label() string {
+	if e.typeBased() {
+		return e.depType.String()
+	}
+	return tokenName(e.token)
+}
+
+// typeBased reports whether e was registered by type (Provide and auto factories)
+// rather than under a named token
+func (e *entry) typeBased() bool {
+	t, ok := e.token.(*tokenKey)
+	return ok && t.key == providedKey(e.depType)
+}
+
+// info returns the public description of the entry
+func (e *entry) info() RegistrationInfo {
+	return RegistrationInfo{
+		Token:        tokenName(e.token),
+		Type:         e.depType,
+		Lifecycle:    e.lifecycle,
+		Module:       e.module,
+		Scope:        e.owner.name,
+		Depth:        e.owner.depth,
+		RegisteredAt: e.registeredAt,
+		Capabilities: e.capabilities,
+		Groups:       e.groups,
+	}
+}
+
+// registrationSite returns file:line of the first caller outside this package when the
+// entry was registered, "unknown" if there is none, or "" if the site was not recorded
+// (see WithRegistrationSites)
+func (e *entry) registrationSite() string {
+	if len(e.sitePCs) == 0 {
+		return ""
+	}
+
+	frames := runtime.CallersFrames(e.sitePCs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" && !strings.HasPrefix(frame.Function, pkgPrefix) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
 }
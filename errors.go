@@ -0,0 +1,206 @@
+package dshot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when no registration matches a token or type
+	ErrNotFound = errors.New("dependency not found")
+
+	// ErrAmbiguous is returned when a type resolves to more than one registration
+	ErrAmbiguous = errors.New("multiple candidates found")
+
+	// ErrCircularDependency is returned when a factory depends on itself, directly or indirectly
+	ErrCircularDependency = errors.New("circular dependency")
+
+	// ErrSimilarType is returned with WithStrictTypes when a type is only registered as its
+	// pointer or value counterpart, e.g. *Config requested but Config registered
+	ErrSimilarType = errors.New("similar-type conversion disabled by strict types")
+)
+
+// ResolutionError reports a failed resolution together with the dependency path that led to it,
+// from the requested token or type down to the failing leaf.
+//
+// Example:
+//
+//	_, err := container.ResolveE[*Service]()
+//	// *app.Service -> param 0 (*app.Repository) -> field DB (*sql.DB): dependency not found
+//
+//	var re *container.ResolutionError
+//	if errors.As(err, &re) {
+//	    log.Printf("missing %s", re.Path[len(re.Path)-1])
+//	}
+type ResolutionError struct {
+	Path []string // Requested token or type, then each factory parameter or struct field on the way
+	Err  error    // Cause at the end of the path, e.g. ErrNotFound or a factory error
+}
+
+func (e *ResolutionError) Error() string {
+	return strings.Join(e.Path, " -> ") + ": " + e.Err.Error()
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// withStep prepends a step to the path of a resolution error, wrapping other errors in a new one
+func withStep(err error, step string) error {
+	if re, ok := err.(*ResolutionError); ok {
+		return &ResolutionError{Path: append([]string{step}, re.Path...), Err: re.Err}
+	}
+
+	return &ResolutionError{Path: []string{step}, Err: err}
+}
+
+// paramStep describes a function parameter in a resolution path
+func paramStep(i int, paramType reflect.Type) string {
+	return fmt.Sprintf("param %d (%s)", i, paramType)
+}
+
+// fieldStep describes a struct field in a resolution path
+func fieldStep(field reflect.StructField) string {
+	return fmt.Sprintf("field %s (%s)", field.Name, field.Type)
+}
+
+// tokenStep describes a token in a resolution path
+func tokenStep(token any) string {
+	return "token " + tokenName(token)
+}
+
+// GetE retrieves a value by token, returning an error instead of panicking.
+// The error wraps ErrNotFound if the token is not registered.
+//
+// Example:
+//
+//	val, err := c.GetE(dbToken)
+//	if errors.Is(err, container.ErrNotFound) {
+//	    // fall back
+//	}
+func (c *Container) GetE(token any) (any, error) {
+	if token == nil {
+		return nil, errors.New("cannot get with nil token")
+	}
+
+	e, ok, err := c.getEntry(token)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, c.tokenNotFound(token)
+	}
+
+	val, err := c.resolveEntry(e, nil)
+	if err != nil {
+		return nil, withStep(err, tokenStep(token))
+	}
+
+	return val, nil
+}
+
+// ResolveE resolves a dependency by type, returning an error instead of panicking.
+// The error wraps ErrNotFound or ErrAmbiguous when the type cannot be resolved to a single registration.
+// Failures are reported as a *ResolutionError carrying the dependency path.
+func (c *Container) ResolveE(targetType reflect.Type) (any, error) {
+	val, ok, err := c.resolveType(targetType, nil)
+	if err != nil {
+		return nil, withStep(err, targetType.String())
+	}
+	if !ok {
+		return nil, withStep(c.notFound(targetType), targetType.String())
+	}
+
+	return val, nil
+}
+
+// InjectE populates a struct's fields like Inject, returning an error instead of panicking.
+func (c *Container) InjectE(target any) error {
+	err := c.inject(target, nil)
+	if _, ok := err.(*ResolutionError); ok {
+		target, _ = unwrapInjectTarget(target)
+		return withStep(err, reflect.TypeOf(target).String())
+	}
+
+	return err
+}
+
+// InvokeE calls fn with parameters resolved from the container, returning an error instead of panicking.
+func (c *Container) InvokeE(fn any) ([]any, error) {
+	return c.invoke(fn, nil)
+}
+
+// GetE retrieves a value by token from the specified container (or global if nil),
+// returning an error instead of panicking.
+//
+// Example:
+//
+//	db, err := container.GetE(dbToken)
+//	if err != nil {
+//	    return err
+//	}
+func GetE[T any](token *Token[T], containers ...*Container) (T, error) {
+	return GetEVia(token, pickContainer(containers, "GetE"))
+}
+
+// ResolveE resolves a dependency by type, returning an error instead of panicking.
+//
+// Example:
+//
+//	db, err := container.ResolveE[*Database]()
+//	if errors.Is(err, container.ErrNotFound) {
+//	    // fall back
+//	}
+func ResolveE[T any](containers ...*Container) (T, error) {
+	return ResolveEVia[T](pickContainer(containers, "ResolveE"))
+}
+
+// InjectE populates a struct's fields from the specified container, returning an error instead of panicking.
+func InjectE(target any, containers ...*Container) error {
+	return pickContainer(containers, "InjectE").InjectE(target)
+}
+
+// InvokeE calls fn with resolved parameters, returning an error instead of panicking
+// if a parameter cannot be resolved.
+func InvokeE(fn any, containers ...*Container) ([]any, error) {
+	return pickContainer(containers, "InvokeE").invoke(fn, nil)
+}
+
+// CallE is a type-safe version of InvokeE that returns T.
+// If fn returns (T, error), its error is returned as well.
+//
+// Example:
+//
+//	svc, err := container.CallE[*Service](func(db *Database) (*Service, error) {
+//	    return NewService(db)
+//	})
+func CallE[T any](fn any, containers ...*Container) (T, error) {
+	return callE[T](InvokeE(fn, containers...))
+}
+
+// callE converts the results of InvokeE or InvokeEVia for CallE and CallEVia
+func callE[T any](results []any, err error) (T, error) {
+	var zero T
+	if err != nil {
+		return zero, err
+	}
+
+	if len(results) == 0 {
+		return zero, errors.New("CallE: function must return T or (T, error)")
+	}
+
+	val, err := typedResult[T](results[0], "CallE: function")
+	if err != nil {
+		return zero, err
+	}
+
+	if len(results) == 2 {
+		if err, _ := results[1].(error); err != nil {
+			return val, err
+		}
+	}
+
+	return val, nil
+}
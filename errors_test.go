@@ -0,0 +1,152 @@
+package dshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestGetE_NotFound(t *testing.T) {
+	c := dshot.New()
+	token := dshot.NewToken[*Service]("missing")
+
+	if _, err := dshot.GetE(token, c); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestResolveE(t *testing.T) {
+	c := dshot.New()
+
+	if _, err := dshot.ResolveE[*Database](c); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	c.Provide(&Database{ConnectionString: "localhost"})
+	db, err := dshot.ResolveE[*Database](c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if db.ConnectionString != "localhost" {
+		t.Errorf("Expected 'localhost', got '%s'", db.ConnectionString)
+	}
+}
+
+func TestResolveE_FactoryError(t *testing.T) {
+	c := dshot.New()
+	errDown := errors.New("database down")
+	attempts := 0
+
+	dshot.ProvideAutoFactory(func() (*Database, func(), error) {
+		attempts++
+		if attempts == 1 {
+			return nil, nil, errDown
+		}
+		return &Database{ConnectionString: "localhost"}, nil, nil
+	}, c)
+
+	if _, err := dshot.ResolveE[*Database](c); !errors.Is(err, errDown) {
+		t.Fatalf("Expected factory error, got %v", err)
+	}
+
+	// Failed singletons are not cached
+	if _, err := dshot.ResolveE[*Database](c); err != nil {
+		t.Errorf("Expected retry to succeed, got %v", err)
+	}
+}
+
+func TestInjectE_MissingField(t *testing.T) {
+	c := dshot.New()
+
+	var target struct {
+		DB *Database
+	}
+
+	if err := dshot.InjectE(&target, c); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCallE(t *testing.T) {
+	c := dshot.New()
+
+	_, err := dshot.CallE[*Repository](func(db *Database) *Repository {
+		return &Repository{DB: db}
+	}, c)
+	if !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	c.Provide(&Database{ConnectionString: "localhost"})
+	repo, err := dshot.CallE[*Repository](func(db *Database) (*Repository, error) {
+		return &Repository{DB: db}, nil
+	}, c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if repo.DB.ConnectionString != "localhost" {
+		t.Errorf("Expected 'localhost', got '%s'", repo.DB.ConnectionString)
+	}
+}
+
+func TestBindAutoFactoryErr(t *testing.T) {
+	c := dshot.New()
+	errConfig := errors.New("missing DSN")
+	token := dshot.NewToken[*Database]("db")
+
+	c.Register(dshot.BindAutoFactoryErr(token, func() (*Database, error) {
+		return nil, errConfig
+	}, c))
+
+	if _, err := dshot.GetE(token, c); !errors.Is(err, errConfig) {
+		t.Errorf("Expected factory error, got %v", err)
+	}
+}
+
+func TestProvideAutoPrototypeErr(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "localhost"})
+
+	calls := 0
+	dshot.ProvideAutoPrototypeErr(func(db *Database) (*Repository, error) {
+		calls++
+		if calls%2 == 0 {
+			return nil, errors.New("even call")
+		}
+		return &Repository{DB: db}, nil
+	}, c)
+
+	if _, err := dshot.ResolveE[*Repository](c); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := dshot.ResolveE[*Repository](c); err == nil {
+		t.Error("Expected error from second prototype construction")
+	}
+}
+
+func TestResolutionError_Path(t *testing.T) {
+	c := dshot.New()
+
+	dshot.ProvideAutoFactory(func(repo *Repository) *Service {
+		return &Service{}
+	}, c)
+	dshot.ProvideAutoFactory(func(deps struct{ DB *Database }) *Repository {
+		return &Repository{DB: deps.DB}
+	}, c)
+
+	_, err := dshot.ResolveE[*Service](c)
+
+	var re *dshot.ResolutionError
+	if !errors.As(err, &re) {
+		t.Fatalf("Expected *ResolutionError, got %v", err)
+	}
+	if !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound cause, got %v", re.Err)
+	}
+
+	want := "*dshot_test.Service -> param 0 (*dshot_test.Repository) -> param 0 (struct { DB *dshot_test.Database }) -> field DB (*dshot_test.Database): dependency not found"
+	if err.Error() != want {
+		t.Errorf("Unexpected message:\n got: %s\nwant: %s", err, want)
+	}
+}
@@ -0,0 +1,81 @@
+package dshot
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// BindIf gates a registration behind a runtime predicate, such as a feature flag. The
+// predicate is evaluated once, when the token is first resolved. While it holds, the
+// registration is used; otherwise resolution falls back to the registration it replaced
+// under the same token in this container, or to the parent's, and fails with ErrNotFound
+// if there is none. The gated registration's lifecycle applies in both cases.
+//
+// Example:
+//
+//	c.Register(
+//	    container.BindAutoFactory(pricingToken, NewPricing),
+//	    container.BindIf(func(c *container.Container) bool {
+//	        return container.MustResolve[*Flags](c).Enabled("pricing-v2")
+//	    }, container.BindAutoFactory(pricingToken, NewPricingV2)),
+//	)
+func BindIf[T any](enabled func(c *Container) bool, r Registration[T]) Registration[T] {
+	if enabled == nil {
+		panic("BindIf: predicate cannot be nil")
+	}
+	r.enabled = enabled
+	return r
+}
+
+// gateEntry makes e resolve to the entry it replaces under token unless enabled holds
+// on first resolution. Callers must hold c.mu.
+func (c *Container) gateEntry(e *entry, token any, enabled func(c *Container) bool) {
+	prev := c.registry[token]
+	if prev != nil {
+		c.shadowEntry(prev)
+	}
+
+	inner := e.factory
+	if inner == nil {
+		value := e.value
+		inner = func(*resolution) (any, error) {
+			return value, nil
+		}
+		e.value = nil
+	}
+
+	var once sync.Once
+	var on bool
+	e.factory = func(r *resolution) (any, error) {
+		once.Do(func() {
+			on = enabled(c)
+		})
+		if on {
+			return inner(r)
+		}
+
+		fallback := prev
+		if fallback == nil && c.parent != nil {
+			fallback, _ = c.parent.lookupEntry(token)
+		}
+		if fallback == nil {
+			return nil, fmt.Errorf("%w: %s is disabled and replaces no registration", ErrNotFound, tokenName(token))
+		}
+		return r.in(c).resolveEntry(fallback, r)
+	}
+}
+
+// shadowEntry takes e out of the registration order and type index while keeping it
+// usable as a fallback. Callers must hold c.mu.
+func (c *Container) shadowEntry(e *entry) {
+	i := slices.Index(c.entries, e)
+	if i < 0 {
+		return
+	}
+	if i < c.indexed {
+		c.indexed--
+	}
+	c.entries = slices.Delete(c.entries, i, i+1)
+	c.reindex()
+}
@@ -0,0 +1,51 @@
+package dshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestBindIf(t *testing.T) {
+	token := dshot.NewToken[*Service]("pricing")
+	flag := false
+	evaluated := 0
+	enabled := func(*dshot.Container) bool {
+		evaluated++
+		return flag
+	}
+
+	on := dshot.New()
+	on.Register(
+		dshot.Bind(token, &Service{Name: "v1"}),
+		dshot.BindIf(enabled, dshot.BindAutoFactory(token, func() *Service { return &Service{Name: "v2"} }, on)),
+	)
+	flag = true // Evaluated on first resolution, not at registration
+	if svc := dshot.Get(token, on); svc.Name != "v2" {
+		t.Errorf("Expected 'v2' with the flag on, got '%s'", svc.Name)
+	}
+	if svc := dshot.MustResolve[*Service](on); svc.Name != "v2" {
+		t.Errorf("Expected the replaced registration out of type resolution, got '%s'", svc.Name)
+	}
+
+	flag = false
+	off := dshot.New()
+	off.Register(
+		dshot.Bind(token, &Service{Name: "v1"}),
+		dshot.BindIf(enabled, dshot.BindAutoFactory(token, func() *Service { return &Service{Name: "v2"} }, off)),
+	)
+	if svc := dshot.Get(token, off); svc.Name != "v1" {
+		t.Errorf("Expected 'v1' with the flag off, got '%s'", svc.Name)
+	}
+	dshot.Get(token, off)
+	if evaluated != 2 {
+		t.Errorf("Expected the predicate evaluated once per container, got %d", evaluated)
+	}
+
+	alone := dshot.New()
+	alone.Register(dshot.BindIf(enabled, dshot.Bind(token, &Service{Name: "v2"})))
+	if _, err := dshot.GetE(token, alone); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound without a fallback, got %v", err)
+	}
+}
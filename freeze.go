@@ -0,0 +1,74 @@
+package dshot
+
+import "fmt"
+
+// Freeze makes the container's registrations immutable: Provide, Register, RegisterModule,
+// Decorate, Replace, Remove, Clear and the other registration methods panic afterwards.
+// Pending lazy modules are loaded first; Freeze panics if one fails. Call it once the
+// production graph is assembled to catch late registrations from handlers; type and token
+// lookups of a frozen container skip locking.
+// A frozen container no longer consults its resolvers (see AddResolver), so Freeze
+// instantiates the resolver types its auto-wired factories depend on; resolve any other
+// type a resolver provides before freezing, or it is not found.
+// Scopes created from it remain writable.
+//
+// Example:
+//
+//	c := app.NewContainer()
+//	if err := c.Validate(); err != nil {
+//	    log.Fatal(err)
+//	}
+//	c.Freeze()
+func (c *Container) Freeze() {
+	if c.frozen.Load() {
+		return
+	}
+	c.checkWritable("Freeze")
+
+	if err := c.loadModules(); err != nil {
+		panic(fmt.Sprintf("Freeze: %v", err))
+	}
+	c.instantiateWired()
+	c.ensureIndexed()
+
+	// Set under the write lock so a mutation that checked writability under c.mu
+	// completes before lookups stop locking
+	c.mu.Lock()
+	c.frozen.Store(true)
+	c.mu.Unlock()
+}
+
+// instantiateWired registers the types resolvers provide for the parameters of the
+// container's auto-wired factories, transitively
+func (c *Container) instantiateWired() {
+	c.mu.RLock()
+	entries := c.entries
+	c.mu.RUnlock()
+
+	v := &validator{done: make(map[*entry]bool), instantiate: true}
+	for _, e := range entries {
+		v.visit(e, nil)
+	}
+}
+
+// readLock read-locks c unless it is frozen, reporting whether it did. The result is stable
+// until readUnlock: Freeze sets frozen under the write lock.
+func (c *Container) readLock() bool {
+	if c.frozen.Load() {
+		return false
+	}
+	c.mu.RLock()
+	return true
+}
+
+// readUnlock releases a read lock taken by readLock
+func (c *Container) readUnlock(locked bool) {
+	if locked {
+		c.mu.RUnlock()
+	}
+}
+
+// Frozen reports whether Freeze was called on the container
+func (c *Container) Frozen() bool {
+	return c.frozen.Load()
+}
@@ -0,0 +1,74 @@
+package dshot
+
+import (
+	"reflect"
+	"slices"
+)
+
+// InGroup adds the registration to named groups, letting features contribute to a
+// collection assembled elsewhere (template helpers, validation rules, event handlers)
+// without the collector knowing them. See ResolveGroup.
+//
+// Example:
+//
+//	container.Register(
+//	    container.BindAutoFactory(slugToken, NewSlugRule).InGroup("validators"),
+//	)
+func (r Registration[T]) InGroup(groups ...string) Registration[T] {
+	r.groups = append(r.groups[:len(r.groups):len(r.groups)], groups...)
+	return r
+}
+
+// ResolveGroup returns the values of the registrations in group that are assignable to T,
+// in registration order, followed by those of parent containers. It panics if one of
+// them fails to resolve.
+//
+// Example:
+//
+//	rules := container.ResolveGroup[FieldValidator]("validators", c)
+func ResolveGroup[T any](group string, containers ...*Container) []T {
+	vals, err := ResolveGroupE[T](group, containers...)
+	if err != nil {
+		panic(err)
+	}
+	return vals
+}
+
+// ResolveGroupE is ResolveGroup returning resolution failures instead of panicking
+func ResolveGroupE[T any](group string, containers ...*Container) ([]T, error) {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "ResolveGroup")
+
+	vals, err := c.resolveInGroup(reflect.TypeFor[T](), group, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	typed := make([]T, len(vals))
+	for i, val := range vals {
+		if typed[i], err = typedByType[T](val, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return typed, nil
+}
+
+// resolveInGroup resolves the registrations of group matching targetType
+func (c *Container) resolveInGroup(targetType reflect.Type, group string, r *resolution) ([]any, error) {
+	var ordered []orderedValue
+	for _, cand := range c.candidates(targetType, r.requester()) {
+		if !slices.Contains(cand.e.groups, group) {
+			continue
+		}
+
+		val, ok, err := cand.resolve(c, targetType, r)
+		if err != nil {
+			return nil, withStep(err, cand.e.label())
+		}
+		if ok {
+			ordered = append(ordered, orderedValue{cand.e, val})
+		}
+	}
+	return sortedValues(ordered), nil
+}
@@ -0,0 +1,69 @@
+package dshot_test
+
+import (
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestResolveGroup(t *testing.T) {
+	parent := dshot.New()
+	parent.Register(dshot.Bind(dshot.NewToken[*Service]("audit"), &Service{Name: "audit"}).InGroup("jobs"))
+
+	c := dshot.NewScoped(parent)
+	c.Register(
+		dshot.BindAutoFactory(dshot.NewToken[*Service]("cleanup"), func() *Service {
+			return &Service{Name: "cleanup"}
+		}, c).InGroup("jobs", "nightly"),
+		dshot.Bind(dshot.NewToken[*Service]("api"), &Service{Name: "api"}),
+	)
+
+	var names []string
+	for _, svc := range dshot.ResolveGroup[*Service]("jobs", c) {
+		names = append(names, svc.Name)
+	}
+	if len(names) != 2 || names[0] != "cleanup" || names[1] != "audit" {
+		t.Errorf("Expected the group members, local first, got %v", names)
+	}
+
+	for info := range c.All() {
+		if info.Token == "cleanup" && (len(info.Groups) != 2 || info.Groups[1] != "nightly") {
+			t.Errorf("Expected groups in RegistrationInfo, got %v", info.Groups)
+		}
+	}
+}
+
+func TestResolveTagged(t *testing.T) {
+	svcToken := dshot.NewToken[*Service]("orders")
+	dbToken := dshot.NewToken[*Database]("audit")
+
+	c := dshot.New()
+	c.Register(
+		dshot.Bind(svcToken, &Service{Name: "orders"}).WithTags("handler", "v2"),
+		dshot.Bind(dbToken, &Database{ConnectionString: "audit"}).WithTags("handler"),
+		dshot.Bind(dshot.NewToken[*Repository]("repo"), &Repository{}),
+	)
+
+	c.Register(dshot.Bind(dshot.NewToken[*ComplexService]("complex"), &ComplexService{}).InGroup("handler"))
+
+	scope := dshot.NewScoped(c)
+	scope.Register(dshot.Bind(svcToken, &Service{Name: "scoped"}).WithTags("handler"))
+
+	handlers := scope.ResolveTagged("handler")
+	if len(handlers) != 3 {
+		t.Fatalf("Expected 3 handlers, got %d", len(handlers))
+	}
+	if svc, ok := handlers[0].(*Service); !ok || svc.Name != "scoped" {
+		t.Errorf("Expected the scope's registration to shadow the parent's, got %v", handlers[0])
+	}
+	if _, ok := handlers[1].(*Database); !ok {
+		t.Errorf("Expected the database handler, got %v", handlers[1])
+	}
+
+	if v2 := c.ResolveTagged("handler", "v2"); len(v2) != 1 || v2[0].(*Service).Name != "orders" {
+		t.Errorf("Expected only the v2 handler, got %v", v2)
+	}
+	if svcs := dshot.ResolveGroup[*Service]("handler", c); len(svcs) != 1 {
+		t.Errorf("Expected tagged registrations in the group, got %v", svcs)
+	}
+}
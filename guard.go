@@ -0,0 +1,96 @@
+package dshot
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+var (
+	guardGlobal    atomic.Bool
+	globalDisabled atomic.Bool
+)
+
+// DisableGlobal makes every package-level helper that would fall back to the global
+// container (Provide, Resolve, MustResolve, Default, FromContext without a container, ...)
+// panic, for codebases that require containers to be passed explicitly. Helpers given a
+// container keep working. It cannot be undone; building with the dshot_noglobal tag
+// calls it at init.
+//
+// Example:
+//
+//	func main() {
+//	    container.DisableGlobal()
+//	    c := container.New()
+//	    container.Provide(&Config{}, c)
+//	}
+func DisableGlobal() {
+	globalDisabled.Store(true)
+}
+
+// checkGlobalUse panics if c is the global container and DisableGlobal was called
+func checkGlobalUse(c *Container, caller string) {
+	if c != defaultContainer || !globalDisabled.Load() {
+		return
+	}
+
+	panic(
+		fmt.Sprintf(
+			"%s: the global container is disabled by DisableGlobal; pass a container explicitly",
+			caller,
+		),
+	)
+}
+
+// GuardGlobalRegistration makes package-level registration helpers (Provide, ProvideFactory,
+// ProvideAutoFactory, Register, ...) panic when they target the global container while any
+// scope created from it with NewScoped is still open, i.e. while requests are being served.
+// Registrations into explicitly passed containers are not affected.
+//
+// Scopes count as open until Close (or Dispose) is called on them, so a scope that is
+// never closed keeps blocking global registration; RunScoped, RunInTx and RunHandler close
+// their scopes automatically. Child containers created with WithParent do not count.
+//
+// Example:
+//
+//	func main() {
+//	    container.GuardGlobalRegistration(true)
+//	    registerServices()
+//	    http.ListenAndServe(":8080", handler)
+//	}
+func GuardGlobalRegistration(enabled bool) {
+	guardGlobal.Store(enabled)
+}
+
+// checkGlobalRegistration panics if registering into c is forbidden by GuardGlobalRegistration
+// or DisableGlobal
+func checkGlobalRegistration(c *Container, caller string) {
+	checkGlobalUse(c, caller)
+
+	if c != defaultContainer || !guardGlobal.Load() {
+		return
+	}
+
+	if open := defaultContainer.openScopes.Load(); open > 0 {
+		panic(
+			fmt.Sprintf(
+				"%s: registering into the global container while %d scope(s) are open; "+
+					"register into the request scope instead (e.g. container.Provide(v, scope))",
+				caller, open,
+			),
+		)
+	}
+}
+
+// trackOpen counts the scope c of the global container as open until it is closed,
+// see releaseOpen
+func (c *Container) trackOpen() {
+	c.open.Store(true)
+	defaultContainer.openScopes.Add(1)
+}
+
+// releaseOpen stops counting c as an open scope of the global container, once
+func (c *Container) releaseOpen() {
+	if c.open.CompareAndSwap(true, false) {
+		defaultContainer.openScopes.Add(-1)
+	}
+}
@@ -0,0 +1,103 @@
+package dshot_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestGuardGlobalRegistration(t *testing.T) {
+	dshot.GuardGlobalRegistration(true)
+	defer dshot.GuardGlobalRegistration(false)
+	defer dshot.Clear()
+
+	scope := dshot.NewScoped(dshot.Default())
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Expected panic registering globally while a scope is open")
+			}
+		}()
+		dshot.Provide(&Service{Name: "late"})
+	}()
+
+	// Registering into the scope itself is fine
+	dshot.Provide(&Service{Name: "scoped"}, scope)
+
+	scope.Close(context.Background())
+
+	// No open scopes left, global registration is allowed again
+	dshot.Provide(&Service{Name: "startup"})
+
+	// Long-lived children do not count as open scopes
+	dshot.New(dshot.WithParent(dshot.Default()))
+	dshot.Provide(&Database{})
+}
+
+func TestGuardGlobalRegistration_ReleasedOnlyByClose(t *testing.T) {
+	dshot.GuardGlobalRegistration(true)
+	defer dshot.GuardGlobalRegistration(false)
+	defer dshot.Clear()
+
+	scope := dshot.NewScoped(dshot.Default())
+	runtime.GC()
+	if !panics(func() { dshot.Provide(&Service{}) }) {
+		t.Error("Expected an unclosed scope to keep blocking global registration")
+	}
+
+	scope.Dispose(context.Background())
+	scope.Close(context.Background())
+	if panics(func() { dshot.Provide(&Service{}) }) {
+		t.Error("Expected a disposed scope to stop blocking global registration")
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+func TestDisableGlobal(t *testing.T) {
+	// DisableGlobal cannot be undone, so it runs in a child test process
+	if os.Getenv("DSHOT_DISABLE_GLOBAL") != "1" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestDisableGlobal$")
+		cmd.Env = append(os.Environ(), "DSHOT_DISABLE_GLOBAL=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Child process failed: %v\n%s", err, out)
+		}
+		return
+	}
+
+	dshot.DisableGlobal()
+
+	c := dshot.New()
+	dshot.Provide(&Service{Name: "explicit"}, c)
+	if svc := dshot.MustResolve[*Service](c); svc.Name != "explicit" {
+		t.Errorf("Expected explicit container to keep working, got %q", svc.Name)
+	}
+
+	for name, fn := range map[string]func(){
+		"Provide": func() { dshot.Provide(&Service{}) },
+		"Resolve": func() { dshot.Resolve[*Service]() },
+		"Default": func() { dshot.Default() },
+	} {
+		func() {
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.Contains(msg, name+": the global container is disabled") {
+					t.Errorf("Expected %s to panic with guidance, got %q", name, msg)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,172 @@
+package dshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// InvokeWith calls fn like InvokeCtxE, passing each of args to the parameter of its type
+// (or, failing that, the first one it is assignable to) instead of resolving it. Event
+// dispatchers and message consumers use it to hand the event to a handler whose other
+// parameters are injected.
+//
+// Example:
+//
+//	results, err := container.InvokeWith(ctx, func(ctx context.Context, evt OrderPlaced, mail *Mailer) error {
+//	    return mail.SendConfirmation(ctx, evt.OrderID)
+//	}, evt)
+func InvokeWith(ctx context.Context, fn any, args ...any) ([]any, error) {
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func {
+		return nil, errors.New("InvokeWith: argument must be a function")
+	}
+	if len(args) == 0 {
+		return InvokeCtxE(ctx, fn)
+	}
+	fnType := fnValue.Type()
+
+	argTypes := make([]reflect.Type, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			return nil, errors.New("InvokeWith: arguments cannot be nil")
+		}
+		argTypes[i] = reflect.TypeOf(arg)
+	}
+	at, err := argParams(fnType, argTypes)
+	if err != nil {
+		return nil, fmt.Errorf("InvokeWith: %w", err)
+	}
+
+	given := make(map[int]reflect.Value, len(args))
+	for i, arg := range args {
+		given[at[i]] = reflect.ValueOf(arg)
+	}
+
+	// A function of the remaining parameters, whose arguments the container resolves
+	in := make([]reflect.Type, 0, fnType.NumIn()-len(given))
+	for i := 0; i < fnType.NumIn(); i++ {
+		if _, ok := given[i]; !ok {
+			in = append(in, fnType.In(i))
+		}
+	}
+	out := make([]reflect.Type, fnType.NumOut())
+	for i := range out {
+		out[i] = fnType.Out(i)
+	}
+
+	wrapper := reflect.MakeFunc(reflect.FuncOf(in, out, false), func(resolved []reflect.Value) []reflect.Value {
+		all := make([]reflect.Value, fnType.NumIn())
+		for i := range all {
+			if v, ok := given[i]; ok {
+				all[i] = v
+				continue
+			}
+			all[i], resolved = resolved[0], resolved[1:]
+		}
+		if fnType.IsVariadic() {
+			return fnValue.CallSlice(all)
+		}
+		return fnValue.Call(all)
+	})
+
+	return InvokeCtxE(ctx, wrapper.Interface())
+}
+
+// CheckFunc returns an error unless fn is a function InvokeWith can pass arguments of
+// argTypes to. Check handlers when they are registered, so that a mistake fails startup
+// rather than the first call.
+func CheckFunc(fn any, argTypes ...reflect.Type) error {
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return errors.New("handler must be a function")
+	}
+	_, err := argParams(fnType, argTypes)
+	return err
+}
+
+// CheckHandler is CheckFunc also requiring fn to return an error or nothing, as
+// CallHandler and RunHandler expect.
+//
+// Example:
+//
+//	if err := container.CheckHandler(fn, reflect.TypeFor[OrderPlaced]()); err != nil {
+//	    panic("events.On: " + err.Error())
+//	}
+func CheckHandler(fn any, argTypes ...reflect.Type) error {
+	if err := CheckFunc(fn, argTypes...); err != nil {
+		return err
+	}
+	if fnType := reflect.TypeOf(fn); fnType.NumOut() > 1 || fnType.NumOut() == 1 && fnType.Out(0) != errorType {
+		return errors.New("handler must return an error or nothing")
+	}
+	return nil
+}
+
+// CallHandler calls the handler fn (see CheckHandler) with InvokeWith and returns the
+// error it returns, or the resolution failure.
+func CallHandler(ctx context.Context, fn any, args ...any) error {
+	results, err := InvokeWith(ctx, fn, args...)
+	if err != nil {
+		return err
+	}
+	if len(results) == 1 && results[0] != nil {
+		return results[0].(error)
+	}
+	return nil
+}
+
+// RunHandler runs the handler fn (see CheckHandler) as a unit of work in a new scope of c
+// named name. Seed values are provided into the scope, fn's parameters are resolved from
+// it, and the scope is disposed when fn returns. The error of fn, a resolution failure, a
+// panic of fn and disposal errors are returned, joined, so that one message failing does
+// not stop a consumer.
+//
+// Example:
+//
+//	err := root.RunHandler(ctx, "message", func(ctx context.Context, msg *Message, h *Handler) error {
+//	    return h.Handle(ctx, msg)
+//	}, msg)
+func (c *Container) RunHandler(ctx context.Context, name string, fn any, seeds ...any) (err error) {
+	scope := NewScoped(c, name)
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("handler panicked: %v", p)
+		}
+		err = errors.Join(err, scope.Dispose(context.WithoutCancel(ctx)))
+	}()
+	for _, seed := range seeds {
+		scope.Provide(seed)
+	}
+
+	return CallHandler(WithContainer(ctx, scope), fn)
+}
+
+// argParams returns the index of the parameter of fnType receiving an argument of each of
+// argTypes: the first one of its type not taken by a previous argument, else the first one
+// it is assignable to
+func argParams(fnType reflect.Type, argTypes []reflect.Type) ([]int, error) {
+	at := make([]int, len(argTypes))
+	taken := make(map[int]bool, len(argTypes))
+
+	for i, argType := range argTypes {
+		at[i] = -1
+		for p := 0; p < fnType.NumIn() && at[i] < 0; p++ {
+			if !taken[p] && fnType.In(p) == argType {
+				at[i] = p
+			}
+		}
+		for p := 0; p < fnType.NumIn() && at[i] < 0; p++ {
+			if !taken[p] && argType.AssignableTo(fnType.In(p)) {
+				at[i] = p
+			}
+		}
+		if at[i] < 0 {
+			return nil, fmt.Errorf("handler must take a %s parameter", argType)
+		}
+		taken[at[i]] = true
+	}
+
+	return at, nil
+}
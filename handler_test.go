@@ -0,0 +1,77 @@
+package dshot_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type orderPlaced struct{ ID string }
+
+type orderTag string
+
+func (t orderTag) String() string { return string(t) }
+
+func TestInvokeWith_PassesArgumentsAndResolvesTheRest(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "localhost"})
+	ctx := dshot.WithContainer(context.Background(), c)
+
+	results, err := dshot.InvokeWith(ctx, func(db *Database, evt orderPlaced, tag fmt.Stringer) string {
+		return db.ConnectionString + "/" + evt.ID + "/" + tag.String()
+	}, orderPlaced{ID: "42"}, orderTag("rush"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if results[0] != "localhost/42/rush" {
+		t.Errorf("Expected the arguments passed, got %v", results[0])
+	}
+
+	if _, err := dshot.InvokeWith(ctx, func(db *Database) {}, orderPlaced{}); err == nil {
+		t.Error("Expected an error for an argument without a parameter")
+	}
+}
+
+func TestCheckHandler(t *testing.T) {
+	evt := reflect.TypeFor[orderPlaced]()
+	for name, fn := range map[string]any{
+		"not a function":   "handler",
+		"missing argument": func(db *Database) error { return nil },
+		"returns a value":  func(evt orderPlaced) string { return "" },
+	} {
+		if err := dshot.CheckHandler(fn, evt); err == nil {
+			t.Errorf("Expected %s rejected", name)
+		}
+	}
+	if err := dshot.CheckHandler(func(ctx context.Context, evt orderPlaced) {}, evt); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestRunHandler_ReturnsErrorsAndPanics(t *testing.T) {
+	c := dshot.New()
+	dshot.ProvideAutoScoped(func() *closableConn { return &closableConn{} }, c)
+	want := errors.New("failed")
+
+	var conn *closableConn
+	err := c.RunHandler(context.Background(), "message", func(evt orderPlaced, cn *closableConn) error {
+		conn = cn
+		return want
+	}, orderPlaced{ID: "42"})
+	if !errors.Is(err, want) {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+	if !conn.closed {
+		t.Error("Expected the scope disposed")
+	}
+
+	err = c.RunHandler(context.Background(), "message", func() { panic("boom") })
+	if err == nil || !strings.Contains(err.Error(), "handler panicked: boom") {
+		t.Errorf("Expected the panic returned, got %v", err)
+	}
+}
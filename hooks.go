@@ -0,0 +1,158 @@
+package dshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+var lifecycleHooksType = reflect.TypeFor[*LifecycleHooks]()
+
+// Hook is a pair of start and stop callbacks; either may be nil
+type Hook struct {
+	Name    string // Optional, used in error messages
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// LifecycleHooks collects start and stop hooks for a container.
+// It is resolvable like any other dependency, so factories can register hooks
+// for what they construct. Since dependencies are constructed first, hooks are
+// appended in dependency order.
+//
+// Example:
+//
+//	container.ProvideAutoFactory(func(lc *container.LifecycleHooks, cfg *Config) *Server {
+//	    srv := NewServer(cfg)
+//	    lc.Append(container.Hook{
+//	        OnStart: srv.Start,
+//	        OnStop:  srv.Shutdown,
+//	    })
+//	    return srv
+//	})
+type LifecycleHooks struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started int // Number of hooks whose OnStart has run
+}
+
+// Append adds a hook. Hooks appended after Start run on the next Start.
+func (l *LifecycleHooks) Append(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, h)
+}
+
+// hooks returns the lifecycle hooks of the container, creating them on first use.
+// Views share the hooks of the container they wrap.
+func (c *Container) hooks() *LifecycleHooks {
+	if c.view {
+		return c.parent.hooks()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lifecycleHooks == nil {
+		c.lifecycleHooks = &LifecycleHooks{}
+	}
+
+	return c.lifecycleHooks
+}
+
+// Start runs the OnStart hooks that have not run yet, in the order they were appended.
+// If one fails, the hooks already started are stopped in reverse order and the error is returned.
+func (c *Container) Start(ctx context.Context) error {
+	l := c.hooks()
+
+	for {
+		l.mu.Lock()
+		if l.started == len(l.hooks) {
+			l.mu.Unlock()
+			c.markReady()
+			return nil
+		}
+		h := l.hooks[l.started]
+		l.mu.Unlock()
+
+		if h.OnStart != nil {
+			if err := h.OnStart(ctx); err != nil {
+				err = fmt.Errorf("start hook %s: %w", hookName(h, l.started), err)
+				return errors.Join(err, c.Stop(ctx))
+			}
+		}
+
+		l.mu.Lock()
+		l.started++
+		l.mu.Unlock()
+	}
+}
+
+// markReady opens the readiness gate of the container, see WithReadinessGate
+func (c *Container) markReady() {
+	for c.view {
+		c = c.parent
+	}
+	if c.ready != nil {
+		c.readyOnce.Do(func() { close(c.ready) })
+	}
+}
+
+// Ready returns a channel closed once Start has completed, for readiness probes.
+// It is nil unless the container was created with WithReadinessGate.
+func (c *Container) Ready() <-chan struct{} {
+	return c.ready
+}
+
+// awaitReady waits until Start has completed if e is lifecycle-managed and the resolution
+// carries a context, see WithReadinessGate
+func (e *entry) awaitReady(r *resolution) error {
+	if e.owner == nil || e.owner.ready == nil || r == nil || r.ctx == nil ||
+		e.wiring == nil || !slices.Contains(e.wiring.params, lifecycleHooksType) {
+		return nil
+	}
+
+	select {
+	case <-e.owner.ready:
+		return nil
+	case <-r.ctx.Done():
+		return fmt.Errorf("waiting for Start: %w", r.ctx.Err())
+	}
+}
+
+// Stop runs the OnStop hooks of started hooks in reverse order.
+// All hooks are run; their errors are joined.
+func (c *Container) Stop(ctx context.Context) error {
+	l := c.hooks()
+
+	var errs []error
+	for {
+		l.mu.Lock()
+		if l.started == 0 {
+			l.mu.Unlock()
+			return errors.Join(errs...)
+		}
+		l.started--
+		i := l.started
+		h := l.hooks[i]
+		l.mu.Unlock()
+
+		if h.OnStop != nil {
+			if err := h.OnStop(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("stop hook %s: %w", hookName(h, i), err))
+			}
+		}
+	}
+}
+
+// hookName names a hook in error messages
+func hookName(h Hook, i int) string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return fmt.Sprintf("#%d", i)
+}
@@ -0,0 +1,112 @@
+package dshot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestLifecycleHooks_StartStopOrder(t *testing.T) {
+	c := dshot.New()
+	var events []string
+
+	hook := func(name string) dshot.Hook {
+		return dshot.Hook{
+			OnStart: func(context.Context) error { events = append(events, "start "+name); return nil },
+			OnStop:  func(context.Context) error { events = append(events, "stop "+name); return nil },
+		}
+	}
+
+	dshot.ProvideAutoFactory(func(lc *dshot.LifecycleHooks) *Database {
+		lc.Append(hook("db"))
+		return &Database{}
+	}, c)
+	dshot.ProvideAutoFactory(func(lc *dshot.LifecycleHooks, db *Database) *Repository {
+		lc.Append(hook("repo"))
+		return &Repository{DB: db}
+	}, c)
+
+	dshot.MustResolve[*Repository](c)
+
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Fatalf("Unexpected stop error: %v", err)
+	}
+
+	want := []string{"start db", "start repo", "stop repo", "stop db"}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, events)
+			break
+		}
+	}
+}
+
+func TestLifecycleHooks_StartFailureStopsStarted(t *testing.T) {
+	c := dshot.New()
+	lc := dshot.MustResolve[*dshot.LifecycleHooks](c)
+	stopped := false
+	errBoom := errors.New("boom")
+
+	lc.Append(dshot.Hook{OnStop: func(context.Context) error { stopped = true; return nil }})
+	lc.Append(dshot.Hook{Name: "broken", OnStart: func(context.Context) error { return errBoom }})
+
+	err := c.Start(context.Background())
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Expected start error, got %v", err)
+	}
+	if !stopped {
+		t.Error("Expected started hooks to be stopped after a failure")
+	}
+}
+
+func TestReadinessGate(t *testing.T) {
+	c := dshot.New(dshot.WithReadinessGate())
+	dshot.ProvideAutoFactory(func(lc *dshot.LifecycleHooks) *Database {
+		lc.Append(dshot.Hook{Name: "db"})
+		return &Database{}
+	}, c)
+	c.Provide(&Service{})
+
+	// Wiring before Start is not gated
+	dshot.MustResolve[*Database](c)
+
+	ctx := dshot.WithContainer(context.Background(), c)
+	dshot.MustResolveCtx[*Service](ctx) // Not lifecycle-managed
+
+	func() {
+		short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Expected resolution to wait for Start, got %v", err)
+			}
+		}()
+		dshot.MustResolveCtx[*Database](short)
+	}()
+
+	resolved := make(chan *Database)
+	go func() { resolved <- dshot.MustResolveCtx[*Database](ctx) }()
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	select {
+	case <-c.Ready():
+	default:
+		t.Error("Expected Ready to be closed after Start")
+	}
+	if db := <-resolved; db == nil {
+		t.Error("Expected database once started")
+	}
+}
@@ -0,0 +1,64 @@
+package dshot
+
+import "context"
+
+// Initializer is implemented by components needing a second setup phase once their
+// dependencies are set. In a container created WithInit, values built by its factories
+// have Init called right after construction; an error fails the resolution.
+//
+// Example:
+//
+//	func (c *Cache) Init(ctx context.Context) error {
+//	    return c.warm(ctx, c.repo)
+//	}
+type Initializer interface {
+	Init(ctx context.Context) error
+}
+
+// WithInit calls Init on every value built by the container's factories that implements
+// Initializer. Containers run no post-construct hook unless created with WithInit or
+// WithPostConstruct.
+//
+// Example:
+//
+//	c := container.New(container.WithInit())
+func WithInit() Option {
+	return WithPostConstruct(callInit)
+}
+
+// callInit is the post-construct hook installed by WithInit
+func callInit(ctx context.Context, v any) error {
+	if i, ok := v.(Initializer); ok {
+		return i.Init(ctx)
+	}
+	return nil
+}
+
+// WithPostConstruct sets the hook run on every value built by the container's factories.
+// Use it to honor an interface other than Initializer (see WithInit); return nil for
+// values the hook does not apply to. Scopes inherit the hook. Values provided as is, not
+// built by a factory, are not passed to it.
+//
+// Example:
+//
+//	type starter interface{ Setup() error }
+//
+//	c := container.New(container.WithPostConstruct(func(ctx context.Context, v any) error {
+//	    if s, ok := v.(starter); ok {
+//	        return s.Setup()
+//	    }
+//	    return nil
+//	}))
+func WithPostConstruct(fn func(ctx context.Context, v any) error) Option {
+	return func(c *Container) {
+		c.postConstructHook = fn
+	}
+}
+
+// postConstruct runs the post-construct hook on a value built by a factory of c
+func (c *Container) postConstruct(r *resolution, val any) error {
+	if c.postConstructHook == nil {
+		return nil
+	}
+	return c.postConstructHook(r.context(c), val)
+}
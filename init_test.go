@@ -0,0 +1,88 @@
+package dshot_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type warmCache struct {
+	repo  *Repository
+	warm  bool
+	trace any
+}
+
+func (c *warmCache) Init(ctx context.Context) error {
+	if c.repo == nil {
+		return errors.New("no repository")
+	}
+	c.warm = true
+	c.trace = ctx.Value(traceKey{})
+	return nil
+}
+
+func TestInit_CalledAfterConstruction(t *testing.T) {
+	c := dshot.New(dshot.WithInit())
+	c.Provide(&Repository{})
+	dshot.ProvideAutoFactory(func(repo *Repository) *warmCache {
+		return &warmCache{repo: repo}
+	}, c)
+
+	ctx := dshot.WithContainer(context.WithValue(context.Background(), traceKey{}, "init"), c)
+	cache := dshot.MustResolveCtx[*warmCache](ctx)
+	if !cache.warm || cache.trace != "init" {
+		t.Errorf("Expected Init called with the resolution context, got %+v", cache)
+	}
+}
+
+func TestInit_ErrorFailsResolution(t *testing.T) {
+	c := dshot.New(dshot.WithInit())
+	calls := 0
+	dshot.ProvideAutoFactory(func() *warmCache {
+		calls++
+		return &warmCache{}
+	}, c)
+
+	_, err := dshot.ResolveE[*warmCache](c)
+	if err == nil || !strings.Contains(err.Error(), "init *dshot_test.warmCache: no repository") {
+		t.Fatalf("Expected the Init error, got %v", err)
+	}
+	dshot.ResolveE[*warmCache](c)
+	if calls != 2 {
+		t.Errorf("Expected the failed singleton retried, got %d calls", calls)
+	}
+}
+
+func TestInit_OptIn(t *testing.T) {
+	c := dshot.New()
+	dshot.ProvideAutoFactory(func() *warmCache { return &warmCache{} }, c)
+
+	if cache, err := dshot.ResolveE[*warmCache](c); err != nil || cache.warm {
+		t.Errorf("Expected Init not called without WithInit, got %+v, %v", cache, err)
+	}
+}
+
+type setupper struct{ ready bool }
+
+func (s *setupper) Setup() { s.ready = true }
+
+func TestWithPostConstruct(t *testing.T) {
+	c := dshot.New(dshot.WithPostConstruct(func(_ context.Context, v any) error {
+		if s, ok := v.(interface{ Setup() }); ok {
+			s.Setup()
+		}
+		return nil
+	}))
+	dshot.ProvideAutoScoped(func() *setupper { return &setupper{} }, c)
+	dshot.ProvideAutoFactory(func() *warmCache { return &warmCache{} }, c)
+
+	if s := dshot.MustResolve[*setupper](dshot.NewScoped(c)); !s.ready {
+		t.Error("Expected the custom hook to run in scopes")
+	}
+	if cache := dshot.MustResolve[*warmCache](c); cache.warm {
+		t.Error("Expected Init replaced by the custom hook")
+	}
+}
@@ -0,0 +1,116 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// In marks a parameter struct whose fields are resolved independently, whatever the
+// number of factory parameters. Fields accept `dshot:"name=...,optional"` tags.
+//
+// Example:
+//
+//	type ServerParams struct {
+//	    container.In
+//
+//	    Config  *Config
+//	    Primary *sql.DB  `dshot:"name=primary-db"`
+//	    Tracer  *Tracer  `dshot:"optional"`
+//	}
+//
+//	container.ProvideAutoFactory(func(p ServerParams, log *Logger) *Server {
+//	    return NewServer(p.Config, p.Primary, p.Tracer, log)
+//	})
+type In struct{}
+
+// Out marks a result struct whose exported fields are each registered under their own type
+// (or under the token name of a `dshot:"name=..."` tag) by ProvideAutoFactory and friends.
+//
+// Example:
+//
+//	type Stores struct {
+//	    container.Out
+//
+//	    Users  *UserStore
+//	    Orders *OrderStore
+//	}
+//
+//	container.ProvideAutoFactory(func(db *sql.DB) Stores {
+//	    return Stores{Users: NewUserStore(db), Orders: NewOrderStore(db)}
+//	})
+//	users := container.MustResolve[*UserStore]()
+type Out struct{}
+
+var (
+	inType  = reflect.TypeFor[In]()
+	outType = reflect.TypeFor[Out]()
+)
+
+// isMarker reports whether typ is In or Out, which are never injected
+func isMarker(typ reflect.Type) bool {
+	return typ == inType || typ == outType
+}
+
+// isInStruct reports whether typ is a struct embedding In
+func isInStruct(typ reflect.Type) bool {
+	return embeds(typ, inType)
+}
+
+// isOutStruct reports whether typ is a struct embedding Out
+func isOutStruct(typ reflect.Type) bool {
+	return embeds(typ, outType)
+}
+
+func embeds(typ, marker reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if f := typ.Field(i); f.Anonymous && f.Type == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// addOutEntries registers each exported field of the Out struct built by e, with the
+// same lifecycle. Field values are read from the struct resolved through e, so a
+// singleton factory runs once for all of them. Callers must hold c.mu.
+func (c *Container) addOutEntries(e *entry) {
+	structType := e.depType
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() || isMarker(field.Type) {
+			continue
+		}
+
+		tag, err := parseInjectTag(field)
+		if tag.skip {
+			continue
+		}
+		if err != nil || tag.optional {
+			panic(fmt.Sprintf("Out struct %s: field %s: only name tags are allowed", structType, field.Name))
+		}
+
+		token := &tokenKey{key: providedKey(field.Type)}
+		if tag.name != "" {
+			token = &tokenKey{key: tag.name}
+		}
+
+		index := field.Index
+		c.addEntry(token, &entry{
+			factory: func(r *resolution) (any, error) {
+				out, err := r.in(c).resolveEntry(e, r)
+				if err != nil {
+					return nil, err
+				}
+				return reflect.ValueOf(out).FieldByIndex(index).Interface(), nil
+			},
+			lifecycle:  e.lifecycle,
+			depType:    field.Type,
+			wiring:     &wiring{c: c, params: []reflect.Type{structType}},
+			ownCleanup: e.ownCleanup,
+		})
+	}
+}
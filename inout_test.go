@@ -0,0 +1,74 @@
+package dshot_test
+
+import (
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type serverParams struct {
+	dshot.In
+
+	Primary *Database `dshot:"name=primary-db"`
+	Tracer  *tracer   `dshot:"optional"`
+	Service *Service  `dshot:"name=missing,optional"`
+}
+
+type server struct {
+	params serverParams
+	name   string
+}
+
+func TestInStruct(t *testing.T) {
+	c := dshot.New()
+	c.Register(dshot.Bind(dshot.NewToken[*Database]("primary-db"), &Database{ConnectionString: "primary"}))
+	c.Provide("api")
+	dshot.ProvideAutoFactory(func(p serverParams, name dshot.Optional[string]) *server {
+		return &server{params: p, name: name.OrElse("")}
+	}, c)
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	srv := dshot.MustResolve[*server](c)
+	if srv.params.Primary.ConnectionString != "primary" {
+		t.Errorf("Expected primary database, got %v", srv.params.Primary)
+	}
+	if srv.params.Tracer != nil || srv.params.Service != nil {
+		t.Error("Expected optional fields left nil")
+	}
+}
+
+type stores struct {
+	dshot.Out
+
+	Users   *Repository
+	Primary *Database `dshot:"name=primary-db"`
+}
+
+func TestOutStruct(t *testing.T) {
+	c := dshot.New()
+	calls := 0
+	dshot.ProvideAutoFactory(func() stores {
+		calls++
+		db := &Database{ConnectionString: "primary"}
+		return stores{Users: &Repository{DB: db}, Primary: db}
+	}, c)
+
+	var deps struct {
+		Users   *Repository
+		Primary *Database `dshot:"name=primary-db"`
+	}
+	c.Inject(&deps)
+
+	if deps.Users.DB != deps.Primary {
+		t.Error("Expected fields from the same factory call")
+	}
+	if calls != 1 {
+		t.Errorf("Expected the singleton factory to run once, got %d", calls)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package dshot
+
+// Interceptor wraps the factory calls of a container's registrations. It can add
+// latency, fail, or observe construction; call next to run the factory.
+//
+// Example:
+//
+//	c.AddInterceptor(func(info container.RegistrationInfo, next func() (any, error)) (any, error) {
+//	    start := time.Now()
+//	    defer func() { metrics.Observe(info.Token, time.Since(start)) }()
+//	    return next()
+//	})
+type Interceptor func(info RegistrationInfo, next func() (any, error)) (any, error)
+
+// AddInterceptor adds an interceptor around the factories registered in this container.
+// Interceptors run in the order they were added, outermost first. Fallback factories
+// are not intercepted, so a failure injected here exercises the fallback.
+func (c *Container) AddInterceptor(i Interceptor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkWritable("AddInterceptor")
+
+	c.interceptors = append(c.interceptors, i)
+}
+
+// intercepted wraps the factory of e with the container's interceptors
+func (c *Container) intercepted(
+	e *entry,
+	factory func(r *resolution) (any, error),
+) func(r *resolution) (any, error) {
+	c.mu.RLock()
+	interceptors := c.interceptors
+	c.mu.RUnlock()
+
+	if len(interceptors) == 0 {
+		return factory
+	}
+
+	return func(r *resolution) (any, error) {
+		info := e.info()
+		next := func() (any, error) {
+			return factory(r)
+		}
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			inner, interceptor := next, interceptors[i]
+			next = func() (any, error) {
+				return interceptor(info, inner)
+			}
+		}
+		return next()
+	}
+}
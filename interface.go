@@ -0,0 +1,269 @@
+package dshot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// AnyRegistration is implemented by every Registration[T] and accepted by Register
+type AnyRegistration = registration
+
+// ContainerI is the resolution and registration surface of a container.
+// *Container implements it. Wrap a container in your own ContainerI to add metrics,
+// access control or tracing, and pass the wrapper to the Via variants of the
+// package-level helpers (GetVia, FindVia, ResolveVia, MustResolveVia, ResolveAllVia,
+// InvokeVia, CallVia, CallErrVia) and their error-returning counterparts (GetEVia,
+// ResolveEVia, InvokeEVia, CallEVia).
+//
+// The Via variants are separate functions because the helpers' ...*Container parameter
+// cannot become ...ContainerI: callers spreading a []*Container (Resolve[T](cs...)) would
+// stop compiling, as Go does not convert a []*Container to a []ContainerI.
+//
+// Example:
+//
+//	type tracingContainer struct {
+//	    container.ContainerI
+//	}
+//
+//	func (t tracingContainer) Resolve(typ reflect.Type) (any, bool) {
+//	    start := time.Now()
+//	    defer func() { metrics.Observe(typ.String(), time.Since(start)) }()
+//	    return t.ContainerI.Resolve(typ)
+//	}
+//
+//	svc := container.MustResolveVia[*Service](tracingContainer{c})
+type ContainerI interface {
+	Get(token any) any
+	Find(token any) (any, bool)
+	Resolve(targetType reflect.Type) (any, bool)
+	ResolveAll(targetType reflect.Type) []any
+	Inject(target any)
+	Register(registrations ...AnyRegistration)
+
+	GetE(token any) (any, error)
+	ResolveE(targetType reflect.Type) (any, error)
+	InjectE(target any) error
+}
+
+var _ ContainerI = (*Container)(nil)
+
+// Find retrieves a value by token, returning false if it is not registered.
+// Falls back to the parent container if this is a scoped container.
+func (c *Container) Find(token any) (any, bool) {
+	e, ok, err := c.getEntry(token)
+	if err != nil {
+		panic(err)
+	}
+	if !ok {
+		return nil, false
+	}
+
+	val, err := c.resolveEntry(e, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return val, true
+}
+
+// pickContainer returns the first given container, or the global container if none (or nil) is given.
+// caller names the package-level helper in DisableGlobal panics.
+func pickContainer(containers []*Container, caller string) *Container {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, caller)
+	return c
+}
+
+// GetVia is Get retrieving the value through ci
+func GetVia[T any](token *Token[T], ci ContainerI) T {
+	return mustTyped(typedByToken[T](ci.Get(token), ci, token))
+}
+
+// FindVia is Find retrieving the value through ci
+func FindVia[T any](token *Token[T], ci ContainerI) (T, bool) {
+	var zero T
+	val, ok := ci.Find(token)
+	if !ok {
+		return zero, false
+	}
+
+	return mustTyped(typedByToken[T](val, ci, token)), true
+}
+
+// ResolveVia is Resolve resolving the dependency through ci
+func ResolveVia[T any](ci ContainerI) (T, bool) {
+	var zero T
+	val, ok := ci.Resolve(reflect.TypeFor[T]())
+	if !ok {
+		return zero, false
+	}
+
+	return mustTyped(typedByType[T](val, ci)), true
+}
+
+// MustResolveVia is MustResolve resolving the dependency through ci
+func MustResolveVia[T any](ci ContainerI) T {
+	val, ok := ResolveVia[T](ci)
+	if !ok {
+		targetType := reflect.TypeFor[T]()
+		if c, ok := ci.(*Container); ok {
+			if hint := c.typeHint(targetType); hint != "" {
+				panic(fmt.Sprintf("could not resolve dependency of type %s (%s)", targetType, hint))
+			}
+		}
+		panic(fmt.Sprintf("could not resolve dependency of type %s", targetType))
+	}
+	return val
+}
+
+// ResolveAllVia is ResolveAll resolving the values through ci
+func ResolveAllVia[T any](ci ContainerI) []T {
+	results := ci.ResolveAll(reflect.TypeFor[T]())
+
+	typed := make([]T, len(results))
+	for i, val := range results {
+		typed[i] = mustTyped(typedByType[T](val, ci))
+	}
+
+	return typed
+}
+
+// GetEVia is GetE retrieving the value through ci
+func GetEVia[T any](token *Token[T], ci ContainerI) (T, error) {
+	var zero T
+	val, err := ci.GetE(token)
+	if err != nil {
+		return zero, err
+	}
+
+	return typedByToken[T](val, ci, token)
+}
+
+// ResolveEVia is ResolveE resolving the dependency through ci
+func ResolveEVia[T any](ci ContainerI) (T, error) {
+	var zero T
+	val, err := ci.ResolveE(reflect.TypeFor[T]())
+	if err != nil {
+		return zero, err
+	}
+
+	return typedByType[T](val, ci)
+}
+
+// CallVia is Call resolving fn's parameters through ci, see InvokeVia
+func CallVia[T any](fn any, ci ContainerI) T {
+	results := InvokeVia(fn, ci)
+	return mustTyped(typedResult[T](results[0], "Call: function"))
+}
+
+// CallErrVia is CallErr resolving fn's parameters through ci, see InvokeVia
+func CallErrVia[T any](fn any, ci ContainerI) (T, error) {
+	return callErr[T](InvokeVia(fn, ci))
+}
+
+// CallEVia is CallE resolving fn's parameters through ci, see InvokeEVia
+func CallEVia[T any](fn any, ci ContainerI) (T, error) {
+	return callE[T](InvokeEVia(fn, ci))
+}
+
+// InvokeVia is Invoke resolving each parameter through ci.Resolve.
+// A single struct parameter that cannot be resolved is populated with ci.Inject.
+//
+// Example:
+//
+//	container.InvokeVia(func(db *Database, log *Logger) {
+//	    // ...
+//	}, tracingContainer{c})
+func InvokeVia(fn any, ci ContainerI) []any {
+	if c, ok := ci.(*Container); ok {
+		results, err := c.invoke(fn, nil)
+		if err != nil {
+			panic(err)
+		}
+		return results
+	}
+
+	fnValue := reflect.ValueOf(fn)
+	fnType := fnValue.Type()
+
+	if fnType.Kind() != reflect.Func {
+		panic("Invoke: argument must be a function")
+	}
+
+	numIn := fnType.NumIn()
+	args := make([]reflect.Value, numIn)
+
+	for i := 0; i < numIn; i++ {
+		paramType := fnType.In(i)
+
+		if val, ok := ci.Resolve(paramType); ok {
+			args[i] = reflect.ValueOf(val)
+			continue
+		}
+
+		if numIn == 1 && paramType.Kind() == reflect.Struct {
+			argValue := reflect.New(paramType)
+			ci.Inject(argValue.Interface())
+			args[i] = argValue.Elem()
+			continue
+		}
+
+		panic(fmt.Sprintf("Invoke: parameter %d (%s): no registration found for type %s", i, paramType, paramType))
+	}
+
+	results := fnValue.Call(args)
+
+	out := make([]any, len(results))
+	for i, result := range results {
+		out[i] = result.Interface()
+	}
+
+	return out
+}
+
+// InvokeEVia is InvokeVia resolving through ci.ResolveE and ci.InjectE, returning errors instead of panicking
+func InvokeEVia(fn any, ci ContainerI) ([]any, error) {
+	if c, ok := ci.(*Container); ok {
+		return c.invoke(fn, nil)
+	}
+
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func {
+		return nil, errors.New("Invoke: argument must be a function")
+	}
+	fnType := fnValue.Type()
+
+	numIn := fnType.NumIn()
+	args := make([]reflect.Value, numIn)
+
+	for i := 0; i < numIn; i++ {
+		paramType := fnType.In(i)
+
+		val, err := ci.ResolveE(paramType)
+		if err == nil {
+			args[i] = reflect.ValueOf(val)
+			continue
+		}
+
+		if errors.Is(err, ErrNotFound) && numIn == 1 && paramType.Kind() == reflect.Struct {
+			argValue := reflect.New(paramType)
+			if err := ci.InjectE(argValue.Interface()); err != nil {
+				return nil, withStep(err, paramStep(i, paramType))
+			}
+			args[i] = argValue.Elem()
+			continue
+		}
+
+		return nil, withStep(err, paramStep(i, paramType))
+	}
+
+	results := fnValue.Call(args)
+
+	out := make([]any, len(results))
+	for i, result := range results {
+		out[i] = result.Interface()
+	}
+
+	return out, nil
+}
@@ -0,0 +1,107 @@
+package dshot_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+// countingContainer wraps a container and counts type resolutions
+type countingContainer struct {
+	dshot.ContainerI
+	resolves map[reflect.Type]int
+}
+
+func (cc *countingContainer) Resolve(targetType reflect.Type) (any, bool) {
+	cc.resolves[targetType]++
+	return cc.ContainerI.Resolve(targetType)
+}
+
+func TestContainerI_WrapperAcceptedByHelpers(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "localhost"})
+	token := dshot.NewToken[*Service]("svc")
+	c.Register(dshot.Bind(token, &Service{Name: "svc"}))
+
+	wrapper := &countingContainer{ContainerI: c, resolves: map[reflect.Type]int{}}
+
+	db := dshot.MustResolveVia[*Database](wrapper)
+	if db.ConnectionString != "localhost" {
+		t.Errorf("Expected 'localhost', got '%s'", db.ConnectionString)
+	}
+
+	repo := dshot.CallVia[*Repository](func(db *Database) *Repository {
+		return &Repository{DB: db}
+	}, wrapper)
+	if repo.DB != db {
+		t.Error("Call should resolve parameters through the wrapper")
+	}
+
+	if svc := dshot.GetVia(token, wrapper); svc.Name != "svc" {
+		t.Errorf("Expected 'svc', got '%s'", svc.Name)
+	}
+
+	if n := wrapper.resolves[reflect.TypeFor[*Database]()]; n != 2 {
+		t.Errorf("Expected 2 intercepted resolutions, got %d", n)
+	}
+}
+
+func TestContainerHelpers_AcceptSpreadContainers(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "localhost"})
+	containers := []*dshot.Container{c}
+
+	if db := dshot.MustResolve[*Database](containers...); db.ConnectionString != "localhost" {
+		t.Errorf("Expected 'localhost', got '%s'", db.ConnectionString)
+	}
+	if _, err := dshot.InvokeE(func(db *Database) {}, containers...); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestResolve_InterfaceType(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&closableConn{})
+
+	if _, ok := dshot.Resolve[interface{ Close() error }](c); !ok {
+		t.Error("Should resolve by interface type")
+	}
+}
+
+type fileStore struct{ name string }
+
+func (f *fileStore) Read(p []byte) (int, error)  { return 0, nil }
+func (f *fileStore) Write(p []byte) (int, error) { return len(p), nil }
+
+func TestAs_ExplicitAliasesResolveDespiteOtherImplementations(t *testing.T) {
+	c := dshot.New()
+	c.Register(
+		dshot.As[io.Writer](dshot.As[io.Reader](
+			dshot.BindAutoFactory(dshot.NewToken[*fileStore]("primary"), func() *fileStore {
+				return &fileStore{name: "primary"}
+			}, c),
+		)),
+		dshot.Bind(dshot.NewToken[*fileStore]("backup"), &fileStore{name: "backup"}),
+	)
+
+	if _, err := dshot.ResolveE[*fileStore](c); err == nil {
+		t.Fatal("Expected the concrete type to stay ambiguous")
+	}
+
+	r := dshot.MustResolve[io.Reader](c)
+	w := dshot.MustResolve[io.Writer](c)
+	if r.(*fileStore).name != "primary" || w.(*fileStore) != r.(*fileStore) {
+		t.Errorf("Expected both aliases bound to the primary store, got %v and %v", r, w)
+	}
+}
+
+func TestAs_PanicsForNonImplementation(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected a panic for a type not implementing the alias")
+		}
+	}()
+	dshot.As[io.Closer](dshot.Bind(dshot.NewToken[*Service]("svc"), &Service{}))
+}
@@ -0,0 +1,182 @@
+package dshot
+
+import (
+	"iter"
+	"reflect"
+	"time"
+)
+
+// RegistrationInfo describes a registration without instantiating it
+type RegistrationInfo struct {
+	Token     string       // Token name (generated for type-based registrations)
+	Type      reflect.Type // Registered type
+	Lifecycle Lifecycle    // Singleton, Prototype or Scoped
+	Module    string       // Module that made the registration, empty if none
+	Scope     string       // Name of the container holding the registration
+	Depth     int          // Scope depth of that container, 0 for a root container
+
+	RegisteredAt time.Time // When the registration was made
+
+	// Capabilities declared with Registration.WithCapability; must not be modified
+	Capabilities map[string]string
+
+	// Groups the registration was added to with Registration.InGroup or WithTags; must not be modified
+	Groups []string
+}
+
+// All iterates over every registration in the container, followed by those of its parents.
+// Each registration is paired with a function that resolves it on demand, so large graphs
+// can be inspected without instantiating anything. Registrations private to a module (see
+// Private and Export) are skipped, as they cannot be resolved from outside it.
+//
+// Example:
+//
+//	for info, resolve := range c.All() {
+//	    if info.Lifecycle == container.Singleton {
+//	        val, err := resolve()
+//	        // ...
+//	    }
+//	}
+func (c *Container) All() iter.Seq2[RegistrationInfo, func() (any, error)] {
+	return func(yield func(RegistrationInfo, func() (any, error)) bool) {
+		// Restricted containers hide parent registrations outside their allowlist
+		var restrictions []*Container
+
+		for cur := c; cur != nil; cur = cur.parent {
+			cur.mu.RLock()
+			entries := cur.entries
+			cur.mu.RUnlock()
+
+			for _, e := range entries {
+				if !allowedBy(restrictions, e.depType) || !e.visibleTo(nil) {
+					continue
+				}
+				if !yield(e.info(), e.tryResolve) {
+					return
+				}
+			}
+
+			if cur.allow != nil {
+				restrictions = append(restrictions, cur)
+			}
+		}
+	}
+}
+
+// ResolveSeq returns an iterator over all registered values of type T.
+// Values are resolved lazily as the iteration advances.
+//
+// Example:
+//
+//	for h := range container.ResolveSeq[Handler]() {
+//	    h.Handle()
+//	}
+func ResolveSeq[T any](containers ...*Container) iter.Seq[T] {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "ResolveSeq")
+
+	targetType := reflect.TypeFor[T]()
+
+	return func(yield func(T) bool) {
+		for _, cand := range c.candidates(targetType, nil) {
+			val, ok, err := cand.resolve(c, targetType, nil)
+			if err != nil {
+				panic(err)
+			}
+			if !ok {
+				continue
+			}
+			if !yield(mustTyped(typedEntry[T](val, cand.e))) {
+				return
+			}
+		}
+	}
+}
+
+// ResolveEach resolves registered values of type T one at a time and passes them to fn.
+// Iteration stops as soon as fn returns false, so remaining candidates are never instantiated.
+//
+// Example:
+//
+//	container.ResolveEach(func(s Storage) bool {
+//	    if s.Available() {
+//	        storage = s
+//	        return false
+//	    }
+//	    return true
+//	})
+func ResolveEach[T any](fn func(T) bool, containers ...*Container) {
+	for val := range ResolveSeq[T](containers...) {
+		if !fn(val) {
+			return
+		}
+	}
+}
+
+// ResolveWhere returns the first registered value of type T accepted by pred.
+// Unlike Resolve, multiple candidates are not an error; they are tried in registration order.
+//
+// Example:
+//
+//	codec, ok := container.ResolveWhere(func(c Codec) bool {
+//	    return c.Supports("application/json")
+//	})
+func ResolveWhere[T any](pred func(T) bool, containers ...*Container) (T, bool) {
+	for val := range ResolveSeq[T](containers...) {
+		if pred(val) {
+			return val, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// ResolveWhereInfo returns the first value of type T whose registration is accepted by pred.
+// Only the selected registration is instantiated.
+//
+// Example:
+//
+//	cache, ok := container.ResolveWhereInfo[Cache](func(info container.RegistrationInfo) bool {
+//	    return info.Token == "redis-cache"
+//	})
+func ResolveWhereInfo[T any](pred func(RegistrationInfo) bool, containers ...*Container) (T, bool) {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "ResolveWhereInfo")
+
+	targetType := reflect.TypeFor[T]()
+
+	for _, cand := range c.candidates(targetType, nil) {
+		if !pred(cand.e.info()) {
+			continue
+		}
+		val, ok, err := cand.resolve(c, targetType, nil)
+		if err != nil {
+			panic(err)
+		}
+		if ok {
+			return mustTyped(typedEntry[T](val, cand.e)), true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// ResolveByCapability returns the first value of type T registered with the given capability.
+//
+// Example:
+//
+//	container.Register(
+//	    container.Bind(jsonToken, Codec(&JSONCodec{})).WithCapability("content-type", "application/json"),
+//	)
+//	codec, ok := container.ResolveByCapability[Codec]("content-type", "application/json")
+func ResolveByCapability[T any](key, value string, containers ...*Container) (T, bool) {
+	return ResolveWhereInfo[T](
+		func(info RegistrationInfo) bool {
+			v, ok := info.Capabilities[key]
+			return ok && v == value
+		},
+		containers...,
+	)
+}
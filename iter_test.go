@@ -0,0 +1,183 @@
+package dshot_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestAll_IteratesWithoutResolving(t *testing.T) {
+	c := dshot.New()
+	callCount := 0
+
+	c.ProvideFactory(func() *Service {
+		callCount++
+		return &Service{Name: "Lazy"}
+	})
+	c.Provide(&Database{ConnectionString: "localhost"})
+
+	count := 0
+	for info, resolve := range c.All() {
+		count++
+		if info.Type == nil {
+			t.Error("RegistrationInfo should carry the registered type")
+		}
+		if info.Type.String() == "*dshot_test.Service" {
+			val, err := resolve()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if val.(*Service).Name != "Lazy" {
+				t.Errorf("Expected 'Lazy', got '%s'", val.(*Service).Name)
+			}
+		}
+	}
+
+	if count != 2 {
+		t.Errorf("Expected 2 registrations, got %d", count)
+	}
+
+	if callCount != 1 {
+		t.Errorf("Expected factory to be called once, got %d", callCount)
+	}
+}
+
+func TestResolveSeq_StopsEarly(t *testing.T) {
+	c := dshot.New()
+	callCount := 0
+
+	for range 3 {
+		c.ProvidePrototype(func() *Service {
+			callCount++
+			return &Service{Name: "Seq"}
+		})
+	}
+
+	for range dshot.ResolveSeq[*Service](c) {
+		break
+	}
+
+	if callCount != 1 {
+		t.Errorf("Expected only one instantiation, got %d", callCount)
+	}
+}
+
+func TestResolveEach_StopsWhenCallbackReturnsFalse(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Service{Name: "First"})
+	c.Provide(&Service{Name: "Second"})
+	c.Provide(&Service{Name: "Third"})
+
+	var visited []string
+	dshot.ResolveEach(func(s *Service) bool {
+		visited = append(visited, s.Name)
+		return s.Name != "Second"
+	}, c)
+
+	if len(visited) != 2 {
+		t.Fatalf("Expected 2 visited services, got %d", len(visited))
+	}
+
+	if visited[0] != "First" || visited[1] != "Second" {
+		t.Errorf("Unexpected visit order: %v", visited)
+	}
+}
+
+func TestResolveWhere(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Service{Name: "json"})
+	c.Provide(&Service{Name: "xml"})
+
+	svc, ok := dshot.ResolveWhere(func(s *Service) bool {
+		return s.Name == "xml"
+	}, c)
+	if !ok {
+		t.Fatal("Expected a matching service")
+	}
+	if svc.Name != "xml" {
+		t.Errorf("Expected 'xml', got '%s'", svc.Name)
+	}
+
+	if _, ok := dshot.ResolveWhere(func(s *Service) bool { return false }, c); ok {
+		t.Error("Should not match when predicate rejects all candidates")
+	}
+}
+
+func TestResolveWhereInfo_OnlyInstantiatesMatch(t *testing.T) {
+	c := dshot.New()
+	primary := dshot.NewToken[*Service]("primary")
+	secondary := dshot.NewToken[*Service]("secondary")
+	calls := map[string]int{}
+
+	c.Register(
+		dshot.BindAutoFactory(primary, func() *Service {
+			calls["primary"]++
+			return &Service{Name: "primary"}
+		}, c),
+		dshot.BindAutoFactory(secondary, func() *Service {
+			calls["secondary"]++
+			return &Service{Name: "secondary"}
+		}, c),
+	)
+
+	svc, ok := dshot.ResolveWhereInfo[*Service](func(info dshot.RegistrationInfo) bool {
+		return info.Token == "secondary"
+	}, c)
+	if !ok {
+		t.Fatal("Expected a matching service")
+	}
+	if svc.Name != "secondary" {
+		t.Errorf("Expected 'secondary', got '%s'", svc.Name)
+	}
+	if calls["primary"] != 0 {
+		t.Error("Non-matching registration should not be instantiated")
+	}
+}
+
+func TestResolveByCapability(t *testing.T) {
+	c := dshot.New()
+	jsonToken := dshot.NewToken[*Service]("json-codec")
+	xmlToken := dshot.NewToken[*Service]("xml-codec")
+
+	c.Register(
+		dshot.Bind(jsonToken, &Service{Name: "json"}).WithCapability("content-type", "application/json"),
+		dshot.Bind(xmlToken, &Service{Name: "xml"}).WithCapability("content-type", "application/xml"),
+	)
+
+	svc, ok := dshot.ResolveByCapability[*Service]("content-type", "application/xml", c)
+	if !ok {
+		t.Fatal("Expected a service with the capability")
+	}
+	if svc.Name != "xml" {
+		t.Errorf("Expected 'xml', got '%s'", svc.Name)
+	}
+
+	if _, ok := dshot.ResolveByCapability[*Service]("content-type", "text/plain", c); ok {
+		t.Error("Should not resolve an undeclared capability")
+	}
+}
+
+func TestAll_ReportsRegistrationOrigin(t *testing.T) {
+	before := time.Now()
+	root := dshot.New()
+	root.Provide(&Database{})
+	tenant := dshot.NewScoped(root, "tenant-42")
+	tenant.Provide(&Service{})
+
+	origins := make(map[string]dshot.RegistrationInfo)
+	for info := range tenant.All() {
+		origins[info.Type.String()] = info
+	}
+
+	if db := origins["*dshot_test.Database"]; db.Scope != "root" || db.Depth != 0 {
+		t.Errorf("Expected database from root at depth 0, got %q at %d", db.Scope, db.Depth)
+	}
+	svc := origins["*dshot_test.Service"]
+	if svc.Scope != "tenant-42" || svc.Depth != 1 {
+		t.Errorf("Expected service from tenant-42 at depth 1, got %q at %d", svc.Scope, svc.Depth)
+	}
+	if svc.RegisteredAt.Before(before) || svc.RegisteredAt.Before(origins["*dshot_test.Database"].RegisteredAt) {
+		t.Errorf("Expected registration times in order, got %v", svc.RegisteredAt)
+	}
+}
@@ -0,0 +1,199 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Module groups the registrations of a subsystem
+//
+// Example:
+//
+//	var BillingModule container.Module = func(c *container.Container) {
+//	    container.ProvideAutoFactory(NewInvoiceRepo, c)
+//	    container.ProvideAutoFactory(NewBillingService, c)
+//	}
+type Module func(c *Container)
+
+// lazyModule is a module registered with RegisterLazyModule and not loaded yet
+type lazyModule struct {
+	name     string
+	load     func() Module
+	provides []any     // Types and tokens the module registers, none if unknown
+	once     sync.Once // Loads the module once when lookups miss concurrently
+	err      error     // Why loading failed, returned by every lookup needing the module
+}
+
+// RegisterModule registers the module's dependencies in the container.
+// Each module name can be registered once.
+//
+// Example:
+//
+//	c.RegisterModule("billing", BillingModule)
+func (c *Container) RegisterModule(name string, m Module) {
+	c.checkWritable("RegisterModule")
+	c.claimModule(name)
+	c.runModule(name, m)
+}
+
+// RegisterLazyModule registers a module that is loaded only when a Get, Find or Resolve
+// on this container (or one of its scopes) misses one of the types (reflect.Type) or tokens
+// the module provides, so rarely used subsystems pay no wiring or init cost unless
+// something resolves from them. Misses of anything else load no module. A module listing
+// nothing it provides is loaded by any miss, after the modules providing the dependency.
+// A module is loaded once even if concurrent lookups miss it; they wait for it to be
+// registered. If the loader or the module panics, the lookup fails with the panic, and so
+// does every later lookup the module provides for.
+// ResolveAll and All do not load lazy modules; Validate and Freeze load all of them.
+//
+// Example:
+//
+//	c.RegisterLazyModule("admin", func() container.Module {
+//	    return admin.Module(adminConfig)
+//	}, reflect.TypeFor[*admin.Console](), admin.AuditToken)
+func (c *Container) RegisterLazyModule(name string, load func() Module, provides ...any) {
+	c.checkWritable("RegisterLazyModule")
+	if slices.Contains(provides, nil) {
+		panic(fmt.Sprintf("RegisterLazyModule: module %q cannot provide nil", name))
+	}
+	c.claimModule(name)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Freeze may have run since the check above
+	c.checkWritable("RegisterLazyModule")
+	c.lazyModules = append(c.lazyModules, &lazyModule{name: name, load: load, provides: provides})
+}
+
+// claimModule records a module name, panicking on duplicates
+func (c *Container) claimModule(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.modules[name] {
+		panic(fmt.Sprintf("module %q is already registered", name))
+	}
+	if c.modules == nil {
+		c.modules = make(map[string]bool)
+	}
+	c.modules[name] = true
+}
+
+// runModule makes the module's registrations, recording the module name on them
+func (c *Container) runModule(name string, m Module) {
+	c.mu.Lock()
+	prev := c.loadingModule
+	c.loadingModule = name
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.loadingModule = prev
+		c.sealModule(name)
+		c.mu.Unlock()
+	}()
+
+	m(c)
+}
+
+// loadModule loads the first pending lazy module of the container or its parents accepted
+// by match, waiting if another lookup is loading it; modules providing nothing are
+// accepted if no other is. It reports false if there is none, and the error of a module
+// that failed to load, which stays pending so that the lookups needing it keep failing.
+func (c *Container) loadModule(match func(m *lazyModule) bool) (bool, error) {
+	for _, fallback := range []bool{false, true} {
+		for cur := c; cur != nil; cur = cur.parent {
+			cur.mu.RLock()
+			i := slices.IndexFunc(cur.lazyModules, func(m *lazyModule) bool {
+				if fallback {
+					return len(m.provides) == 0
+				}
+				return len(m.provides) > 0 && match(m)
+			})
+			var m *lazyModule
+			if i >= 0 {
+				m = cur.lazyModules[i]
+			}
+			cur.mu.RUnlock()
+
+			if m == nil {
+				continue
+			}
+
+			m.once.Do(func() { m.err = cur.loadLazyModule(m) })
+			return true, m.err
+		}
+	}
+
+	return false, nil
+}
+
+// loadLazyModule runs the lazy module m and drops it from the pending ones, returning a
+// panic of its loader or registrations as an error instead
+func (c *Container) loadLazyModule(m *lazyModule) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rErr, ok := r.(error); ok {
+				err = fmt.Errorf("loading module %q: %w", m.name, rErr)
+				return
+			}
+			err = fmt.Errorf("loading module %q: %v", m.name, r)
+		}
+	}()
+
+	c.runModule(m.name, m.load())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lazyModules = slices.DeleteFunc(c.lazyModules, func(pending *lazyModule) bool {
+		return pending == m
+	})
+	return nil
+}
+
+// loadModules loads all pending lazy modules of the container and its parents, stopping
+// at the first that fails
+func (c *Container) loadModules() error {
+	for {
+		loaded, err := c.loadModule(func(*lazyModule) bool { return true })
+		if !loaded || err != nil {
+			return err
+		}
+	}
+}
+
+// loadModuleForType loads a pending lazy module providing targetType, see loadModule
+func (c *Container) loadModuleForType(targetType reflect.Type) (bool, error) {
+	return c.loadModule(func(m *lazyModule) bool {
+		for _, p := range m.provides {
+			if t, ok := p.(reflect.Type); ok && (t.AssignableTo(targetType) || isSimilarKind(t, targetType)) {
+				return true
+			}
+			if tok, ok := p.(typed); ok && tok.valueType().AssignableTo(targetType) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// loadModuleForToken loads a pending lazy module providing token, see loadModule
+func (c *Container) loadModuleForToken(token any) (bool, error) {
+	return c.loadModule(func(m *lazyModule) bool {
+		return slices.Contains(m.provides, token)
+	})
+}
+
+// loadModuleForName loads a pending lazy module providing a token named name, see loadModule
+func (c *Container) loadModuleForName(name string) (bool, error) {
+	return c.loadModule(func(m *lazyModule) bool {
+		return slices.ContainsFunc(m.provides, func(p any) bool {
+			_, isType := p.(reflect.Type)
+			return !isType && tokenName(p) == name
+		})
+	})
+}
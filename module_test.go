@@ -0,0 +1,223 @@
+package dshot_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestRegisterLazyModule_LoadedOnMiss(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "localhost"})
+
+	var loaded []string
+	c.RegisterLazyModule("services", func() dshot.Module {
+		loaded = append(loaded, "services")
+		return func(c *dshot.Container) {
+			dshot.ProvideAutoFactory(func(db *Database) *Repository {
+				return &Repository{DB: db}
+			}, c)
+		}
+	}, reflect.TypeFor[*Repository]())
+	c.RegisterLazyModule("admin", func() dshot.Module {
+		loaded = append(loaded, "admin")
+		return func(c *dshot.Container) {
+			c.Provide(&Service{Name: "admin"})
+		}
+	}, reflect.TypeFor[*Service]())
+
+	dshot.MustResolve[*Database](c)
+	if len(loaded) != 0 {
+		t.Fatalf("Expected no modules loaded, got %v", loaded)
+	}
+
+	scope := dshot.NewScoped(c)
+	repo := dshot.MustResolve[*Repository](scope)
+	if repo.DB.ConnectionString != "localhost" {
+		t.Errorf("Expected 'localhost', got '%s'", repo.DB.ConnectionString)
+	}
+	if len(loaded) != 1 || loaded[0] != "services" {
+		t.Errorf("Expected only 'services' loaded, got %v", loaded)
+	}
+
+	if _, ok := dshot.Resolve[*ComplexService](c); ok {
+		t.Error("Should not resolve an unregistered type")
+	}
+	dshot.Resolve[dshot.Optional[*ComplexService]](c)
+	dshot.Call[[]*Database](func(dbs []*Database) []*Database { return dbs }, c)
+	if len(loaded) != 1 {
+		t.Errorf("Expected misses of unprovided types to load no module, got %v", loaded)
+	}
+
+	if svc := dshot.MustResolve[*Service](c); svc.Name != "admin" {
+		t.Errorf("Expected 'admin', got '%s'", svc.Name)
+	}
+	if len(loaded) != 2 {
+		t.Errorf("Expected the module providing *Service loaded, got %v", loaded)
+	}
+}
+
+func TestRegisterLazyModule_ConcurrentMissesLoadOnce(t *testing.T) {
+	c := dshot.New()
+
+	var loads atomic.Int32
+	c.RegisterLazyModule("services", func() dshot.Module {
+		loads.Add(1)
+		return func(c *dshot.Container) {
+			c.Provide(&Service{Name: "lazy"})
+		}
+	}, reflect.TypeFor[*Service]())
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if svc := dshot.MustResolve[*Service](c); svc.Name != "lazy" {
+				t.Errorf("Expected 'lazy', got '%s'", svc.Name)
+			}
+		})
+	}
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Errorf("Expected the module loaded once, got %d", loads.Load())
+	}
+}
+
+func TestRegisterLazyModule_FailedLoadFailsLookups(t *testing.T) {
+	c := dshot.New()
+	c.RegisterLazyModule("services", func() dshot.Module {
+		panic("config missing")
+	}, reflect.TypeFor[*Service]())
+
+	for range 2 {
+		_, err := dshot.ResolveE[*Service](c)
+		if err == nil || !strings.Contains(err.Error(), `loading module "services": config missing`) {
+			t.Errorf("Expected the load failure, got %v", err)
+		}
+	}
+	if err := c.Validate(); err == nil {
+		t.Error("Expected Validate to report the load failure")
+	}
+}
+
+func TestRegisterLazyModule_WithoutProvides(t *testing.T) {
+	c := dshot.New()
+	c.RegisterLazyModule("services", func() dshot.Module {
+		return func(c *dshot.Container) {
+			c.Provide(&Service{Name: "lazy"})
+		}
+	})
+
+	if svc := dshot.MustResolve[*Service](c); svc.Name != "lazy" {
+		t.Errorf("Expected 'lazy', got '%s'", svc.Name)
+	}
+}
+
+func TestRegisterModule_DuplicateName(t *testing.T) {
+	c := dshot.New()
+	c.RegisterModule("db", func(c *dshot.Container) {
+		c.Provide(&Database{})
+	})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for duplicate module name")
+		}
+	}()
+
+	c.RegisterLazyModule("db", func() dshot.Module {
+		return func(*dshot.Container) {}
+	}, reflect.TypeFor[*Database]())
+}
+
+func TestExport_HidesUnexportedRegistrations(t *testing.T) {
+	c := dshot.New()
+	c.RegisterModule("data", func(c *dshot.Container) {
+		c.Provide(&Database{ConnectionString: "internal"})
+		dshot.ProvideAutoFactory(func(db *Database) *Repository {
+			return &Repository{DB: db}
+		}, c)
+		c.Export(reflect.TypeFor[*Repository]())
+	})
+
+	repo := dshot.MustResolve[*Repository](c)
+	if repo.DB.ConnectionString != "internal" {
+		t.Errorf("Expected the module's own database, got '%s'", repo.DB.ConnectionString)
+	}
+
+	_, err := dshot.ResolveE[*Database](c)
+	if !errors.Is(err, dshot.ErrNotFound) || !strings.Contains(err.Error(), `private to module "data"`) {
+		t.Errorf("Expected the database private to the module, got %v", err)
+	}
+
+	dshot.ProvideAutoFactory(func(db *Database) *Service {
+		return &Service{Name: db.ConnectionString}
+	}, c)
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "private to module") {
+		t.Errorf("Expected Validate to report the private dependency, got %v", err)
+	}
+}
+
+func TestPrivate_Registration(t *testing.T) {
+	token := dshot.NewToken[*Database]("db")
+
+	c := dshot.New()
+	c.RegisterModule("data", func(c *dshot.Container) {
+		c.Register(dshot.Bind(token, &Database{ConnectionString: "private"}).Private())
+		dshot.ProvideAutoFactory(func(db *Database) *Repository {
+			return &Repository{DB: db}
+		}, c)
+	})
+
+	if _, err := dshot.GetE(token, c); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected the token private to the module, got %v", err)
+	}
+	if repo := dshot.MustResolve[*Repository](c); repo.DB.ConnectionString != "private" {
+		t.Errorf("Expected the private database injected, got '%s'", repo.DB.ConnectionString)
+	}
+
+	for info := range c.All() {
+		if info.Type == reflect.TypeFor[*Database]() {
+			t.Errorf("Expected All to skip the private registration, got %s", info.Token)
+		}
+	}
+}
+
+func TestExport_PrivateRegistrationsDoNotCollide(t *testing.T) {
+	c := dshot.New()
+	repos := make(map[string]*dshot.Token[*Repository])
+	seen := make(map[string]int)
+	for _, name := range []string{"orders", "billing"} {
+		repos[name] = dshot.NewToken[*Repository](name)
+		c.RegisterModule(name, func(c *dshot.Container) {
+			c.Provide(&Database{ConnectionString: name})
+			c.Register(dshot.BindAutoFactory(repos[name], func(db *Database, all []*Database) *Repository {
+				seen[name] = len(all)
+				return &Repository{DB: db}
+			}, c))
+			c.Export(repos[name])
+		})
+	}
+
+	for name, token := range repos {
+		if repo := dshot.Get(token, c); repo.DB.ConnectionString != name || seen[name] != 1 {
+			t.Errorf("Expected %s to see only its own database, got '%s' of %d", name, repo.DB.ConnectionString, seen[name])
+		}
+	}
+	if _, err := dshot.ResolveE[*Database](c); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected private databases not found outside their modules, got %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+
+	c.Provide(&Database{ConnectionString: "shared"})
+	if db := dshot.MustResolve[*Database](c); db.ConnectionString != "shared" {
+		t.Errorf("Expected private databases skipped, got '%s'", db.ConnectionString)
+	}
+}
@@ -0,0 +1,68 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isMultiOutput reports whether an auto-wired factory returns several values to register,
+// optionally followed by an error, e.g. func(*Config) (*DB, *Cache, error)
+func isMultiOutput(fnType reflect.Type) bool {
+	return fnType.NumOut() >= 2 && !isCleanupFactory(fnType)
+}
+
+// provideMultiOutput registers each non-error result of factory under its own type.
+// The factory runs once per lifecycle instance for all of them: a hidden entry holds
+// its results and each result entry reads its own.
+func (c *Container) provideMultiOutput(fnValue reflect.Value, fnType reflect.Type, lifecycle Lifecycle) {
+	numOut := fnType.NumOut()
+	withError := fnType.Out(numOut-1) == errorType
+	if withError {
+		numOut--
+	}
+
+	for i := 0; i < numOut; i++ {
+		if fnType.Out(i) == errorType {
+			panic("factory error must be the last return value")
+		}
+	}
+
+	results := &entry{
+		token: &tokenKey{key: fnType.String()},
+		factory: func(r *resolution) (any, error) {
+			out, err := r.in(c).invoke(fnValue.Interface(), r)
+			if err != nil {
+				return nil, err
+			}
+			if withError && out[numOut] != nil {
+				return nil, fmt.Errorf("factory[%v] returned error: %w", fnType, out[numOut].(error))
+			}
+			return out[:numOut], nil
+		},
+		lifecycle:  lifecycle,
+		depType:    fnType,
+		owner:      c,
+		ownCleanup: true, // Results are disposed through their own entries
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results.module = c.loadingModule
+
+	for i := 0; i < numOut; i++ {
+		returnType := fnType.Out(i)
+		c.addEntry(&tokenKey{key: providedKey(returnType)}, &entry{
+			factory: func(r *resolution) (any, error) {
+				out, err := r.in(c).resolveEntry(results, r)
+				if err != nil {
+					return nil, err
+				}
+				return out.([]any)[i], nil
+			},
+			lifecycle: lifecycle,
+			depType:   returnType,
+			wiring:    newWiring(c, fnType),
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package dshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type cache struct{ db *Database }
+
+func TestMultiOutputFactory(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Service{Name: "config"})
+	calls := 0
+
+	dshot.ProvideAutoFactory(func(cfg *Service) (*Database, *cache, error) {
+		calls++
+		db := &Database{ConnectionString: cfg.Name}
+		return db, &cache{db: db}, nil
+	}, c)
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	db := dshot.MustResolve[*Database](c)
+	ch := dshot.MustResolve[*cache](c)
+	if ch.db != db || db.ConnectionString != "config" {
+		t.Errorf("Expected results of a single factory call, got %v and %v", db, ch.db)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the factory to run once, got %d", calls)
+	}
+}
+
+func TestMultiOutputFactory_Error(t *testing.T) {
+	c := dshot.New()
+	errDown := errors.New("database down")
+
+	dshot.ProvideAutoFactory(func() (*Database, *cache, error) {
+		return nil, nil, errDown
+	}, c)
+
+	if _, err := dshot.ResolveE[*cache](c); !errors.Is(err, errDown) {
+		t.Errorf("Expected factory error, got %v", err)
+	}
+}
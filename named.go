@@ -0,0 +1,135 @@
+package dshot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// injectTag holds the options of a `dshot:"name=primary-db,optional"` struct field tag.
+// token= and value= are synonyms of name=; `dshot:"-"` skips the field.
+type injectTag struct {
+	name     string // Token name the field is resolved by, "" to resolve by type
+	optional bool   // Leave the field zero if nothing is registered
+	skip     bool   // Never populate the field
+}
+
+// parseInjectTag parses the dshot tag of a struct field
+func parseInjectTag(field reflect.StructField) (injectTag, error) {
+	var t injectTag
+
+	tag, ok := field.Tag.Lookup("dshot")
+	if !ok {
+		return t, nil
+	}
+	if tag == "-" {
+		t.skip = true
+		return t, nil
+	}
+
+	for opt := range strings.SplitSeq(tag, ",") {
+		key, value, hasValue := strings.Cut(strings.TrimSpace(opt), "=")
+		switch {
+		case (key == "name" || key == "token" || key == "value") && value != "":
+			t.name = value
+		case key == "optional" && !hasValue:
+			t.optional = true
+		default:
+			return t, fmt.Errorf("invalid dshot tag option %q", opt)
+		}
+	}
+
+	return t, nil
+}
+
+// lookupNamed finds the token registration named name whose type is assignable to
+// targetType, checking parents if it is not registered locally. Pending lazy modules
+// providing a token of that name are loaded until it is found.
+func (c *Container) lookupNamed(name string, targetType reflect.Type) (*entry, error) {
+	for {
+		e, err := c.findNamed(name, targetType)
+		if e != nil || err != nil {
+			return e, err
+		}
+		loaded, err := c.loadModuleForName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !loaded {
+			return nil, fmt.Errorf("%w: token %q", ErrNotFound, name)
+		}
+	}
+}
+
+// findNamed is lookupNamed over already registered entries
+func (c *Container) findNamed(name string, targetType reflect.Type) (*entry, error) {
+	// Same-named tokens of other types do not make the lookup ambiguous
+	var found, mismatch *entry
+
+	c.mu.RLock()
+	for _, e := range c.entries {
+		if e.typeBased() {
+			continue
+		}
+		if tokenName(e.token) != name {
+			continue
+		}
+		if e.depType == nil || !e.depType.AssignableTo(targetType) {
+			mismatch = e
+			continue
+		}
+		if found != nil {
+			c.mu.RUnlock()
+			return nil, fmt.Errorf("%w for token name %q", ErrAmbiguous, name)
+		}
+		found = e
+	}
+	c.mu.RUnlock()
+
+	if found != nil {
+		return found, nil
+	}
+
+	if c.parent != nil && c.allowsFromParent(targetType) {
+		if e, err := c.parent.findNamed(name, targetType); e != nil || err != nil || mismatch == nil {
+			return e, err
+		}
+	}
+	if mismatch != nil {
+		return nil, fmt.Errorf("token %q has type %v, not assignable to %s", name, mismatch.depType, targetType)
+	}
+
+	return nil, nil
+}
+
+// resolveNamed resolves the value of a named field, leaving optional and Optional fields
+// empty if the name is not registered
+func (c *Container) resolveNamed(name string, fieldType reflect.Type, optionalTag bool, r *resolution) (reflect.Value, error) {
+	targetType := fieldType
+	elem, isOptional := optionalElem(fieldType)
+	if isOptional {
+		targetType = elem
+	}
+
+	e, err := c.lookupNamed(name, targetType)
+	if err != nil {
+		if (optionalTag || isOptional) && errors.Is(err, ErrNotFound) {
+			return reflect.Zero(fieldType), nil
+		}
+		return reflect.Value{}, err
+	}
+
+	val, err := c.resolveEntry(e, r)
+	if err != nil {
+		return reflect.Value{}, withStep(err, tokenStep(e.token))
+	}
+
+	if isOptional {
+		opt := reflect.New(fieldType)
+		opt.Interface().(optional).set(val)
+		return opt.Elem(), nil
+	}
+
+	return reflect.ValueOf(val), nil
+}
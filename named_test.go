@@ -0,0 +1,155 @@
+package dshot_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/overdevelop/dshot"
+)
+
+type replicatedStore struct {
+	Primary *Database `dshot:"name=primary-db"`
+	Replica *Database `dshot:"name=replica-db"`
+}
+
+func TestNamedFields(t *testing.T) {
+	c := dshot.New()
+	c.Register(
+		dshot.Bind(dshot.NewToken[*Database]("primary-db"), &Database{ConnectionString: "primary"}),
+		dshot.Bind(dshot.NewToken[*Database]("replica-db"), &Database{ConnectionString: "replica"}),
+	)
+	dshot.ProvideAutoFactory(func(deps replicatedStore) *Repository {
+		return &Repository{DB: deps.Replica}
+	}, c)
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	if db := dshot.MustResolve[*Repository](c).DB; db.ConnectionString != "replica" {
+		t.Errorf("Expected replica database, got %q", db.ConnectionString)
+	}
+
+	var store replicatedStore
+	c.Inject(&store)
+	if store.Primary.ConnectionString != "primary" {
+		t.Errorf("Expected primary database, got %q", store.Primary.ConnectionString)
+	}
+}
+
+func TestNamedFields_Missing(t *testing.T) {
+	c := dshot.New()
+	c.Register(dshot.Bind(dshot.NewToken[*Database]("primary-db"), &Database{}))
+
+	var store replicatedStore
+	if err := c.InjectE(&store); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for replica-db, got %v", err)
+	}
+
+	var optional struct {
+		Replica dshot.Optional[*Database] `dshot:"name=replica-db"`
+	}
+	if err := c.InjectE(&optional); err != nil {
+		t.Errorf("Expected empty Optional, got %v", err)
+	}
+}
+
+func TestProvideNamed_Primitives(t *testing.T) {
+	type DBParams struct {
+		dshot.In
+
+		DSN     string        `dshot:"value=db.dsn"`
+		Timeout time.Duration `dshot:"value=db.timeout,optional"`
+		Port    int           `dshot:"name=db.port"`
+	}
+
+	c := dshot.New()
+	c.ProvideNamed("db.dsn", "postgres://localhost/app")
+	c.ProvideNamed("db.port", 5432)
+	dshot.ProvideAutoFactory(func(p DBParams) *Database {
+		return &Database{ConnectionString: fmt.Sprintf("%s:%d/%s", p.DSN, p.Port, p.Timeout)}
+	}, c)
+
+	if db := dshot.MustResolve[*Database](c); db.ConnectionString != "postgres://localhost/app:5432/0s" {
+		t.Errorf("Unexpected connection string '%s'", db.ConnectionString)
+	}
+	if port := dshot.Value[int]("db.port", c); port != 5432 {
+		t.Errorf("Expected 5432, got %d", port)
+	}
+	if _, err := dshot.ValueE[string]("db.port", c); err == nil {
+		t.Error("Expected a type error for an int value read as string")
+	}
+	if _, err := dshot.ValueE[string]("missing", c); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestInject_TagControls(t *testing.T) {
+	type target struct {
+		Primary *Database   `dshot:"token=primary-db"`
+		Service *Service    `dshot:"optional"`
+		Repo    *Repository `dshot:"-"`
+	}
+
+	c := dshot.New()
+	c.Register(dshot.Bind(dshot.NewToken[*Database]("primary-db"), &Database{ConnectionString: "primary"}))
+
+	var got target
+	if err := c.InjectE(&got); err != nil {
+		t.Fatalf("Expected skipped and optional fields not to fail, got %v", err)
+	}
+	if got.Primary == nil || got.Primary.ConnectionString != "primary" || got.Service != nil || got.Repo != nil {
+		t.Errorf("Unexpected injection %+v", got)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Expected no validation error, got %v", err)
+	}
+}
+
+func TestInject_IncludeUnexported(t *testing.T) {
+	type service struct {
+		db      *Database
+		primary *Service `dshot:"token=primary"`
+		repo    *Repository
+		calls   int
+	}
+
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "default"})
+	c.Register(dshot.Bind(dshot.NewToken[*Service]("primary"), &Service{Name: "primary"}))
+
+	var plain service
+	c.Inject(&plain)
+	if plain.db != nil {
+		t.Error("Expected unexported fields untouched without IncludeUnexported")
+	}
+
+	svc := service{calls: 3}
+	c.Inject(dshot.IncludeUnexported(&svc))
+	if svc.db == nil || svc.db.ConnectionString != "default" {
+		t.Errorf("Expected the unexported dependency injected, got %+v", svc.db)
+	}
+	if svc.primary == nil || svc.primary.Name != "primary" {
+		t.Errorf("Expected the tagged unexported field resolved by token, got %+v", svc.primary)
+	}
+	if svc.repo != nil || svc.calls != 3 {
+		t.Errorf("Expected unresolvable unexported fields left as is, got %+v", svc)
+	}
+}
+
+func TestInject_SameNameDifferentTypes(t *testing.T) {
+	c := dshot.New()
+	c.Register(
+		dshot.Bind(dshot.NewToken[*Database]("orders"), &Database{ConnectionString: "orders"}),
+		dshot.Bind(dshot.NewToken[*Service]("orders"), &Service{Name: "orders"}),
+	)
+
+	var deps struct {
+		DB *Database `dshot:"name=orders"`
+	}
+	if err := c.InjectE(&deps); err != nil || deps.DB.ConnectionString != "orders" {
+		t.Errorf("Expected the token of the field type picked, got %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	namespaces        sync.Map    // Namespace name -> *Container
+	packageNamespaces sync.Map    // Package path -> namespace name, see NamespacePackages
+	packagesRouted    atomic.Bool // NamespacePackages was called
+)
+
+// WithNamespace places the container in an isolated namespace, so that independent copies
+// of wiring, such as two embedded products, can coexist in one process. Scopes inherit the
+// namespace, and a container cannot be the child of one in another namespace: types and
+// token names shared across namespaces must go through Bridge.
+//
+// Example:
+//
+//	billing := container.New(container.WithNamespace("billing"))
+func WithNamespace(name string) Option {
+	return func(c *Container) {
+		c.namespace = name
+	}
+}
+
+// Namespace returns the root container of the named namespace, created on first use. It is
+// the per-namespace counterpart of the global container: embedded libraries calling
+// Namespace with their own name never see each other's registrations.
+//
+// Example:
+//
+//	func init() {
+//	    container.Namespace("search").Provide(NewIndex())
+//	}
+func Namespace(name string) *Container {
+	if name == "" {
+		panic("Namespace: name cannot be empty")
+	}
+	if c, ok := namespaces.Load(name); ok {
+		return c.(*Container)
+	}
+	c, _ := namespaces.LoadOrStore(name, New(WithNamespace(name)))
+	return c.(*Container)
+}
+
+// NamespacePackages routes the package-level helpers (Provide, Register, ProvideAutoFactory,
+// Resolve, Default, ...) called without a container from the listed packages, and the
+// packages under them, to Namespace(name) instead of the global container, so embedded
+// libraries relying on the global helpers never see each other's registrations.
+//
+// Example:
+//
+//	func init() {
+//	    container.NamespacePackages("search", "example.com/search")
+//	}
+func NamespacePackages(name string, pkgPaths ...string) {
+	Namespace(name) // Validates the name
+
+	for _, pkgPath := range pkgPaths {
+		packageNamespaces.Store(strings.TrimSuffix(pkgPath, "/"), name)
+	}
+	packagesRouted.Store(true)
+}
+
+// global returns the container package-level helpers fall back to: the namespace of the
+// calling package if it is routed by NamespacePackages, or the global container
+func global() *Container {
+	if !packagesRouted.Load() {
+		return defaultContainer
+	}
+
+	for pkg := callerPackage(); pkg != ""; {
+		if name, ok := packageNamespaces.Load(pkg); ok {
+			return Namespace(name.(string))
+		}
+		i := strings.LastIndex(pkg, "/")
+		if i < 0 {
+			break
+		}
+		pkg = pkg[:i]
+	}
+	return defaultContainer
+}
+
+// callerPackage returns the package path of the first caller outside this package
+func callerPackage() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkgPrefix) {
+			// Function names are the package path, a dot and the (possibly qualified) name
+			fn := frame.Function
+			slash := strings.LastIndex(fn, "/") + 1
+			if dot := strings.Index(fn[slash:], "."); dot >= 0 {
+				return fn[:slash+dot]
+			}
+			return fn
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// checkNamespace panics if c and its parent belong to different namespaces
+func (c *Container) checkNamespace() {
+	if c.namespace != c.parent.namespace {
+		panic(fmt.Sprintf("container in namespace %q cannot be a child of namespace %q; use Bridge to share registrations",
+			c.namespace, c.parent.namespace))
+	}
+}
+
+// Bridge makes shared registrations of from resolvable in to, typically across namespaces.
+// Each shared item is a token or a reflect.Type; resolving it from to resolves it from from,
+// which keeps owning and disposing the instance. Nothing else crosses the bridge.
+// It panics if to already registers a shared token or type, including through an earlier
+// Bridge, instead of making it ambiguous.
+//
+// Example:
+//
+//	search := container.Namespace("search")
+//	billing := container.Namespace("billing")
+//	container.Bridge(search, billing, reflect.TypeFor[*Tracer](), metricsToken)
+func Bridge(from, to *Container, shared ...any) {
+	if from == nil || to == nil {
+		panic("Bridge: containers cannot be nil")
+	}
+
+	for _, item := range shared {
+		var token any
+		var depType reflect.Type
+		var resolve func(r *resolution) (any, error)
+
+		if typ, ok := item.(reflect.Type); ok {
+			token = &tokenKey{key: providedKey(typ)}
+			depType = typ
+			resolve = func(r *resolution) (any, error) {
+				val, ok, err := from.resolveType(typ, r)
+				if err == nil && !ok {
+					err = fmt.Errorf("%w: %s in the bridged container", ErrNotFound, typ)
+				}
+				return val, err
+			}
+		} else {
+			e, ok, err := from.getEntry(item)
+			if err != nil {
+				panic(fmt.Sprintf("Bridge: %v", err))
+			}
+			if !ok {
+				panic(fmt.Sprintf("Bridge: %s is not registered", tokenName(item)))
+			}
+			token = item
+			depType = e.depType
+			resolve = func(r *resolution) (any, error) {
+				return from.resolveEntry(e, r)
+			}
+		}
+
+		// A prototype that delegates on every resolution, owning nothing itself
+		to.mu.Lock()
+		if to.registersLocally(token, depType) {
+			to.mu.Unlock()
+			panic(fmt.Sprintf("Bridge: %s is already registered in the target container", tokenName(item)))
+		}
+		to.addEntry(token, &entry{
+			factory:    resolve,
+			lifecycle:  Prototype,
+			depType:    depType,
+			ownCleanup: true,
+		})
+		to.mu.Unlock()
+	}
+}
+
+// registersLocally reports whether c has a registration under token, or of depType if
+// token is a type-based one. Callers must hold c.mu.
+func (c *Container) registersLocally(token any, depType reflect.Type) bool {
+	if _, ok := c.registry[token]; ok {
+		return true
+	}
+	if _, ok := token.(*tokenKey); !ok {
+		return false
+	}
+	for _, e := range c.entries {
+		if e.depType == depType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package dshot_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestNamespace_IsolatesAndBridges(t *testing.T) {
+	search := dshot.Namespace("search-test")
+	billing := dshot.Namespace("billing-test")
+	if dshot.Namespace("search-test") != search {
+		t.Fatal("Expected the same root container for a namespace")
+	}
+
+	dbToken := dshot.NewToken[*Database]("db")
+	search.Register(dshot.Bind(dbToken, &Database{ConnectionString: "search"}))
+	dshot.ProvideAutoFactory(func() *Service { return &Service{Name: "tracer"} }, search)
+	search.Provide(&Repository{})
+
+	if _, err := dshot.ResolveE[*Service](billing); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected namespaces isolated, got %v", err)
+	}
+
+	dshot.Bridge(search, billing, reflect.TypeFor[*Service](), dbToken)
+	if svc := dshot.MustResolve[*Service](billing); svc != dshot.MustResolve[*Service](search) {
+		t.Error("Expected the bridged singleton shared with its namespace")
+	}
+	if db := dshot.Get(dbToken, billing); db.ConnectionString != "search" {
+		t.Errorf("Expected the bridged token, got '%s'", db.ConnectionString)
+	}
+	if _, err := dshot.ResolveE[*Repository](billing); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected unshared types kept isolated, got %v", err)
+	}
+	if !panics(func() { dshot.Bridge(search, billing, reflect.TypeFor[*Service]()) }) {
+		t.Error("Expected bridging a type twice to panic")
+	}
+	if !panics(func() { dshot.Bridge(search, billing, dbToken) }) {
+		t.Error("Expected bridging a token twice to panic")
+	}
+	if svc := dshot.MustResolve[*Service](billing); svc.Name != "tracer" {
+		t.Errorf("Expected the bridged type still unambiguous, got '%s'", svc.Name)
+	}
+
+	if info := dshot.NewScoped(billing).ScopeInfo(); info.Namespace != "billing-test" {
+		t.Errorf("Expected scopes to inherit the namespace, got %q", info.Namespace)
+	}
+
+	defer func() {
+		if msg := fmt.Sprint(recover()); !strings.Contains(msg, "use Bridge") {
+			t.Errorf("Expected a cross-namespace parent to panic, got %q", msg)
+		}
+	}()
+	dshot.New(dshot.WithParent(search), dshot.WithNamespace("billing-test"))
+}
+
+func TestNamespacePackages_RoutesGlobalHelpers(t *testing.T) {
+	// NamespacePackages cannot be undone, so it runs in a child test process
+	if os.Getenv("DSHOT_NAMESPACE_PACKAGES") != "1" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestNamespacePackages_RoutesGlobalHelpers$")
+		cmd.Env = append(os.Environ(), "DSHOT_NAMESPACE_PACKAGES=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Child process failed: %v\n%s", err, out)
+		}
+		return
+	}
+
+	dshot.NamespacePackages("embedded", reflect.TypeFor[Service]().PkgPath())
+
+	dshot.Provide(&Service{Name: "embedded"})
+	dshot.ProvideAutoFactory(func(svc *Service) *Repository { return &Repository{} })
+
+	if dshot.Default() != dshot.Namespace("embedded") {
+		t.Error("Expected Default to return the package's namespace")
+	}
+	if svc := dshot.MustResolve[*Service](dshot.Namespace("embedded")); svc.Name != "embedded" {
+		t.Errorf("Expected the registration in the namespace, got '%s'", svc.Name)
+	}
+	if _, ok := dshot.Resolve[*Repository](dshot.Namespace("embedded")); !ok {
+		t.Error("Expected auto factories registered in the namespace")
+	}
+}
@@ -0,0 +1,51 @@
+package dshot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNilValue is returned when a factory returns nil in a container created with WithNilGuard
+var ErrNilValue = errors.New("nil value")
+
+// WithNilGuard rejects nil pointers and nil interfaces entering the graph: registering one
+// with Provide, Bind or a value registration panics, and a factory returning one fails the
+// resolution with ErrNilValue. Both report where the registration was made, instead of
+// leaving the nil to surface at a distant call site. Scopes inherit the guard.
+//
+// Example:
+//
+//	c := container.New(container.WithNilGuard())
+//	container.ProvideAutoFactory(func() *Cache { return nil }, c)
+//	_, err := container.ResolveE[*Cache](c)
+//	// *app.Cache: factory returned nil (registered at main.go:42): nil value
+func WithNilGuard() Option {
+	return func(c *Container) {
+		c.nilGuard = true
+	}
+}
+
+// isNilValue reports whether v is a nil interface or a nil pointer
+func isNilValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// guardRegistration rejects nil values. Callers must hold c.mu.
+func (c *Container) guardRegistration(e *entry) {
+	if e.factory == nil && e.variants == nil && isNilValue(e.value) {
+		panic(fmt.Sprintf("%s: cannot register a nil value (registered at %s)", e.label(), e.registrationSite()))
+	}
+}
+
+// guardBuilt returns an error if the factory of e returned nil
+func (e *entry) guardBuilt(val any) error {
+	if e.owner == nil || !e.owner.nilGuard || !isNilValue(val) {
+		return nil
+	}
+	return fmt.Errorf("%s: factory returned nil (registered at %s): %w", e.label(), e.registrationSite(), ErrNilValue)
+}
@@ -0,0 +1,41 @@
+package dshot_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestWithNilGuard_RejectsProvidedNil(t *testing.T) {
+	c := dshot.New(dshot.WithNilGuard())
+
+	defer func() {
+		msg := fmt.Sprint(recover())
+		if !strings.Contains(msg, "cannot register a nil value") || !strings.Contains(msg, "nilguard_test.go:") {
+			t.Errorf("Expected a panic with the registration site, got %q", msg)
+		}
+	}()
+
+	var db *Database
+	c.Provide(db)
+}
+
+func TestWithNilGuard_RejectsFactoryNil(t *testing.T) {
+	c := dshot.New(dshot.WithNilGuard())
+	dshot.ProvideAutoFactory(func() *Database { return nil }, c)
+
+	scope := dshot.NewScoped(c)
+	_, err := dshot.ResolveE[*Database](scope)
+	if !errors.Is(err, dshot.ErrNilValue) || !strings.Contains(err.Error(), "registered at ") {
+		t.Errorf("Expected ErrNilValue with the registration site, got %v", err)
+	}
+
+	unguarded := dshot.New()
+	dshot.ProvideAutoFactory(func() *Database { return nil }, unguarded)
+	if _, err := dshot.ResolveE[*Database](unguarded); err != nil {
+		t.Errorf("Expected nil allowed without the guard, got %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build dshot_noglobal
+
+package dshot
+
+// Built with -tags dshot_noglobal, the global container is unavailable from the start
+func init() {
+	DisableGlobal()
+}
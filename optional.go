@@ -0,0 +1,81 @@
+package dshot
+
+import (
+	"reflect"
+)
+
+// Optional is a dependency that may not be registered. Factory parameters and Inject
+// fields of type Optional[T] receive an empty value instead of failing when T cannot be
+// resolved. Other resolution errors, such as a failing factory, are still reported.
+//
+// Example:
+//
+//	container.ProvideAutoFactory(func(db *sql.DB, tracer container.Optional[*Tracer]) *Repo {
+//	    repo := &Repo{db: db}
+//	    if t, ok := tracer.Get(); ok {
+//	        repo.tracer = t
+//	    }
+//	    return repo
+//	})
+type Optional[T any] struct {
+	value T
+	ok    bool
+}
+
+// Some returns an Optional holding v, e.g. to call a constructor directly in tests
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, ok: true}
+}
+
+// Get returns the value and whether it was resolved
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// OrElse returns the value, or def if it was not resolved
+func (o Optional[T]) OrElse(def T) T {
+	if !o.ok {
+		return def
+	}
+	return o.value
+}
+
+func (o *Optional[T]) elemType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (o *Optional[T]) set(v any) {
+	o.value, _ = v.(T)
+	o.ok = true
+}
+
+// optional is implemented by *Optional[T]
+type optional interface {
+	elemType() reflect.Type
+	set(v any)
+}
+
+var optionalIface = reflect.TypeFor[optional]()
+
+// optionalElem returns T if typ is Optional[T]
+func optionalElem(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() != reflect.Struct || !reflect.PointerTo(typ).Implements(optionalIface) {
+		return nil, false
+	}
+	return reflect.New(typ).Interface().(optional).elemType(), true
+}
+
+// resolveOptional resolves an Optional[T] of type typ, leaving it empty if T is not registered
+func (c *Container) resolveOptional(typ, elem reflect.Type, r *resolution) (any, error) {
+	val, ok, err := c.resolveType(elem, r)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := reflect.New(typ)
+	if ok {
+		opt.Interface().(optional).set(val)
+	}
+
+	return opt.Elem().Interface(), nil
+}
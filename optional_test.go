@@ -0,0 +1,67 @@
+package dshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type tracer struct{ name string }
+
+type tracedRepo struct {
+	tracer dshot.Optional[*tracer]
+}
+
+func TestOptional_Parameter(t *testing.T) {
+	c := dshot.New()
+	dshot.ProvideAutoFactory(func(tr dshot.Optional[*tracer]) *tracedRepo {
+		return &tracedRepo{tracer: tr}
+	}, c)
+
+	if _, ok := dshot.MustResolve[*tracedRepo](c).tracer.Get(); ok {
+		t.Error("Expected empty Optional when nothing is registered")
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Expected Optional parameters to validate, got %v", err)
+	}
+
+	scope := dshot.NewScoped(c)
+	scope.Provide(&tracer{name: "otel"})
+	dshot.ProvideAutoPrototype(func(tr dshot.Optional[*tracer]) *tracedRepo {
+		return &tracedRepo{tracer: tr}
+	}, scope)
+
+	if tr := dshot.MustResolve[*tracedRepo](scope).tracer.OrElse(nil); tr == nil || tr.name != "otel" {
+		t.Errorf("Expected registered tracer, got %v", tr)
+	}
+}
+
+func TestOptional_Field(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "localhost"})
+
+	var deps struct {
+		DB     dshot.Optional[*Database]
+		Tracer dshot.Optional[*tracer]
+	}
+	c.Inject(&deps)
+
+	if db, ok := deps.DB.Get(); !ok || db.ConnectionString != "localhost" {
+		t.Errorf("Expected database, got %v", db)
+	}
+	if _, ok := deps.Tracer.Get(); ok {
+		t.Error("Expected empty Optional for unregistered tracer")
+	}
+}
+
+func TestOptional_FactoryErrorStillFails(t *testing.T) {
+	c := dshot.New()
+	errDown := errors.New("collector down")
+	dshot.ProvideAutoFactoryErr(func() (*tracer, error) { return nil, errDown }, c)
+
+	var deps struct{ Tracer dshot.Optional[*tracer] }
+	if err := c.InjectE(&deps); !errors.Is(err, errDown) {
+		t.Errorf("Expected factory error, got %v", err)
+	}
+}
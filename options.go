@@ -0,0 +1,132 @@
+package dshot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Option configures a container created with New
+type Option func(*Container)
+
+// WithSilentDiagnostics disables diagnostic warnings (such as similar-type fallbacks)
+// for the container and its scopes. Warning messages are not even formatted,
+// which keeps hot resolution paths free of fmt and slog overhead.
+//
+// Example:
+//
+//	c := container.New(container.WithSilentDiagnostics())
+func WithSilentDiagnostics() Option {
+	return func(c *Container) {
+		c.silent = true
+	}
+}
+
+// WithReadinessGate makes context-aware resolutions (GetCtx, ResolveCtx, CallContext, ...)
+// of lifecycle-managed registrations wait until Start has completed, so handlers never
+// observe a component whose start hook has not run yet. A registration is lifecycle-managed
+// when its auto-wired factory takes *LifecycleHooks. Waiting ends early with the error of
+// the resolution context. Plain Get and Resolve, used to wire the application before
+// Start, are not gated.
+//
+// Example:
+//
+//	c := container.New(container.WithReadinessGate())
+//	// In handlers:
+//	cache := container.MustResolveCtx[*Cache](r.Context()) // Blocks until c.Start returns
+func WithReadinessGate() Option {
+	return func(c *Container) {
+		c.ready = make(chan struct{})
+	}
+}
+
+// WithBaseContext sets the context passed to factories taking a context.Context parameter
+// when they are resolved without one, as by Get, Resolve and Call. Context-aware calls
+// (GetCtx, ResolveCtx, CallContext, ...) still pass their own context. Scopes inherit
+// the base context.
+//
+// Example:
+//
+//	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer cancel()
+//	c := container.New(container.WithBaseContext(ctx))
+func WithBaseContext(ctx context.Context) Option {
+	return func(c *Container) {
+		c.baseCtx = ctx
+	}
+}
+
+// WithLogger sets the logger receiving the container's diagnostic warnings. Without it,
+// warnings go to slog.Default(), so the host application's logging setup applies.
+// Scopes inherit it.
+//
+// Example:
+//
+//	c := container.New(container.WithLogger(slog.New(slog.NewJSONHandler(os.Stderr, nil))))
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Container) {
+		c.logger = l
+	}
+}
+
+// WithRegistrationSites records where each registration is made, so that type mismatch
+// errors and duplicate registration errors name the file and line to fix. Capturing the
+// call stack slows every registration, so sites are only recorded with this option or
+// WithNilGuard; NewForTest and NewForDev enable it. Scopes inherit the setting.
+//
+// Example:
+//
+//	c := container.New(container.WithRegistrationSites())
+func WithRegistrationSites() Option {
+	return func(c *Container) {
+		c.sites = true
+	}
+}
+
+// WithParent makes the new container a child of parent, resolving what it does not hold
+// from it like a scope created with NewScoped. The child starts with the parent's settings
+// (logger, strictness, profiles, ...), which options after WithParent override.
+//
+// Example:
+//
+//	tenant := container.New(container.WithParent(app), container.WithActiveProfiles("eu"))
+func WithParent(parent *Container) Option {
+	if parent == nil {
+		panic("WithParent: parent container cannot be nil")
+	}
+	return func(c *Container) {
+		c.parent = parent
+		c.inherit(parent)
+	}
+}
+
+// warnEnabled reports whether diagnostic warnings should be built and reported
+func (c *Container) warnEnabled() bool {
+	if c.silent {
+		return false
+	}
+	return c.warningHandler() != nil || c.log().Enabled(context.Background(), slog.LevelWarn)
+}
+
+// log returns the logger of the container's diagnostics
+func (c *Container) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return slog.Default()
+}
+
+// inherit copies the settings a child container takes from its parent
+func (c *Container) inherit(parent *Container) {
+	c.namespace = parent.namespace
+	c.maxScopeDepth.Store(parent.maxScopeDepth.Load())
+	c.silent = parent.silent
+	c.strict = parent.strict
+	c.nilGuard = parent.nilGuard
+	c.sites = parent.sites
+	c.profiles = parent.profiles
+	c.drift = parent.drift
+	c.duplicates = parent.duplicates
+	c.baseCtx = parent.baseCtx
+	c.logger = parent.logger
+	c.postConstructHook = parent.postConstructHook
+}
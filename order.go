@@ -0,0 +1,64 @@
+package dshot
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Orderer is implemented by values that set their position among the results of
+// ResolveAll, []T parameters and groups: lower orders come first. Registration.WithOrder
+// takes precedence.
+type Orderer interface {
+	Order() int
+}
+
+// WithOrder sets the position of the registration among the results of ResolveAll, []T
+// parameters, ResolveGroup and ResolveTagged: lower orders come first, and registrations
+// with the same order keep registration order (parent registrations after the scope's).
+// Registrations without an order use the value's Order method (see Orderer), else 0.
+//
+// Example:
+//
+//	container.Register(
+//	    container.Bind(recoverToken, Middleware(Recover)).WithOrder(-100), // Outermost
+//	    container.Bind(authToken, Middleware(Auth)).WithOrder(10),
+//	)
+func (r Registration[T]) WithOrder(n int) Registration[T] {
+	r.order = &n
+	return r
+}
+
+// orderedValue is a resolved value with the entry it comes from
+type orderedValue struct {
+	e   *entry
+	val any
+}
+
+// order returns the position of the value, see WithOrder
+func (v orderedValue) order() int {
+	if v.e.order != nil {
+		return *v.e.order
+	}
+	if o, ok := v.val.(Orderer); ok {
+		return o.Order()
+	}
+	return 0
+}
+
+// sortByOrder sorts values by their order, keeping the original order of equal ones
+func sortByOrder(vals []orderedValue) {
+	slices.SortStableFunc(vals, func(a, b orderedValue) int {
+		return cmp.Compare(a.order(), b.order())
+	})
+}
+
+// sortedValues sorts values by their order and returns them
+func sortedValues(vals []orderedValue) []any {
+	sortByOrder(vals)
+
+	sorted := make([]any, len(vals))
+	for i, v := range vals {
+		sorted[i] = v.val
+	}
+	return sorted
+}
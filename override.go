@@ -0,0 +1,201 @@
+package dshot
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Replace registers the registrations in place of those registered in this container
+// under the same tokens, keeping their position in registration order; registrations
+// whose token is new are added. Each replacement happens under the container lock, so
+// concurrent resolutions see either the old entry or the new one. Values already resolved
+// from the old entry, including cached singletons and dependents built from them, are not
+// affected. It is meant for tests and plugin-style overrides.
+//
+// Example:
+//
+//	c.Replace(container.Bind(repoToken, Repository(&fakeRepository{})))
+func (c *Container) Replace(registrations ...registration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.replacing = true
+	defer func() { c.replacing = false }()
+
+	for _, reg := range registrations {
+		reg.registerTo(c)
+	}
+}
+
+// Override provides value in place of the values of the same type registered in this
+// container with Provide, which would otherwise make the type ambiguous. See Replace.
+//
+// Example:
+//
+//	c.Override(&Config{Env: "test"})
+func (c *Container) Override(value any) {
+	typ := reflect.TypeOf(value)
+	if typ == nil {
+		panic("Override: cannot register nil value")
+	}
+
+	e := &entry{
+		value:     value,
+		lifecycle: Singleton,
+		depType:   typ,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.replacing = true
+	defer func() { c.replacing = false }()
+
+	c.addEntry(&tokenKey{key: providedKey(typ)}, e)
+}
+
+// DuplicatePolicy decides what happens when a registration is made under a token, or for
+// a type with Provide, already registered in the same container
+type DuplicatePolicy int
+
+const (
+	// DuplicateAllow keeps both registrations: the token resolves to the latest, and a type
+	// provided twice is ambiguous for Resolve but listed twice by ResolveAll
+	DuplicateAllow DuplicatePolicy = iota
+
+	// DuplicateReplace makes the new registration replace the old one, as Replace does
+	DuplicateReplace
+
+	// DuplicateError panics, reporting where the first registration was made
+	DuplicateError
+)
+
+// WithDuplicatePolicy sets how the container handles duplicate registrations. Scopes
+// inherit the policy; registering in a scope what its parent holds is never a duplicate.
+//
+// Example:
+//
+//	c := container.New(container.WithDuplicatePolicy(container.DuplicateError))
+func WithDuplicatePolicy(policy DuplicatePolicy) Option {
+	return func(c *Container) {
+		c.duplicates = policy
+	}
+}
+
+// duplicateOf returns the local entry e would duplicate under token, or nil. Callers must
+// hold c.mu.
+func (c *Container) duplicateOf(token any) *entry {
+	i := slices.IndexFunc(c.entries, func(old *entry) bool {
+		return sameRegistration(old, token)
+	})
+	if i < 0 {
+		return nil
+	}
+	return c.entries[i]
+}
+
+// sameRegistration reports whether old is registered under token. Type-based registrations
+// match by their type's key.
+func sameRegistration(old *entry, token any) bool {
+	if old.token == token {
+		return true
+	}
+	k, ok := old.token.(*tokenKey)
+	newKey, newOK := token.(*tokenKey)
+	return ok && newOK && k.key == newKey.key && k.key == providedKey(old.depType)
+}
+
+// replaceEntry puts e in place of the local entries registered under token, reporting
+// whether there were any. Callers must hold c.mu.
+func (c *Container) replaceEntry(token any, e *entry) bool {
+	matches := func(old *entry) bool {
+		return sameRegistration(old, token)
+	}
+
+	i := slices.IndexFunc(c.entries, matches)
+	if i < 0 {
+		return false
+	}
+
+	delete(c.registry, c.entries[i].token)
+	c.entries[i].invalidate()
+	c.entries[i] = e
+	c.registry[token] = e
+
+	if c.removeEntries(func(old *entry) bool {
+		return old != e && matches(old)
+	}) == 0 {
+		c.reindex()
+	}
+
+	return true
+}
+
+// Remove unregisters the local registration of token, reporting whether there was one.
+// A singleton it cached is dropped, not disposed: it is still closed with the container.
+// Values already resolved from it are not affected.
+//
+// Example:
+//
+//	c.Remove(featureToken)
+func (c *Container) Remove(token any) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkWritable("Remove")
+
+	return c.removeEntries(func(e *entry) bool {
+		return e.token == token
+	}) > 0
+}
+
+// RemoveType unregisters the local registrations of exactly targetType, type-based and
+// token-based alike, returning how many were removed. See Remove.
+//
+// Example:
+//
+//	c.RemoveType(reflect.TypeFor[*Cache]())
+func (c *Container) RemoveType(targetType reflect.Type) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkWritable("RemoveType")
+
+	return c.removeEntries(func(e *entry) bool {
+		return e.depType == targetType
+	})
+}
+
+// removeEntries unregisters the local entries accepted by match, rebuilding the type
+// index, and returns how many were removed. Callers must hold c.mu.
+func (c *Container) removeEntries(match func(*entry) bool) int {
+	removed, removedIndexed := 0, 0
+	for i, e := range c.entries {
+		if !match(e) {
+			continue
+		}
+		delete(c.registry, e.token)
+		e.invalidate()
+		removed++
+		if i < c.indexed {
+			removedIndexed++
+		}
+	}
+	if removed == 0 {
+		return 0
+	}
+
+	c.entries = slices.DeleteFunc(c.entries, match)
+	c.indexed -= removedIndexed
+	c.reindex()
+
+	return removed
+}
+
+// reindex rebuilds the type index of the indexed entries. Callers must hold c.mu.
+func (c *Container) reindex() {
+	c.typeRegistry = make(map[reflect.Type][]*entry)
+	for _, e := range c.entries[:c.indexed] {
+		c.indexEntry(e)
+	}
+}
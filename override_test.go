@@ -0,0 +1,93 @@
+package dshot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestReplace_SwapsTokenRegistration(t *testing.T) {
+	c := dshot.New()
+	token := dshot.NewToken[*Service]("svc")
+	c.Register(dshot.BindAutoFactory(token, func() *Service { return &Service{Name: "real"} }, c))
+	c.Provide(&Database{})
+
+	c.Replace(dshot.Bind(token, &Service{Name: "fake"}))
+
+	if svc := dshot.Get(token, c); svc.Name != "fake" {
+		t.Errorf("Expected the replacement, got %q", svc.Name)
+	}
+	if svc := dshot.MustResolve[*Service](c); svc.Name != "fake" {
+		t.Errorf("Expected the type index updated, got %q", svc.Name)
+	}
+
+	var tokens []string
+	for info := range c.All() {
+		tokens = append(tokens, info.Token)
+	}
+	if len(tokens) != 2 || tokens[0] != "svc" {
+		t.Errorf("Expected the replacement to keep its position, got %v", tokens)
+	}
+}
+
+func TestOverride_RemovesAmbiguousDuplicates(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "a"})
+	c.Provide(&Database{ConnectionString: "b"})
+	c.Provide(&Service{})
+
+	c.Override(&Database{ConnectionString: "test"})
+
+	db, err := dshot.ResolveE[*Database](c)
+	if err != nil || db.ConnectionString != "test" {
+		t.Fatalf("Expected the override, got %v, %v", db, err)
+	}
+	if n := len(dshot.ResolveAll[*Database](c)); n != 1 {
+		t.Errorf("Expected a single database registration, got %d", n)
+	}
+
+	c.Override(&Repository{})
+	if _, ok := dshot.Resolve[*Repository](c); !ok {
+		t.Error("Expected Override to add a missing registration")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	c := dshot.New()
+	token := dshot.NewToken[*Service]("svc")
+	built := 0
+	c.Register(dshot.BindAutoFactory(token, func() *Service {
+		built++
+		return &Service{}
+	}, c))
+	c.Provide(&Database{})
+	c.Provide(&Database{})
+
+	dshot.Get(token, c)
+	if !c.Remove(token) || c.Remove(token) {
+		t.Fatal("Expected Remove to report a single removal")
+	}
+	if _, err := dshot.GetE(token, c); err == nil {
+		t.Error("Expected the token to be unregistered")
+	}
+	if _, ok := dshot.Resolve[*Service](c); ok {
+		t.Error("Expected the type index updated")
+	}
+
+	c.Register(dshot.BindAutoFactory(token, func() *Service {
+		built++
+		return &Service{}
+	}, c))
+	dshot.Get(token, c)
+	if built != 2 {
+		t.Errorf("Expected the cached singleton not reused, got %d builds", built)
+	}
+
+	if n := c.RemoveType(reflect.TypeFor[*Database]()); n != 2 {
+		t.Errorf("Expected both databases removed, got %d", n)
+	}
+	if _, ok := dshot.Resolve[*Database](c); ok {
+		t.Error("Expected *Database unregistered")
+	}
+}
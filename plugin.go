@@ -0,0 +1,106 @@
+package dshot
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Registry is a keyed lookup table of T implementations built from container registrations.
+// It is the usual shape of a plugin system: every implementation is registered independently
+// and the registry indexes them by a key derived from registration metadata.
+type Registry[K comparable, T any] struct {
+	c     *Container
+	key   func(RegistrationInfo) (K, bool)
+	mu    sync.RWMutex
+	items map[K]T
+	keys  []K
+}
+
+// NewRegistry builds a registry of all registrations of type T for which key returns true.
+// When several registrations share a key, the first one registered wins.
+//
+// Example:
+//
+//	codecs := container.NewRegistry[string, Codec](container.ByCapability("content-type"))
+//	codec, ok := codecs.Lookup("application/json")
+func NewRegistry[K comparable, T any](key func(RegistrationInfo) (K, bool), containers ...*Container) *Registry[K, T] {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "NewRegistry")
+
+	r := &Registry[K, T]{
+		c:   c,
+		key: key,
+	}
+	r.Reload()
+
+	return r
+}
+
+// Lookup returns the implementation registered under k
+func (r *Registry[K, T]) Lookup(k K) (T, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	val, ok := r.items[k]
+	return val, ok
+}
+
+// Keys returns the registered keys in registration order
+func (r *Registry[K, T]) Keys() []K {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]K, len(r.keys))
+	copy(keys, r.keys)
+	return keys
+}
+
+// Reload rebuilds the registry from the container's current registrations.
+// Lookups running concurrently keep seeing the previous set until the rebuild completes.
+func (r *Registry[K, T]) Reload() {
+	targetType := reflect.TypeFor[T]()
+
+	items := make(map[K]T)
+	var keys []K
+
+	for _, cand := range r.c.candidates(targetType, nil) {
+		k, ok := r.key(cand.e.info())
+		if !ok {
+			continue
+		}
+		if _, exists := items[k]; exists {
+			continue
+		}
+
+		val, ok, err := cand.resolve(r.c, targetType, nil)
+		if err != nil {
+			panic(err)
+		}
+		if !ok {
+			continue
+		}
+
+		items[k] = mustTyped(typedEntry[T](val, cand.e))
+		keys = append(keys, k)
+	}
+
+	r.mu.Lock()
+	r.items = items
+	r.keys = keys
+	r.mu.Unlock()
+}
+
+// ByCapability keys registrations by the value of the named capability
+func ByCapability(name string) func(RegistrationInfo) (string, bool) {
+	return func(info RegistrationInfo) (string, bool) {
+		v, ok := info.Capabilities[name]
+		return v, ok
+	}
+}
+
+// ByToken keys registrations by their token name
+func ByToken() func(RegistrationInfo) (string, bool) {
+	return func(info RegistrationInfo) (string, bool) {
+		return info.Token, true
+	}
+}
@@ -0,0 +1,50 @@
+package dshot_test
+
+import (
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestRegistry_LookupAndKeys(t *testing.T) {
+	c := dshot.New()
+	c.Register(
+		dshot.Bind(dshot.NewToken[*Service]("json"), &Service{Name: "json"}).WithCapability("format", "json"),
+		dshot.Bind(dshot.NewToken[*Service]("xml"), &Service{Name: "xml"}).WithCapability("format", "xml"),
+		dshot.Bind(dshot.NewToken[*Service]("plain"), &Service{Name: "plain"}),
+	)
+
+	registry := dshot.NewRegistry[string, *Service](dshot.ByCapability("format"), c)
+
+	keys := registry.Keys()
+	if len(keys) != 2 || keys[0] != "json" || keys[1] != "xml" {
+		t.Fatalf("Unexpected keys: %v", keys)
+	}
+
+	svc, ok := registry.Lookup("xml")
+	if !ok {
+		t.Fatal("Expected 'xml' to be registered")
+	}
+	if svc.Name != "xml" {
+		t.Errorf("Expected 'xml', got '%s'", svc.Name)
+	}
+}
+
+func TestRegistry_Reload(t *testing.T) {
+	c := dshot.New()
+	c.Register(dshot.Bind(dshot.NewToken[*Service]("first"), &Service{Name: "first"}))
+
+	registry := dshot.NewRegistry[string, *Service](dshot.ByToken(), c)
+
+	c.Register(dshot.Bind(dshot.NewToken[*Service]("second"), &Service{Name: "second"}))
+
+	if _, ok := registry.Lookup("second"); ok {
+		t.Fatal("Registry should not see registrations added after build")
+	}
+
+	registry.Reload()
+
+	if _, ok := registry.Lookup("second"); !ok {
+		t.Error("Registry should see new registrations after Reload")
+	}
+}
@@ -0,0 +1,116 @@
+package dshot
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// NewForTest creates a container with defaults suited to tests: strict types, fail-fast
+// validation, recorded registration sites and no diagnostic warnings. Containers are isolated, so nothing resolved
+// through it falls back to the global container; combine with DisableGlobal (or the
+// dshot_noglobal build tag) to also catch helpers called without a container. Further
+// options are applied after the preset's.
+//
+// Example:
+//
+//	func TestCheckout(t *testing.T) {
+//	    c := container.NewForTest()
+//	    c.Provide(&fakePayments{})
+//	    svc := container.MustResolve[*Checkout](c) // Panics if anything in the graph is unresolvable
+//	}
+func NewForTest(opts ...Option) *Container {
+	return New(append([]Option{WithStrictTypes(), WithFailFast(), WithRegistrationSites(), WithSilentDiagnostics()}, opts...)...)
+}
+
+// NewForDev creates a container with defaults suited to local development: every factory
+// call is traced to the default slog logger, registration sites are recorded and
+// diagnostic warnings are enabled. Further
+// options are applied after the preset's.
+//
+// Example:
+//
+//	c := container.NewForDev()
+func NewForDev(opts ...Option) *Container {
+	return New(append([]Option{WithTracing(slog.Default()), WithRegistrationSites()}, opts...)...)
+}
+
+// WithStrictTypes disables similar-type resolution: a *T is never served by a T
+// registration or the other way around, so a shared *Config is never silently copied.
+// Such a lookup fails with ErrSimilarType (which also matches ErrNotFound) instead of
+// logging a warning; multi-value resolutions skip similar types. Scopes inherit the setting.
+//
+// Example:
+//
+//	c := container.New(container.WithStrictTypes())
+func WithStrictTypes() Option {
+	return func(c *Container) {
+		c.strict = true
+	}
+}
+
+// WithFailFast validates the whole graph (see Validate) on the first resolution from the
+// container or its scopes. If validation fails, that resolution and every later one fail
+// with the validation error, so wiring mistakes surface at once rather than on the code
+// path that happens to hit them. Registrations must therefore be complete before anything
+// is resolved.
+//
+// Example:
+//
+//	c := container.New(container.WithFailFast())
+func WithFailFast() Option {
+	return func(c *Container) {
+		c.failFast = &failFast{}
+	}
+}
+
+// WithTracing logs every factory call of the container with its duration and error,
+// at debug level on success and error level on failure.
+//
+// Example:
+//
+//	c := container.New(container.WithTracing(slog.Default()))
+func WithTracing(logger *slog.Logger) Option {
+	return func(c *Container) {
+		c.interceptors = append(c.interceptors, func(info RegistrationInfo, next func() (any, error)) (any, error) {
+			start := time.Now()
+			val, err := next()
+
+			attrs := []any{
+				slog.String("token", info.Token),
+				slog.String("lifecycle", info.Lifecycle.String()),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				logger.Error("dshot: factory failed", append(attrs, slog.Any("error", err))...)
+			} else {
+				logger.Debug("dshot: factory called", attrs...)
+			}
+
+			return val, err
+		})
+	}
+}
+
+// failFast holds the outcome of the validation run by WithFailFast
+type failFast struct {
+	once sync.Once
+	err  error
+}
+
+// checkFailFast validates the graph of a WithFailFast container on first use
+func (c *Container) checkFailFast() error {
+	root := c.root()
+	if root.failFast == nil {
+		return nil
+	}
+
+	root.failFast.once.Do(func() {
+		if err := root.Validate(); err != nil {
+			root.failFast.err = fmt.Errorf("fail-fast validation: %w", err)
+		}
+	})
+
+	return root.failFast.err
+}
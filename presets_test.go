@@ -0,0 +1,127 @@
+package dshot_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestWithStrictTypes(t *testing.T) {
+	c := dshot.New(dshot.WithStrictTypes())
+	c.Provide(Database{ConnectionString: "value"})
+
+	if _, err := dshot.ResolveE[*Database](dshot.NewScoped(c)); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected no similar-type fallback, got %v", err)
+	}
+	if _, err := dshot.ResolveE[*Database](c); !errors.Is(err, dshot.ErrSimilarType) {
+		t.Errorf("Expected the similar registration reported, got %v", err)
+	}
+	if all := dshot.ResolveAll[*Database](c); len(all) != 0 {
+		t.Errorf("Expected similar types skipped by ResolveAll, got %d", len(all))
+	}
+}
+
+func TestNewForTest_FailsFast(t *testing.T) {
+	c := dshot.NewForTest()
+	c.Provide(&Database{})
+	dshot.ProvideAutoFactory(func(svc *Service) *ComplexService { return &ComplexService{Service: svc} }, c)
+
+	_, err := dshot.ResolveE[*Database](c)
+	if !errors.Is(err, dshot.ErrNotFound) || !strings.Contains(err.Error(), "fail-fast validation") {
+		t.Fatalf("Expected the broken graph reported on the first resolution, got %v", err)
+	}
+	if _, err := dshot.ResolveE[*Database](dshot.NewScoped(c)); err == nil {
+		t.Error("Expected later resolutions from scopes to fail too")
+	}
+}
+
+func TestWithTracing(t *testing.T) {
+	var buf bytes.Buffer
+	c := dshot.New(dshot.WithTracing(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	dshot.ProvideAutoFactory(func() *Database { return &Database{} }, c)
+
+	dshot.MustResolve[*Database](c)
+
+	if out := buf.String(); !strings.Contains(out, "dshot: factory called") || !strings.Contains(out, "lifecycle=Singleton") {
+		t.Errorf("Expected the factory call traced, got %q", out)
+	}
+}
+
+func TestNewOptions_LoggerParentDuplicates(t *testing.T) {
+	var logs bytes.Buffer
+	parent := dshot.New(dshot.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))), dshot.WithRegistrationSites())
+	parent.Provide(Database{ConnectionString: "parent"})
+
+	child := dshot.New(dshot.WithParent(parent), dshot.WithDuplicatePolicy(dshot.DuplicateError))
+	if db := dshot.MustResolve[*Database](child); db.ConnectionString != "parent" {
+		t.Errorf("Expected the parent's database, got '%s'", db.ConnectionString)
+	}
+	if !strings.Contains(logs.String(), "similar type") {
+		t.Errorf("Expected the child to log through the inherited logger, got %q", logs.String())
+	}
+	if info := child.ScopeInfo(); info.Depth != 1 {
+		t.Errorf("Expected depth 1, got %d", info.Depth)
+	}
+
+	child.Provide(&Service{Name: "a"})
+	func() {
+		defer func() {
+			if msg := fmt.Sprint(recover()); !strings.Contains(msg, "already registered (at ") {
+				t.Errorf("Expected a duplicate registration panic, got %q", msg)
+			}
+		}()
+		child.Provide(&Service{Name: "b"})
+	}()
+
+	replacing := dshot.New(dshot.WithDuplicatePolicy(dshot.DuplicateReplace))
+	replacing.Provide(&Service{Name: "a"})
+	replacing.Provide(&Service{Name: "b"})
+	if svc := dshot.MustResolve[*Service](replacing); svc.Name != "b" {
+		t.Errorf("Expected the duplicate to replace the first registration, got '%s'", svc.Name)
+	}
+}
+
+func TestDiagnostics_DefaultToSlogDefault(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	c := dshot.New()
+	c.Provide(Database{})
+	dshot.MustResolve[*Database](c)
+
+	if !strings.Contains(logs.String(), "similar type") {
+		t.Errorf("Expected the warning logged through slog.Default(), got %q", logs.String())
+	}
+}
+
+func TestSetWarningHandler_CapturesScopeWarnings(t *testing.T) {
+	var logs bytes.Buffer
+	c := dshot.New(dshot.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	c.Provide(Database{})
+	scope := dshot.NewScoped(c)
+
+	var warnings []dshot.Warning
+	c.SetWarningHandler(func(w dshot.Warning) {
+		warnings = append(warnings, w)
+	})
+	dshot.MustResolve[*Database](scope)
+
+	if len(warnings) != 1 || warnings[0].Kind != dshot.WarnSimilarType {
+		t.Fatalf("Expected one similar-type warning, got %+v", warnings)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected nothing logged, got %q", logs.String())
+	}
+
+	c.SetWarningHandler(nil)
+	dshot.MustResolve[*Database](scope)
+	if !strings.Contains(logs.String(), "similar type") {
+		t.Errorf("Expected logging restored, got %q", logs.String())
+	}
+}
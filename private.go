@@ -0,0 +1,137 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// moduleExports lists what a module calling Export makes visible outside of it
+type moduleExports struct {
+	tokens map[any]bool
+	types  map[reflect.Type]bool
+}
+
+// Private makes the registration resolvable only by the factories of the module registering
+// it. Outside of a module it has no effect. See Container.Export to make a whole module
+// private except for an explicit list.
+//
+// Example:
+//
+//	var BillingModule container.Module = func(c *container.Container) {
+//	    c.Register(container.BindAutoFactory(ledgerToken, NewLedger).Private())
+//	    container.ProvideAutoFactory(NewBillingService, c) // May depend on the ledger
+//	}
+func (r Registration[T]) Private() Registration[T] {
+	r.private = true
+	return r
+}
+
+// Export makes the registrations of the module being registered private, except for the
+// listed tokens and types (reflect.Type), which the rest of the application may resolve.
+// Private registrations are resolvable only by the factories of the same module; resolving
+// them from elsewhere fails with ErrNotFound, and Validate reports such dependencies. They
+// are skipped elsewhere, so modules may privately register the same type.
+// It must be called from a Module function, may be called more than once, and takes
+// effect once the module function returns.
+//
+// Example:
+//
+//	var BillingModule container.Module = func(c *container.Container) {
+//	    container.ProvideAutoFactory(NewInvoiceRepo, c)   // Private to the module
+//	    container.ProvideAutoFactory(NewBillingService, c)
+//	    c.Export(reflect.TypeFor[*BillingService]())
+//	}
+func (c *Container) Export(exports ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkWritable("Export")
+
+	if c.loadingModule == "" {
+		panic("Export: must be called while registering a module")
+	}
+	if c.exports == nil {
+		c.exports = make(map[string]*moduleExports)
+	}
+	ex := c.exports[c.loadingModule]
+	if ex == nil {
+		ex = &moduleExports{tokens: make(map[any]bool), types: make(map[reflect.Type]bool)}
+		c.exports[c.loadingModule] = ex
+	}
+
+	for _, export := range exports {
+		switch export := export.(type) {
+		case reflect.Type:
+			ex.types[export] = true
+		case nil:
+			panic("Export: cannot export nil")
+		default:
+			ex.tokens[export] = true
+		}
+	}
+}
+
+// sealModule hides the registrations of module name that it did not export, once the
+// module function has returned. Callers must hold c.mu.
+func (c *Container) sealModule(name string) {
+	ex := c.exports[name]
+	if ex == nil {
+		return
+	}
+
+	for _, e := range c.entries {
+		if e.module == name && !ex.exports(e) {
+			e.hidden.Store(true)
+		}
+	}
+}
+
+// exports reports whether e is listed by Export
+func (ex *moduleExports) exports(e *entry) bool {
+	if ex.tokens[e.token] || ex.types[e.depType] {
+		return true
+	}
+	for _, alias := range e.aliases {
+		if ex.types[alias] {
+			return true
+		}
+	}
+	return false
+}
+
+// isPrivate reports whether e is only resolvable from its own module. Privacy is set when
+// e is registered with Private, or for unexported registrations once their module is
+// registered.
+func (e *entry) isPrivate() bool {
+	return e.hidden.Load()
+}
+
+// visibleTo reports whether the factory of requester (nil outside of any factory) may
+// resolve e
+func (e *entry) visibleTo(requester *entry) bool {
+	if requester != nil && requester.module == e.module && requester.owner == e.owner {
+		return true
+	}
+	return !e.isPrivate()
+}
+
+// requester returns the entry whose factory is resolving, or nil outside of any factory
+func (r *resolution) requester() *entry {
+	if r == nil || len(r.chain) == 0 {
+		return nil
+	}
+	return r.chain[len(r.chain)-1]
+}
+
+// checkVisible returns an error if e is private to a module the resolution is not in
+func (r *resolution) checkVisible(e *entry) error {
+	if e.visibleTo(r.requester()) {
+		return nil
+	}
+	return privateError(e)
+}
+
+// privateError reports that e was found but is private to its module
+func privateError(e *entry) error {
+	return fmt.Errorf("%w: %s is private to module %q", ErrNotFound, e.label(), e.module)
+}
@@ -0,0 +1,66 @@
+package dshot
+
+import (
+	"slices"
+	"strings"
+)
+
+// WithActiveProfiles activates deployment profiles (e.g. "dev", "staging", "prod"). Only the
+// registrations made for an active profile, with When or Registration.WithProfile, are kept.
+// Scopes inherit the profiles of their parent.
+//
+// Example:
+//
+//	c := container.New(container.WithActiveProfiles(os.Getenv("APP_PROFILE")))
+func WithActiveProfiles(profiles ...string) Option {
+	return func(c *Container) {
+		c.profiles = append(c.profiles, profiles...)
+	}
+}
+
+// ProfileActive reports whether profile is active in the container. A profile prefixed
+// with "!" is active when the named profile is not.
+func (c *Container) ProfileActive(profile string) bool {
+	if name, negated := strings.CutPrefix(profile, "!"); negated {
+		return !slices.Contains(c.profiles, name)
+	}
+	return slices.Contains(c.profiles, profile)
+}
+
+// anyProfileActive reports whether one of profiles is active
+func (c *Container) anyProfileActive(profiles []string) bool {
+	return slices.ContainsFunc(profiles, c.ProfileActive)
+}
+
+// When returns a provider set installed only when one of the comma-separated profiles is
+// active. Items are those accepted by Set. A profile prefixed with "!" matches when the
+// profile is not active.
+//
+// Example:
+//
+//	c.Install(
+//	    container.When("prod", NewSQSQueue),
+//	    container.When("!prod", NewMemoryQueue),
+//	)
+func When(profiles string, items ...any) *ProviderSet {
+	set := Set(items...)
+	set.profiles = strings.Split(profiles, ",")
+	for i, p := range set.profiles {
+		set.profiles[i] = strings.TrimSpace(p)
+	}
+	return set
+}
+
+// WithProfile keeps the registration only when one of profiles is active, see
+// WithActiveProfiles. A profile prefixed with "!" matches when the profile is not active.
+//
+// Example:
+//
+//	container.Register(
+//	    container.BindAutoFactory(queueToken, NewSQSQueue).WithProfile("staging", "prod"),
+//	    container.BindAutoFactory(queueToken, NewMemoryQueue).WithProfile("dev"),
+//	)
+func (r Registration[T]) WithProfile(profiles ...string) Registration[T] {
+	r.profiles = append(r.profiles[:len(r.profiles):len(r.profiles)], profiles...)
+	return r
+}
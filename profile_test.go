@@ -0,0 +1,45 @@
+package dshot_test
+
+import (
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestProfiles(t *testing.T) {
+	token := dshot.NewToken[*Service]("queue")
+	register := func(c *dshot.Container) {
+		c.Register(
+			dshot.Bind(token, &Service{Name: "sqs"}).WithProfile("staging", "prod"),
+			dshot.Bind(token, &Service{Name: "memory"}).WithProfile("dev"),
+		)
+		c.Install(
+			dshot.When("prod", func() *Database { return &Database{ConnectionString: "rds"} }),
+			dshot.When("!prod", func() *Database { return &Database{ConnectionString: "sqlite"} }),
+		)
+	}
+
+	prod := dshot.New(dshot.WithActiveProfiles("prod"))
+	register(prod)
+	if svc := dshot.Get(token, prod); svc.Name != "sqs" {
+		t.Errorf("Expected 'sqs' in prod, got '%s'", svc.Name)
+	}
+	if db := dshot.MustResolve[*Database](dshot.NewScoped(prod)); db.ConnectionString != "rds" {
+		t.Errorf("Expected 'rds' in prod, got '%s'", db.ConnectionString)
+	}
+
+	dev := dshot.New(dshot.WithActiveProfiles("dev"))
+	register(dev)
+	if svc := dshot.Get(token, dev); svc.Name != "memory" {
+		t.Errorf("Expected 'memory' in dev, got '%s'", svc.Name)
+	}
+	if db := dshot.MustResolve[*Database](dev); db.ConnectionString != "sqlite" {
+		t.Errorf("Expected 'sqlite' in dev, got '%s'", db.ConnectionString)
+	}
+
+	none := dshot.New()
+	register(none)
+	if _, err := dshot.GetE(token, none); err == nil {
+		t.Error("Expected no queue without an active profile")
+	}
+}
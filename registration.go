@@ -1,39 +1,172 @@
 package dshot
 
-import "reflect"
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+)
 
 type registration interface {
 	registerTo(c *Container)
 }
 
 type Registration[T any] struct {
-	token     *Token[T]
-	value     T
-	factory   func() T
-	lifecycle Lifecycle
+	token        *Token[T]
+	value        T
+	factory      func(r *resolution) (T, error)
+	fallback     func(r *resolution) (T, error)
+	lifecycle    Lifecycle
+	capabilities map[string]string
+	wiring       *wiring
+	closer       func(ctx context.Context, v T) error
+	ownCleanup   bool
+	aliases      []reflect.Type
+	groups       []string
+	private      bool
+	profiles     []string
+	enabled      func(c *Container) bool
+	order        *int
+	override     bool
+	variants     *variantSpec[T]
 }
 
 func (r Registration[T]) registerTo(c *Container) {
+	if r.profiles != nil && !c.anyProfileActive(r.profiles) {
+		return
+	}
+
+	e := r.newEntry()
+	if r.variants != nil {
+		e.variants = r.variants.newSet()
+	}
+
+	if r.enabled != nil {
+		// gateEntry shadows the previous registration, check before touching it
+		c.checkWritable("register")
+		c.gateEntry(e, r.token, r.enabled)
+	}
+	c.addEntry(r.token, e)
+	if e.variants != nil {
+		c.adoptVariants(e)
+	}
+}
+
+// newEntry returns the entry of the registration, not yet added to a container
+func (r Registration[T]) newEntry() *entry {
 	e := &entry{
-		lifecycle: r.lifecycle,
+		token:        r.token,
+		lifecycle:    r.lifecycle,
+		capabilities: r.capabilities,
+		wiring:       r.wiring,
+		ownCleanup:   r.ownCleanup,
+		aliases:      r.aliases,
+		groups:       r.groups,
+		private:      r.private,
+		order:        r.order,
+		override:     r.override,
+	}
+
+	if r.fallback != nil {
+		e.fallback = func(res *resolution) (any, error) {
+			return r.fallback(res)
+		}
+	}
+
+	if r.closer != nil {
+		e.closer = func(ctx context.Context, v any) error {
+			return r.closer(ctx, v.(T))
+		}
 	}
 
 	if r.factory != nil {
-		e.factory = func() any {
-			return r.factory()
+		e.factory = func(res *resolution) (any, error) {
+			return r.factory(res)
 		}
 	} else {
 		e.value = r.value
 	}
 
-	var zero T
-	typ := reflect.TypeOf(zero)
-	if typ != nil {
-		e.depType = typ
-		c.typeRegistry[typ] = append(c.typeRegistry[typ], e)
+	e.depType = reflect.TypeFor[T]()
+
+	return e
+}
+
+// WithCapability declares a capability of the registered implementation,
+// e.g. the content type a codec supports. See ResolveByCapability.
+func (r Registration[T]) WithCapability(key, value string) Registration[T] {
+	capabilities := make(map[string]string, len(r.capabilities)+1)
+	maps.Copy(capabilities, r.capabilities)
+	capabilities[key] = value
+	r.capabilities = capabilities
+	return r
+}
+
+// OnClose sets the function disposing the singleton when its container is closed,
+// overriding Disposable and io.Closer.
+//
+// Example:
+//
+//	container.BindAutoFactory(poolToken, NewPool).OnClose(func(ctx context.Context, p *Pool) error {
+//	    return p.Drain(ctx)
+//	})
+func (r Registration[T]) OnClose(fn func(ctx context.Context, v T) error) Registration[T] {
+	r.closer = fn
+	return r
+}
+
+// Fallback sets an auto-wired factory (func(...) T or func(...) (T, error)) that provides
+// the value when the primary factory fails or panics, e.g. an in-memory cache when Redis is
+// unreachable. Its dependencies are resolved from the primary factory's container.
+// Each degradation is recorded and reported by Container.Degradations.
+//
+// Example:
+//
+//	container.Register(
+//	    container.BindAutoFactoryErr(cacheToken, NewRedisCache).Fallback(NewMemoryCache),
+//	)
+func (r Registration[T]) Fallback(factory any) Registration[T] {
+	if r.factory == nil {
+		panic("Fallback: registration has no factory")
+	}
+
+	var c *Container
+	if r.wiring != nil {
+		c = r.wiring.c
+	} else {
+		c = global()
+	}
+	checkGlobalUse(c, "Fallback")
+
+	fnType := reflect.TypeOf(factory)
+	withError := fnType != nil && fnType.Kind() == reflect.Func && fnType.NumOut() == 2 && fnType.Out(1) == errorType
+
+	r.fallback = buildAutoFactory(r.token, factory, r.lifecycle, withError, c).factory
+	return r
+}
+
+// As makes the registration resolvable by the interface type I as well. Explicit aliases
+// take precedence over matching implementations by scan, so a type resolves without
+// ambiguity to the registration declaring it even when other implementations exist.
+// Registering several registrations with the same alias makes that alias ambiguous.
+//
+// Example:
+//
+//	container.Register(
+//	    container.As[io.Writer](container.As[io.Reader](container.BindAutoFactory(bufToken, NewBuffer))),
+//	)
+//	r := container.MustResolve[io.Reader]() // The buffer, even if other readers are registered
+func As[I, T any](r Registration[T]) Registration[T] {
+	alias := reflect.TypeFor[I]()
+	if alias.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("As: %s is not an interface type", alias))
+	}
+	if !reflect.TypeFor[T]().Implements(alias) {
+		panic(fmt.Sprintf("As: %s does not implement %s", reflect.TypeFor[T](), alias))
 	}
 
-	c.registry[r.token] = e
+	r.aliases = append(r.aliases[:len(r.aliases):len(r.aliases)], alias)
+	return r
 }
 
 func Bind[T any](token *Token[T], value T) Registration[T] {
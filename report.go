@@ -0,0 +1,100 @@
+package dshot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Report writes a markdown summary of the container's wiring: modules, then every visible
+// registration with its lifecycle, module, scope and the dependencies its auto-wired factory
+// takes. Nothing is constructed and lazy modules are not loaded, so the report can be
+// regenerated from a test and committed as architecture documentation.
+//
+// Example:
+//
+//	func TestWiringReport(t *testing.T) {
+//	    f, _ := os.Create("docs/wiring.md")
+//	    defer f.Close()
+//	    if err := app.NewContainer().Report(f); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+func (c *Container) Report(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "# Dependency Report")
+
+	type module struct{ name, status string }
+	var modules []module
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		for name := range cur.modules {
+			status := "loaded"
+			if slices.ContainsFunc(cur.lazyModules, func(m *lazyModule) bool { return m.name == name }) {
+				status = "lazy, not loaded"
+			}
+			modules = append(modules, module{name, status})
+		}
+		cur.mu.RUnlock()
+	}
+
+	if len(modules) > 0 {
+		slices.SortFunc(modules, func(a, b module) int { return strings.Compare(a.name, b.name) })
+
+		fmt.Fprintln(bw, "\n## Modules\n\n| Module | Status |\n|---|---|")
+		for _, m := range modules {
+			fmt.Fprintf(bw, "| %s | %s |\n", markdownCell(m.name), m.status)
+		}
+	}
+
+	fmt.Fprintln(bw, "\n## Registrations\n\n| Name | Type | Lifecycle | Module | Scope | Dependencies |\n|---|---|---|---|---|---|")
+
+	// Restricted containers hide parent registrations outside their allowlist
+	var restrictions []*Container
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		entries := cur.entries
+		cur.mu.RUnlock()
+
+		for _, e := range entries {
+			if !allowedBy(restrictions, e.depType) {
+				continue
+			}
+
+			lifecycle := "Value"
+			if e.factory != nil {
+				lifecycle = e.lifecycle.String()
+			}
+
+			var deps []string
+			if e.wiring != nil {
+				for _, p := range e.wiring.params {
+					deps = append(deps, "`"+p.String()+"`")
+				}
+			}
+
+			fmt.Fprintf(bw, "| %s | `%s` | %s | %s | %s | %s |\n",
+				markdownCell(e.label()),
+				markdownCell(e.depType.String()),
+				lifecycle,
+				markdownCell(e.module),
+				markdownCell(cur.name),
+				markdownCell(strings.Join(deps, ", ")),
+			)
+		}
+
+		if cur.allow != nil {
+			restrictions = append(restrictions, cur)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// markdownCell escapes s for use in a markdown table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
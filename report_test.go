@@ -0,0 +1,39 @@
+package dshot_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestReport(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{})
+	c.RegisterModule("storage", func(c *dshot.Container) {
+		dshot.ProvideAutoFactory(func(db *Database) *Repository {
+			return &Repository{DB: db}
+		}, c)
+	})
+	c.RegisterLazyModule("admin", func() dshot.Module {
+		return func(*dshot.Container) {}
+	}, reflect.TypeFor[*Service]())
+
+	var sb strings.Builder
+	if err := c.Report(&sb); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	report := sb.String()
+
+	for _, want := range []string{
+		"| admin | lazy, not loaded |",
+		"| storage | loaded |",
+		"| *dshot_test.Database | `*dshot_test.Database` | Value |  | root |  |",
+		"| *dshot_test.Repository | `*dshot_test.Repository` | Singleton | storage | root | `*dshot_test.Database` |",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
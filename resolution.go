@@ -0,0 +1,154 @@
+package dshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// resolution carries state across the nested resolutions triggered by a single call.
+// A nil *resolution is valid and tracks nothing.
+type resolution struct {
+	closers *[]io.Closer    // Closers constructed during the call, nil when not tracking
+	chain   []*entry        // Entries whose factories are running, outermost first
+	scope   *Container      // Scope a Scoped factory resolves its dependencies from
+	ctx     context.Context // Context of the *Ctx call that started the resolution, if any
+
+	// Scope that disposes the prototypes built during the call, see Dispose
+	disposer *Container
+}
+
+// newTrackingResolution returns a resolution that records constructed closers
+func newTrackingResolution() *resolution {
+	return &resolution{closers: new([]io.Closer)}
+}
+
+// newContextResolution returns a resolution started by a context-aware call
+func newContextResolution(ctx context.Context) *resolution {
+	return &resolution{ctx: ctx}
+}
+
+// untracked returns a resolution for constructing values owned by the container
+func (r *resolution) untracked() *resolution {
+	if r == nil {
+		return nil
+	}
+	return &resolution{chain: r.chain, ctx: r.ctx}
+}
+
+// enter returns a resolution for running the factory of e, or a cycle error
+// if e is already being constructed further up the chain
+func (r *resolution) enter(e *entry) (*resolution, error) {
+	if r == nil {
+		return &resolution{chain: []*entry{e}}, nil
+	}
+
+	for i, cur := range r.chain {
+		if cur == e {
+			return nil, newCycleError(append(r.chain[i:len(r.chain):len(r.chain)], e))
+		}
+	}
+
+	return &resolution{
+		closers:  r.closers,
+		chain:    append(r.chain[:len(r.chain):len(r.chain)], e),
+		ctx:      r.ctx,
+		disposer: r.disposer,
+	}, nil
+}
+
+// newCycleError describes a dependency cycle, e.g. *Repo -> *Service -> *Repo
+func newCycleError(cycle []*entry) error {
+	names := make([]string, len(cycle))
+	for i, e := range cycle {
+		names[i] = e.label()
+	}
+
+	return fmt.Errorf("%w: %s", ErrCircularDependency, strings.Join(names, " -> "))
+}
+
+// in returns the container a factory bound to c resolves its dependencies from:
+// the requesting scope for Scoped factories, else c
+func (r *resolution) in(c *Container) *Container {
+	if r != nil && r.scope != nil {
+		return r.scope
+	}
+	return c
+}
+
+// track records val for disposal if it is a closer and tracking is enabled
+func (r *resolution) track(val any) {
+	if r == nil || r.closers == nil {
+		return
+	}
+	if closer, ok := val.(io.Closer); ok {
+		*r.closers = append(*r.closers, closer)
+	}
+}
+
+// disposedBy returns a resolution whose prototypes are disposed with scope
+func (r *resolution) disposedBy(scope *Container) *resolution {
+	if r == nil {
+		return &resolution{disposer: scope}
+	}
+	cp := *r
+	cp.disposer = scope
+	return &cp
+}
+
+// trackBuilt records a prototype built by e: for the call if it tracks closers,
+// else for the scope it was resolved through
+func (r *resolution) trackBuilt(e *entry, val any) {
+	if r != nil && r.closers == nil && r.disposer != nil {
+		r.disposer.trackDisposal(e, val)
+		return
+	}
+	r.track(val)
+}
+
+// trackCleanup records the cleanup function returned by a factory bound to c. Like
+// their closers (see trackBuilt), the cleanups of prototypes run with the call or scope
+// disposing them; a prototype built outside both has no owner and its cleanup is
+// dropped with a warning rather than kept by c until it closes.
+func (r *resolution) trackCleanup(c *Container, fn func()) {
+	switch e := r.requester(); {
+	case r != nil && r.closers == nil && r.disposer != nil:
+		r.disposer.addCleanup(fn)
+	case e == nil || e.lifecycle != Prototype:
+		c.addCleanup(fn)
+	case r.closers != nil:
+		*r.closers = append(*r.closers, cleanupCloser(fn))
+	default:
+		c.warnUnownedCleanup(e)
+	}
+}
+
+// cleanupCloser adapts a factory cleanup function to io.Closer
+type cleanupCloser func()
+
+// Close calls f
+func (f cleanupCloser) Close() error {
+	f()
+	return nil
+}
+
+// dispose closes tracked values in reverse construction order
+func (r *resolution) dispose() error {
+	if r == nil || r.closers == nil {
+		return nil
+	}
+
+	closers := *r.closers
+	*r.closers = nil
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
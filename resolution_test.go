@@ -0,0 +1,55 @@
+package dshot_test
+
+import (
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type closableConn struct {
+	closed bool
+}
+
+func (c *closableConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestInvokeDisposable_ClosesPrototypes(t *testing.T) {
+	c := dshot.New()
+	c.ProvidePrototype(func() *closableConn {
+		return &closableConn{}
+	})
+
+	conn, dispose := dshot.CallDisposable[*closableConn](func(conn *closableConn) *closableConn {
+		return conn
+	}, c)
+
+	if conn.closed {
+		t.Fatal("Connection should not be closed before dispose")
+	}
+
+	if err := dispose(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !conn.closed {
+		t.Error("Prototype connection should be closed by dispose")
+	}
+}
+
+func TestInvokeDisposable_LeavesSingletonsOpen(t *testing.T) {
+	c := dshot.New()
+	c.ProvideFactory(func() *closableConn {
+		return &closableConn{}
+	})
+
+	conn, dispose := dshot.CallDisposable[*closableConn](func(conn *closableConn) *closableConn {
+		return conn
+	}, c)
+	dispose()
+
+	if conn.closed {
+		t.Error("Singleton connection should be owned by the container")
+	}
+}
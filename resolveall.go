@@ -0,0 +1,217 @@
+package dshot
+
+import (
+	"reflect"
+	"slices"
+)
+
+// ResolveAllOption configures ResolveAllWith
+type ResolveAllOption func(*resolveAllConfig)
+
+type resolveAllConfig struct {
+	dedupInstances bool
+	localOnly      bool
+	where          func(RegistrationInfo) bool
+}
+
+// DedupInstances drops values that are the same instance (pointer, map or channel)
+// as an earlier result, e.g. a handler provided in both a scope and its parent.
+func DedupInstances() ResolveAllOption {
+	return func(cfg *resolveAllConfig) {
+		cfg.dedupInstances = true
+	}
+}
+
+// LocalOnly excludes parent registrations, for scopes that replace the whole set,
+// e.g. of request middlewares.
+func LocalOnly() ResolveAllOption {
+	return func(cfg *resolveAllConfig) {
+		cfg.localOnly = true
+	}
+}
+
+// Where keeps only registrations accepted by pred. It is evaluated before instantiation,
+// so rejected implementations are never constructed.
+func Where(pred func(RegistrationInfo) bool) ResolveAllOption {
+	return func(cfg *resolveAllConfig) {
+		cfg.where = pred
+	}
+}
+
+// ResolveAllLocal returns the values of targetType registered in this container only.
+// It is ResolveAllWith(targetType, LocalOnly()).
+func (c *Container) ResolveAllLocal(targetType reflect.Type) []any {
+	return c.ResolveAllWith(targetType, LocalOnly())
+}
+
+// ResolveAllWith is ResolveAll with options.
+//
+// Example:
+//
+//	handlers := c.ResolveAllWith(handlerType, container.DedupInstances())
+func (c *Container) ResolveAllWith(targetType reflect.Type, opts ...ResolveAllOption) []any {
+	var cfg resolveAllConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	candidates := c.findCandidates(targetType, !cfg.localOnly, nil)
+	if cfg.where != nil {
+		candidates = slices.DeleteFunc(candidates, func(cand candidate) bool {
+			return !cfg.where(cand.e.info())
+		})
+	}
+
+	results := c.resolveCandidates(targetType, candidates, nil)
+
+	if cfg.dedupInstances {
+		results = dedupInstances(results)
+	}
+
+	return results
+}
+
+// ResolveAllWith returns all registered values of type T from c (or the global container if nil),
+// applying the given options.
+//
+// Example:
+//
+//	for _, m := range container.ResolveAllWith[Middleware](scope, container.DedupInstances()) {
+//	    handler = m.Wrap(handler)
+//	}
+func ResolveAllWith[T any](c *Container, opts ...ResolveAllOption) []T {
+	if c == nil {
+		c = global()
+	}
+	checkGlobalUse(c, "ResolveAllWith")
+
+	results := c.ResolveAllWith(reflect.TypeFor[T](), opts...)
+
+	typed := make([]T, len(results))
+	for i, val := range results {
+		typed[i] = mustTyped(typedByType[T](val, c))
+	}
+
+	return typed
+}
+
+// ResolveAllWhere returns the values of type T from c (or the global container if nil)
+// whose registration is accepted by pred, constructing only those.
+//
+// Example:
+//
+//	billing := container.ResolveAllWhere[Job](c, func(info container.RegistrationInfo) bool {
+//	    return info.Module == "billing"
+//	})
+func ResolveAllWhere[T any](c *Container, pred func(RegistrationInfo) bool) []T {
+	return ResolveAllWith[T](c, Where(pred))
+}
+
+// instanceKey identifies a reference value by dynamic type and address
+type instanceKey struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// dedupInstances removes repeated reference values, keeping the first occurrence
+func dedupInstances(values []any) []any {
+	seen := make(map[instanceKey]bool, len(values))
+	out := values[:0:0]
+
+	for _, val := range values {
+		v := reflect.ValueOf(val)
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+			key := instanceKey{typ: v.Type(), ptr: v.Pointer()}
+			if !v.IsNil() && seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		out = append(out, val)
+	}
+
+	return out
+}
+
+// isGroup reports whether a factory parameter of type typ is resolved as a group:
+// a slice of a non-primitive element type, e.g. []Handler
+func isGroup(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && !isPrimitive(typ.Elem().Kind())
+}
+
+// resolveGroup resolves a []T parameter. A registration of the slice type itself wins;
+// otherwise the slice holds every registration assignable to T, sorted by WithOrder then
+// in registration order, and is empty if there is none. Lazy modules are not loaded, as with ResolveAll.
+func (c *Container) resolveGroup(sliceType reflect.Type, r *resolution) (reflect.Value, error) {
+	val, ok, err := c.resolveType(sliceType, r)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if ok {
+		return reflect.ValueOf(val), nil
+	}
+
+	elem := sliceType.Elem()
+	candidates := c.candidates(elem, r.requester())
+
+	ordered := make([]orderedValue, 0, len(candidates))
+	for _, cand := range candidates {
+		val, ok, err := cand.resolve(c, elem, r)
+		if err != nil {
+			return reflect.Value{}, withStep(err, cand.e.label())
+		}
+		if ok {
+			ordered = append(ordered, orderedValue{cand.e, val})
+		}
+	}
+	sortByOrder(ordered)
+
+	group := reflect.MakeSlice(sliceType, 0, len(ordered))
+	for _, v := range ordered {
+		group = reflect.Append(group, reflect.ValueOf(v.val))
+	}
+
+	return group, nil
+}
+
+// isKeyedGroup reports whether a factory parameter or injected field of type typ is
+// resolved as a keyed group: a map from strings to a non-primitive type, e.g. map[string]Provider
+func isKeyedGroup(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String && !isPrimitive(typ.Elem().Kind())
+}
+
+// resolveKeyedGroup resolves a map[string]T. A registration of the map type itself wins;
+// otherwise the map holds every registration assignable to T keyed by its token name
+// (its type for type-based registrations). Registrations in a scope shadow parent ones
+// with the same name.
+func (c *Container) resolveKeyedGroup(mapType reflect.Type, r *resolution) (reflect.Value, error) {
+	val, ok, err := c.resolveType(mapType, r)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if ok {
+		return reflect.ValueOf(val), nil
+	}
+
+	elem := mapType.Elem()
+	candidates := c.candidates(elem, r.requester())
+
+	group := reflect.MakeMapWithSize(mapType, len(candidates))
+	for _, cand := range candidates {
+		key := reflect.ValueOf(cand.e.label()).Convert(mapType.Key())
+		if group.MapIndex(key).IsValid() {
+			continue
+		}
+
+		val, ok, err := cand.resolve(c, elem, r)
+		if err != nil {
+			return reflect.Value{}, withStep(err, cand.e.label())
+		}
+		if ok {
+			group.SetMapIndex(key, reflect.ValueOf(val))
+		}
+	}
+
+	return group, nil
+}
@@ -0,0 +1,221 @@
+package dshot_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestResolveAllWith_DedupInstances(t *testing.T) {
+	parent := dshot.New()
+	shared := &Service{Name: "shared"}
+	parent.Provide(shared)
+
+	child := dshot.NewScoped(parent)
+	child.Register(dshot.Bind(dshot.NewToken[*Service]("again"), shared))
+	child.Register(dshot.Bind(dshot.NewToken[*Service]("other"), &Service{Name: "other"}))
+
+	if all := dshot.ResolveAll[*Service](child); len(all) != 3 {
+		t.Fatalf("Expected 3 values without dedup, got %d", len(all))
+	}
+
+	unique := dshot.ResolveAllWith[*Service](child, dshot.DedupInstances())
+	if len(unique) != 2 {
+		t.Fatalf("Expected 2 unique values, got %d", len(unique))
+	}
+	if unique[0] != shared || unique[1].Name != "other" {
+		t.Errorf("Expected first occurrences in order, got %v", unique)
+	}
+}
+
+func TestResolveAllLocal(t *testing.T) {
+	parent := dshot.New()
+	parent.Register(dshot.Bind(dshot.NewToken[*Service]("global"), &Service{Name: "global"}))
+
+	scope := dshot.NewScoped(parent)
+	scope.Register(dshot.Bind(dshot.NewToken[*Service]("local"), &Service{Name: "local"}))
+
+	local := dshot.ResolveAllWith[*Service](scope, dshot.LocalOnly())
+	if len(local) != 1 || local[0].Name != "local" {
+		t.Errorf("Expected only the local service, got %v", local)
+	}
+
+	if all := scope.ResolveAllLocal(reflect.TypeFor[*Service]()); len(all) != 1 {
+		t.Errorf("Expected 1 local value, got %d", len(all))
+	}
+}
+
+func TestResolveAllWhere_ByModule(t *testing.T) {
+	c := dshot.New()
+	built := 0
+
+	c.RegisterModule("billing", func(c *dshot.Container) {
+		c.Register(dshot.Bind(dshot.NewToken[*Service]("invoices"), &Service{Name: "invoices"}))
+	})
+	c.ProvideFactory(func() *Service {
+		built++
+		return &Service{Name: "other"}
+	})
+
+	got := dshot.ResolveAllWhere[*Service](c, func(info dshot.RegistrationInfo) bool {
+		return info.Module == "billing"
+	})
+	if len(got) != 1 || got[0].Name != "invoices" {
+		t.Errorf("Expected only the billing service, got %v", got)
+	}
+	if built != 0 {
+		t.Error("Rejected registrations should not be constructed")
+	}
+}
+
+type routeHandler interface {
+	Route() string
+}
+
+type healthHandler struct{}
+
+func (healthHandler) Route() string { return "/health" }
+
+type usersHandler struct{}
+
+func (*usersHandler) Route() string { return "/users" }
+
+type router struct {
+	routes []string
+}
+
+func TestGroupParameter(t *testing.T) {
+	c := dshot.New()
+	c.Provide(healthHandler{})
+	c.Register(dshot.Bind(dshot.NewToken[*usersHandler]("users"), &usersHandler{}))
+	dshot.ProvideAutoFactory(func(handlers []routeHandler) *router {
+		r := &router{}
+		for _, h := range handlers {
+			r.routes = append(r.routes, h.Route())
+		}
+		return r
+	}, c)
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	got := dshot.MustResolve[*router](c).routes
+	if !reflect.DeepEqual(got, []string{"/health", "/users"}) {
+		t.Errorf("Expected all handlers in registration order, got %v", got)
+	}
+
+	empty := dshot.Call[[]*Service](func(services []*Service) []*Service { return services }, dshot.New())
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("Expected an empty group, got %#v", empty)
+	}
+}
+
+type paymentProvider interface {
+	Charge(cents int) string
+}
+
+type stripeProvider struct{}
+
+func (stripeProvider) Charge(int) string { return "stripe" }
+
+type paypalProvider struct{}
+
+func (paypalProvider) Charge(int) string { return "paypal" }
+
+type checkout struct {
+	providers map[string]paymentProvider
+}
+
+func TestKeyedGroupParameter(t *testing.T) {
+	c := dshot.New()
+	c.Register(
+		dshot.Bind(dshot.NewToken[stripeProvider]("stripe"), stripeProvider{}),
+		dshot.Bind(dshot.NewToken[paypalProvider]("paypal"), paypalProvider{}),
+	)
+	dshot.ProvideAutoFactory(func(providers map[string]paymentProvider) *checkout {
+		return &checkout{providers: providers}
+	}, c)
+
+	scope := dshot.NewScoped(c)
+	scope.Register(dshot.Bind(dshot.NewToken[paypalProvider]("paypal"), paypalProvider{}))
+
+	providers := dshot.MustResolve[*checkout](c).providers
+	if len(providers) != 2 || providers["stripe"].Charge(100) != "stripe" || providers["paypal"].Charge(100) != "paypal" {
+		t.Errorf("Expected providers keyed by token name, got %v", providers)
+	}
+
+	var deps struct {
+		Providers map[string]paymentProvider
+	}
+	scope.Inject(&deps)
+	if len(deps.Providers) != 2 {
+		t.Errorf("Expected scope registration to shadow the parent one, got %v", deps.Providers)
+	}
+}
+
+type orderedMiddleware struct {
+	name  string
+	order int
+}
+
+func (m *orderedMiddleware) Order() int {
+	return m.order
+}
+
+func TestResolveAll_Order(t *testing.T) {
+	c := dshot.New()
+	c.Register(
+		dshot.Bind(dshot.NewToken[*orderedMiddleware]("auth"), &orderedMiddleware{name: "auth"}).WithOrder(10),
+		dshot.Bind(dshot.NewToken[*orderedMiddleware]("log"), &orderedMiddleware{name: "log", order: 5}),
+		dshot.Bind(dshot.NewToken[*orderedMiddleware]("recover"), &orderedMiddleware{name: "recover", order: 50}).WithOrder(-1),
+		dshot.Bind(dshot.NewToken[*orderedMiddleware]("metrics"), &orderedMiddleware{name: "metrics", order: 5}),
+	)
+
+	var names []string
+	for _, m := range dshot.ResolveAll[*orderedMiddleware](c) {
+		names = append(names, m.name)
+	}
+	if want := "recover,log,metrics,auth"; strings.Join(names, ",") != want {
+		t.Errorf("Expected %s, got %v", want, names)
+	}
+
+	chain := dshot.Call[[]*orderedMiddleware](func(ms []*orderedMiddleware) []*orderedMiddleware { return ms }, c)
+	if len(chain) != 4 || chain[0].name != "recover" || chain[3].name != "auth" {
+		t.Errorf("Expected []T parameters sorted too, got %v", chain)
+	}
+}
+
+func TestResolveN(t *testing.T) {
+	c := dshot.New()
+	var built atomic.Int64
+	dshot.ProvideAutoPrototype(func() *Service {
+		return &Service{Name: fmt.Sprint("worker-", built.Add(1))}
+	}, c)
+
+	workers := dshot.ResolveN[*Service](c, 3)
+	if len(workers) != 3 || workers[0].Name != "worker-1" || workers[2].Name != "worker-3" {
+		t.Errorf("Expected 3 workers in order, got %v", workers)
+	}
+
+	concurrent := dshot.ResolveN[*Service](c, 10, dshot.Concurrently(4))
+	seen := make(map[*Service]bool)
+	for _, w := range concurrent {
+		seen[w] = true
+	}
+	if len(seen) != 10 || built.Load() != 13 {
+		t.Errorf("Expected 10 distinct workers, got %d (built %d)", len(seen), built.Load())
+	}
+
+	c.Provide(&Database{})
+	if _, err := dshot.ResolveNE[*Database](c, 2); err == nil || !strings.Contains(err.Error(), "not a prototype") {
+		t.Errorf("Expected singletons rejected, got %v", err)
+	}
+	if _, err := dshot.ResolveNE[*Service](c, -1); err == nil || !strings.Contains(err.Error(), "negative") {
+		t.Errorf("Expected a negative count rejected, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package dshot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ResolveNOption configures ResolveN
+type ResolveNOption func(*resolveNConfig)
+
+type resolveNConfig struct {
+	concurrency int
+}
+
+// Concurrently builds up to limit instances at a time in ResolveN, or all of them at once
+// if limit is 0 or less. Factories must then be safe to call concurrently.
+func Concurrently(limit int) ResolveNOption {
+	return func(cfg *resolveNConfig) {
+		cfg.concurrency = limit
+		if limit <= 0 {
+			cfg.concurrency = -1
+		}
+	}
+}
+
+// ResolveN builds n instances of the prototype registration of T, e.g. workers or
+// sharded clients sharing one registration. Instances are returned in the order they
+// were requested, and it panics if n is negative, T is not registered as a prototype or a
+// factory fails.
+//
+// Example:
+//
+//	container.ProvideAutoPrototype(NewWorker, c)
+//	for i, w := range container.ResolveN[*Worker](c, 8, container.Concurrently(0)) {
+//	    go w.Run(ctx, i)
+//	}
+func ResolveN[T any](c *Container, n int, opts ...ResolveNOption) []T {
+	if n < 0 {
+		panic(fmt.Sprintf("ResolveN: negative instance count %d", n))
+	}
+	vals, err := ResolveNE[T](c, n, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return vals
+}
+
+// ResolveNE is ResolveN returning failures instead of panicking. Failures of concurrent
+// builds are joined.
+func ResolveNE[T any](c *Container, n int, opts ...ResolveNOption) ([]T, error) {
+	if c == nil {
+		c = global()
+	}
+	checkGlobalUse(c, "ResolveN")
+
+	if n < 0 {
+		return nil, fmt.Errorf("ResolveN: negative instance count %d", n)
+	}
+
+	var cfg resolveNConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	targetType := reflect.TypeFor[T]()
+	cand, err := c.findPrototype(targetType)
+	if err != nil {
+		return nil, withStep(err, targetType.String())
+	}
+
+	vals := make([]T, n)
+	build := func(i int) error {
+		val, _, err := cand.resolve(c, targetType, nil)
+		if err == nil {
+			vals[i], err = typedEntry[T](val, cand.e)
+		}
+		if err != nil {
+			return withStep(err, fmt.Sprintf("%s instance %d", targetType, i))
+		}
+		return nil
+	}
+
+	if cfg.concurrency == 0 {
+		for i := range n {
+			if err := build(i); err != nil {
+				return nil, err
+			}
+		}
+		return vals, nil
+	}
+
+	limit := cfg.concurrency
+	if limit < 0 || limit > n {
+		limit = n
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, max(limit, 1))
+	var wg sync.WaitGroup
+	for i := range n {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			errs[i] = build(i)
+		})
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return vals, nil
+}
+
+// findPrototype returns the prototype registration targetType resolves to, loading pending
+// lazy modules providing it until it is found
+func (c *Container) findPrototype(targetType reflect.Type) (candidate, error) {
+	for {
+		cand, ok, err := c.findEntry(targetType, nil)
+		if err != nil {
+			return candidate{}, err
+		}
+		if ok {
+			if cand.e.lifecycle != Prototype {
+				return candidate{}, fmt.Errorf("ResolveN: %s is a %s registration, not a prototype", cand.e.label(), cand.e.lifecycle)
+			}
+			return cand, nil
+		}
+		loaded, err := c.loadModuleForType(targetType)
+		if err != nil {
+			return candidate{}, err
+		}
+		if !loaded {
+			return candidate{}, c.notFound(targetType)
+		}
+	}
+}
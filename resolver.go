@@ -0,0 +1,103 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Resolver supplies constructors for types that have no registration.
+// It returns an auto-wired factory (func(...) T or func(...) (T, error)) for targetType,
+// or false to decline. Go cannot instantiate generic functions at runtime, so a resolver
+// is where a generic constructor is instantiated for the requested type argument.
+//
+// Example:
+//
+//	c.AddResolver(func(t reflect.Type) (any, bool) {
+//	    switch t {
+//	    case reflect.TypeFor[*Repo[User]]():
+//	        return NewRepo[User], true
+//	    case reflect.TypeFor[*Repo[Order]]():
+//	        return NewRepo[Order], true
+//	    }
+//	    return nil, false
+//	})
+type Resolver func(targetType reflect.Type) (factory any, ok bool)
+
+// AddResolver adds a resolver consulted when a type resolution misses in this container
+// or its scopes. The factory it returns is registered as an auto-wired singleton in this
+// container, so each type is instantiated once. Once the container is frozen its resolvers
+// are no longer consulted, see Freeze.
+func (c *Container) AddResolver(r Resolver) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkWritable("AddResolver")
+
+	c.resolvers = append(c.resolvers, r)
+}
+
+// instantiate asks the resolvers of the container and the parents it can resolve from
+// to register a factory for targetType. It reports whether one was registered.
+func (c *Container) instantiate(targetType reflect.Type) bool {
+	for cur := c; cur != nil; cur = cur.parent {
+		if cur.instantiateLocal(targetType) {
+			return true
+		}
+		if !cur.allowsFromParent(targetType) {
+			return false
+		}
+	}
+
+	return false
+}
+
+// instantiateLocal asks this container's resolvers for a factory producing targetType
+func (c *Container) instantiateLocal(targetType reflect.Type) bool {
+	if c.frozen.Load() {
+		return false
+	}
+
+	c.mu.RLock()
+	resolvers := c.resolvers
+	c.mu.RUnlock()
+
+	if len(resolvers) == 0 {
+		return false
+	}
+
+	c.resolverMu.Lock()
+	defer c.resolverMu.Unlock()
+
+	// Another resolution may have instantiated the type while we were waiting
+	if c.hasLocalType(targetType) {
+		return true
+	}
+
+	for _, r := range resolvers {
+		factory, ok := r(targetType)
+		if !ok {
+			continue
+		}
+
+		fnType := reflect.TypeOf(factory)
+		if fnType == nil || fnType.Kind() != reflect.Func || fnType.NumOut() == 0 || fnType.Out(0) != targetType {
+			panic(fmt.Sprintf("resolver returned %T for type %s, want a factory returning %s", factory, targetType, targetType))
+		}
+
+		withError := fnType.NumOut() == 2 && fnType.Out(1) == errorType
+		c.provideAutoFactoryWithLifecycle(factory, Singleton, withError)
+		return true
+	}
+
+	return false
+}
+
+// hasLocalType reports whether targetType has an exact registration in this container
+func (c *Container) hasLocalType(targetType reflect.Type) bool {
+	c.ensureIndexed()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.typeRegistry[targetType]) > 0
+}
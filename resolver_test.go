@@ -0,0 +1,81 @@
+package dshot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type User struct{}
+type Order struct{}
+
+type GenericRepo[T any] struct {
+	DB *Database
+}
+
+func NewGenericRepo[T any](db *Database) *GenericRepo[T] {
+	return &GenericRepo[T]{DB: db}
+}
+
+func TestAddResolver_GenericConstructor(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "localhost"})
+
+	calls := 0
+	c.AddResolver(func(typ reflect.Type) (any, bool) {
+		calls++
+		switch typ {
+		case reflect.TypeFor[*GenericRepo[User]]():
+			return NewGenericRepo[User], true
+		case reflect.TypeFor[*GenericRepo[Order]]():
+			return NewGenericRepo[Order], true
+		}
+		return nil, false
+	})
+
+	scope := dshot.NewScoped(c)
+	users := dshot.MustResolve[*GenericRepo[User]](scope)
+	if users.DB.ConnectionString != "localhost" {
+		t.Errorf("Expected 'localhost', got '%s'", users.DB.ConnectionString)
+	}
+
+	if again := dshot.MustResolve[*GenericRepo[User]](c); again != users {
+		t.Error("Instantiated type should be cached as a singleton")
+	}
+	dshot.MustResolve[*GenericRepo[Order]](c)
+
+	if calls != 2 {
+		t.Errorf("Expected resolver to be asked twice, got %d", calls)
+	}
+
+	if _, ok := dshot.Resolve[*GenericRepo[Service]](c); ok {
+		t.Error("Declined types should not resolve")
+	}
+}
+
+func TestAddResolver_Freeze(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "localhost"})
+	c.AddResolver(func(typ reflect.Type) (any, bool) {
+		switch typ {
+		case reflect.TypeFor[*GenericRepo[User]]():
+			return NewGenericRepo[User], true
+		case reflect.TypeFor[*GenericRepo[Order]]():
+			return NewGenericRepo[Order], true
+		}
+		return nil, false
+	})
+	dshot.ProvideAutoFactory(func(users *GenericRepo[User]) *Service {
+		return &Service{Name: users.DB.ConnectionString}
+	}, c)
+	c.Freeze()
+
+	scope := dshot.NewScoped(c)
+	if svc := dshot.MustResolve[*Service](scope); svc.Name != "localhost" {
+		t.Errorf("Expected 'localhost', got '%s'", svc.Name)
+	}
+	if _, ok := dshot.Resolve[*GenericRepo[Order]](scope); ok {
+		t.Error("Expected a frozen container not to consult its resolvers")
+	}
+}
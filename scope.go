@@ -0,0 +1,257 @@
+package dshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+var contextType = reflect.TypeFor[context.Context]()
+
+// RunScoped runs fn as a unit of work inside a fresh scope of c.
+// Seed values are provided into the scope before fn is called, fn's parameters are
+// auto-wired from the scope (a leading context.Context parameter receives ctx with the
+// scope attached), and the scope is closed when fn returns.
+// If fn's last return value is an error, it is returned along with any close error.
+//
+// Example:
+//
+//	err := c.RunScoped(ctx, func(ctx context.Context, msg *Message, h *Handler) error {
+//	    return h.Handle(ctx, msg)
+//	}, msg)
+func (c *Container) RunScoped(ctx context.Context, fn any, seeds ...any) error {
+	return NewScoped(c).run(ctx, fn, seeds)
+}
+
+// run provides seeds into the scope c, invokes fn and closes c
+func (c *Container) run(ctx context.Context, fn any, seeds []any) (err error) {
+	defer func() {
+		closeErr := c.Close(context.WithoutCancel(ctx))
+		err = errors.Join(err, closeErr)
+	}()
+
+	return c.call(ctx, fn, seeds, "RunScoped")
+}
+
+// call provides seeds into the scope c and invokes fn with c attached to ctx
+func (c *Container) call(ctx context.Context, fn any, seeds []any, caller string) error {
+	for _, seed := range seeds {
+		c.Provide(seed)
+	}
+
+	results := c.invokeContext(WithContainer(ctx, c), fn, caller)
+	return lastError(results)
+}
+
+// invokeContext calls fn with parameters resolved from the container,
+// passing ctx to a leading context.Context parameter if fn declares one
+func (c *Container) invokeContext(ctx context.Context, fn any, caller string) []reflect.Value {
+	fnValue := reflect.ValueOf(fn)
+	fnType := fnValue.Type()
+
+	if fnType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("%s: argument must be a function", caller))
+	}
+
+	numIn := fnType.NumIn()
+	args := make([]reflect.Value, numIn)
+
+	for i := 0; i < numIn; i++ {
+		paramType := fnType.In(i)
+		if i == 0 && paramType == contextType {
+			args[i] = reflect.ValueOf(ctx)
+			continue
+		}
+
+		arg, err := resolveParameter(c, paramType, numIn, nil)
+		if err != nil {
+			panic(fmt.Sprintf("%s: parameter %d (%s): %v", caller, i, paramType, err))
+		}
+		args[i] = arg
+	}
+
+	return fnValue.Call(args)
+}
+
+// lastError returns the last result if it is a non-nil error
+func lastError(results []reflect.Value) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	last := results[len(results)-1]
+	if last.Type() != errorType || last.IsNil() {
+		return nil
+	}
+
+	return last.Interface().(error)
+}
+
+// SetValue stores request-local data on the container, outside the dependency registry.
+// Values are not resolvable by type; use them for data such as deadlines or idempotency
+// keys. Keys follow the same rules as context keys: use an unexported key type.
+//
+// Example:
+//
+//	type idempotencyKey struct{}
+//	reqContainer.SetValue(idempotencyKey{}, r.Header.Get("Idempotency-Key"))
+func (c *Container) SetValue(key, value any) {
+	if key == nil {
+		panic("SetValue: key cannot be nil")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.values == nil {
+		c.values = make(map[any]any)
+	}
+	c.values[key] = value
+}
+
+// GetValue returns the value stored under key, checking parent containers if not found locally
+func (c *Container) GetValue(key any) (any, bool) {
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		val, ok := cur.values[key]
+		cur.mu.RUnlock()
+
+		if ok {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
+
+// ScopeValue is a typed version of GetValue
+//
+// Example:
+//
+//	key, ok := container.ScopeValue[string](reqContainer, idempotencyKey{})
+func ScopeValue[T any](c *Container, key any) (T, bool) {
+	val, ok := c.GetValue(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	typed, ok := val.(T)
+	return typed, ok
+}
+
+var scopeInfoType = reflect.TypeFor[ScopeInfo]()
+
+// ScopeInfo describes where a container sits in the scope hierarchy.
+// It can be resolved or injected from any container.
+type ScopeInfo struct {
+	Name      string    // Scope name given to NewScoped ("root" for New)
+	Namespace string    // Namespace of the container, see WithNamespace
+	Depth     int       // Number of parent containers
+	Parents   []string  // Names of parent containers, nearest first
+	CreatedAt time.Time // When the container was created
+}
+
+// ScopeInfo returns the scope metadata of the container
+//
+// Example:
+//
+//	func NewAuditLogger(scope container.ScopeInfo) *AuditLogger {
+//	    return &AuditLogger{requestScoped: scope.Depth > 0}
+//	}
+func (c *Container) ScopeInfo() ScopeInfo {
+	if c.view {
+		return c.parent.ScopeInfo()
+	}
+
+	parents := make([]string, 0, c.depth)
+	for cur := c.parent; cur != nil; cur = cur.parent {
+		if !cur.view {
+			parents = append(parents, cur.name)
+		}
+	}
+
+	return ScopeInfo{
+		Name:      c.name,
+		Namespace: c.namespace,
+		Depth:     c.depth,
+		Parents:   parents,
+		CreatedAt: c.createdAt,
+	}
+}
+
+// SetMaxScopeDepth limits how deeply scopes created from c (directly or transitively) may nest.
+// NewScoped panics with a *ScopeDepthError listing the scope chain when the limit is exceeded,
+// which catches accidental scope-per-call chains that keep slowing down lookups.
+// A limit of 0 disables the check.
+//
+// Example:
+//
+//	app := container.New()
+//	app.SetMaxScopeDepth(4)
+func (c *Container) SetMaxScopeDepth(depth int) {
+	// Read by NewScoped on concurrent scope creation, see inherit
+	c.maxScopeDepth.Store(int64(depth))
+}
+
+// ScopeDepthError reports a scope nested deeper than the configured maximum
+type ScopeDepthError struct {
+	Max   int
+	Chain []string // Scopes from the offending one up to the root, with creation sites
+}
+
+func (e *ScopeDepthError) Error() string {
+	return fmt.Sprintf(
+		"scope depth %d exceeds maximum of %d: %s",
+		len(e.Chain)-1, e.Max, strings.Join(e.Chain, " <- "),
+	)
+}
+
+func newScopeDepthError(scope *Container) *ScopeDepthError {
+	var chain []string
+	for cur := scope; cur != nil; cur = cur.parent {
+		if cur.createdAtSite != "" {
+			chain = append(chain, fmt.Sprintf("%s (%s)", cur.name, cur.createdAtSite))
+		} else {
+			chain = append(chain, cur.name)
+		}
+	}
+
+	return &ScopeDepthError{
+		Max:   int(scope.maxScopeDepth.Load()),
+		Chain: chain,
+	}
+}
+
+// callerSite returns file:line of the first caller outside this package
+func callerSite() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkgPrefix) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// pkgPrefix is the function name prefix of this package's frames
+var pkgPrefix = reflect.TypeFor[Container]().PkgPath() + "."
+
+// root returns the top-most ancestor of c
+func (c *Container) root() *Container {
+	cur := c
+	for cur.parent != nil {
+		cur = cur.parent
+	}
+	return cur
+}
@@ -0,0 +1,286 @@
+package dshot_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestRunScoped_SeedsAndClosesScope(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "localhost"})
+
+	cleaned := false
+	var seen *Service
+
+	err := c.RunScoped(context.Background(), func(ctx context.Context, svc *Service, db *Database) error {
+		seen = svc
+		scope := dshot.FromContext(ctx)
+		dshot.ProvideAutoFactory(func() (*Repository, func(), error) {
+			return &Repository{DB: db}, func() { cleaned = true }, nil
+		}, scope)
+		dshot.MustResolve[*Repository](scope)
+		return nil
+	}, &Service{Name: "seed"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if seen == nil || seen.Name != "seed" {
+		t.Error("Seed value should be injected into fn")
+	}
+	if !cleaned {
+		t.Error("Scope should be closed after fn returns")
+	}
+	if _, ok := dshot.Resolve[*Service](c); ok {
+		t.Error("Seed values should not leak into the parent container")
+	}
+}
+
+func TestRunScoped_ReturnsFnError(t *testing.T) {
+	c := dshot.New()
+	want := errors.New("boom")
+
+	err := c.RunScoped(context.Background(), func() error {
+		return want
+	})
+
+	if !errors.Is(err, want) {
+		t.Errorf("Expected %v, got %v", want, err)
+	}
+}
+
+type requestIDKey struct{}
+
+func TestScopeValues(t *testing.T) {
+	parent := dshot.New()
+	parent.SetValue(requestIDKey{}, "parent-id")
+
+	scope := dshot.NewScoped(parent)
+	if id, ok := dshot.ScopeValue[string](scope, requestIDKey{}); !ok || id != "parent-id" {
+		t.Errorf("Expected value from parent, got %q", id)
+	}
+
+	scope.SetValue(requestIDKey{}, "scope-id")
+	if id, _ := dshot.ScopeValue[string](scope, requestIDKey{}); id != "scope-id" {
+		t.Errorf("Expected scope value to shadow parent, got %q", id)
+	}
+	if id, _ := dshot.ScopeValue[string](parent, requestIDKey{}); id != "parent-id" {
+		t.Error("Scope value should not affect parent")
+	}
+
+	if _, ok := dshot.Resolve[string](scope); ok {
+		t.Error("Values should not be resolvable as dependencies")
+	}
+}
+
+func TestScopeInfo_Resolvable(t *testing.T) {
+	root := dshot.New()
+	request := dshot.NewScoped(root, "request")
+	job := dshot.NewScoped(request, "job")
+
+	info := dshot.MustResolve[dshot.ScopeInfo](job)
+	if info.Name != "job" || info.Depth != 2 {
+		t.Errorf("Unexpected scope info: %+v", info)
+	}
+	if len(info.Parents) != 2 || info.Parents[0] != "request" || info.Parents[1] != "root" {
+		t.Errorf("Unexpected parent chain: %v", info.Parents)
+	}
+
+	var deps struct {
+		Scope dshot.ScopeInfo
+	}
+	root.Inject(&deps)
+	if deps.Scope.Name != "root" || deps.Scope.Depth != 0 {
+		t.Errorf("Unexpected injected scope info: %+v", deps.Scope)
+	}
+}
+
+func TestSetMaxScopeDepth(t *testing.T) {
+	root := dshot.New()
+	root.SetMaxScopeDepth(2)
+
+	request := dshot.NewScoped(root, "request")
+	job := dshot.NewScoped(request, "job")
+
+	defer func() {
+		r := recover()
+		depthErr, ok := r.(*dshot.ScopeDepthError)
+		if !ok {
+			t.Fatalf("Expected *ScopeDepthError panic, got %v", r)
+		}
+		if depthErr.Max != 2 || len(depthErr.Chain) != 4 {
+			t.Errorf("Unexpected error: %v", depthErr)
+		}
+		if !strings.Contains(depthErr.Error(), "scope_test.go") {
+			t.Errorf("Error should include creation sites: %v", depthErr)
+		}
+	}()
+
+	dshot.NewScoped(job, "too-deep")
+}
+
+func TestSetMaxScopeDepth_ConcurrentWithNewScoped(t *testing.T) {
+	root := dshot.New()
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range 100 {
+			root.SetMaxScopeDepth(10 + i)
+		}
+	})
+	for range 100 {
+		dshot.NewScoped(root).Close(context.Background())
+	}
+	wg.Wait()
+}
+
+func TestOverrideToken_CopyOnResolve(t *testing.T) {
+	engineToken := dshot.NewToken[*Database]("pricing-engine")
+
+	app := dshot.New()
+	app.Register(dshot.Bind(engineToken, &Database{ConnectionString: "stable"}))
+	dshot.ProvideAutoFactory(func(engine *Database) *Repository {
+		return &Repository{DB: engine}
+	}, app)
+	dshot.ProvideAutoFactory(func(repo *Repository) *Service {
+		return &Service{Name: repo.DB.ConnectionString}
+	}, app)
+	dshot.ProvideAutoFactory(func() *ComplexService { return &ComplexService{} }, app)
+
+	before := dshot.MustResolve[*Service](app)
+	unrelated := dshot.MustResolve[*ComplexService](app)
+
+	canary := dshot.NewScoped(app, "canary")
+	dshot.OverrideToken(canary, engineToken, func() *Database {
+		return &Database{ConnectionString: "experimental"}
+	})
+
+	if svc := dshot.MustResolve[*Service](canary); svc.Name != "experimental" {
+		t.Errorf("Expected the dependent singleton rebuilt in the scope, got '%s'", svc.Name)
+	}
+	if svc := dshot.MustResolve[*Service](canary); svc != dshot.MustResolve[*Service](canary) {
+		t.Error("Expected the rebuilt singleton cached in the scope")
+	}
+	if dshot.MustResolve[*ComplexService](canary) != unrelated {
+		t.Error("Expected unaffected singletons shared with the parent")
+	}
+	if svc := dshot.MustResolve[*Service](app); svc != before || svc.Name != "stable" {
+		t.Errorf("Expected the parent untouched, got '%s'", svc.Name)
+	}
+}
+
+type overrideDeps struct {
+	dshot.In
+	Engine *Database `dshot:"name=pricing-engine"`
+}
+
+func TestOverrideToken_ReachedThroughInStructsAndGroups(t *testing.T) {
+	engineToken := dshot.NewToken[*Database]("pricing-engine")
+
+	app := dshot.New()
+	app.Register(dshot.Bind(engineToken, &Database{ConnectionString: "stable"}))
+	dshot.ProvideAutoFactory(func(deps overrideDeps) *Repository {
+		return &Repository{DB: deps.Engine}
+	}, app)
+	dshot.ProvideAutoFactory(func(engines []*Database) *Service {
+		return &Service{Name: engines[0].ConnectionString}
+	}, app)
+
+	canary := dshot.NewScoped(app, "canary")
+	dshot.OverrideToken(canary, engineToken, func() *Database {
+		return &Database{ConnectionString: "experimental"}
+	})
+
+	if repo := dshot.MustResolve[*Repository](canary); repo.DB.ConnectionString != "experimental" {
+		t.Errorf("Expected the In struct dependent rebuilt, got '%s'", repo.DB.ConnectionString)
+	}
+	if svc := dshot.MustResolve[*Service](canary); svc.Name != "experimental" {
+		t.Errorf("Expected the group dependent rebuilt, got '%s'", svc.Name)
+	}
+	if repo := dshot.MustResolve[*Repository](app); repo.DB.ConnectionString != "stable" {
+		t.Errorf("Expected the parent untouched, got '%s'", repo.DB.ConnectionString)
+	}
+}
+
+func TestCanary_PicksVariantPerScope(t *testing.T) {
+	dbToken := dshot.NewToken[*Database]("db")
+	newStable := func() *Database { return &Database{ConnectionString: "stable"} }
+	newCanary := func() *Database { return &Database{ConnectionString: "canary"} }
+
+	for _, tt := range []struct {
+		percent float64
+		want    string
+	}{{0, "stable"}, {100, "canary"}} {
+		app := dshot.New()
+		app.Register(dshot.Canary(tt.percent,
+			dshot.BindAutoFactory(dbToken, newStable, app),
+			dshot.BindAutoPrototype(dbToken, newCanary, app),
+		))
+
+		for range 3 {
+			scope := dshot.NewScoped(app, "request")
+			if db := dshot.Get(dbToken, scope); db.ConnectionString != tt.want {
+				t.Errorf("percent %v: expected %s, got %s", tt.percent, tt.want, db.ConnectionString)
+			}
+			if v, ok := scope.ServedVariant(dbToken); !ok || v != tt.want {
+				t.Errorf("percent %v: expected the scope served by %s, got %q", tt.percent, tt.want, v)
+			}
+		}
+
+		usage := app.VariantUsage(dbToken)
+		if len(usage) != 2 || usage[0].Variant != "stable" || usage[0].Scopes+usage[1].Scopes != 3 {
+			t.Errorf("percent %v: unexpected usage %+v", tt.percent, usage)
+		}
+	}
+}
+
+func TestVariants_SelectorReadsScopeValues(t *testing.T) {
+	type tenantKey struct{}
+	dbToken := dshot.NewToken[*Database]("db")
+
+	app := dshot.New()
+	var calls atomic.Int32
+	selector := dshot.VariantSelectorFunc(func(scope *dshot.Container, variants []string) string {
+		calls.Add(1)
+		tenant, _ := scope.GetValue(tenantKey{})
+		if tenant == "beta-corp" {
+			return "v2"
+		}
+		return "unknown"
+	})
+	app.Register(dshot.Variants(selector,
+		dshot.Variant[*Database]{Name: "control", Registration: dshot.Bind(dbToken, &Database{ConnectionString: "control"})},
+		dshot.Variant[*Database]{Name: "v2", Registration: dshot.Bind(dbToken, &Database{ConnectionString: "v2"})},
+	))
+
+	for tenant, want := range map[string]string{"beta-corp": "v2", "acme": "control"} {
+		scope := dshot.NewScoped(app, "request")
+		scope.SetValue(tenantKey{}, tenant)
+		dshot.Get(dbToken, scope)
+		if db := dshot.Get(dbToken, scope); db.ConnectionString != want {
+			t.Errorf("%s: expected %s, got %s", tenant, want, db.ConnectionString)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected the selector called once per scope, got %d", calls.Load())
+	}
+}
+
+func TestResolveContainer_RegistrationTakesPrecedence(t *testing.T) {
+	app := dshot.New()
+	other := dshot.New()
+	app.Provide(other)
+
+	if got := dshot.MustResolve[*dshot.Container](app); got != other {
+		t.Error("Expected a registered *Container to win over the resolving container")
+	}
+	if got := dshot.MustResolve[*dshot.Container](other); got != other {
+		t.Error("Expected the resolving container when none is registered")
+	}
+}
@@ -0,0 +1,116 @@
+package dshot
+
+import (
+	"sync"
+)
+
+// scopedSlot holds the instance of a Scoped entry in one scope
+type scopedSlot struct {
+	mu    sync.Mutex
+	done  bool
+	value any
+}
+
+// resolveEntry resolves e on behalf of c, which is the scope for Scoped entries
+func (c *Container) resolveEntry(e *entry, r *resolution) (any, error) {
+	if err := c.checkFailFast(); err != nil {
+		return nil, err
+	}
+	if err := r.checkVisible(e); err != nil {
+		return nil, err
+	}
+	if c.overridesAffect(e) {
+		return c.resolveOverridden(e, r)
+	}
+	if e.variants != nil {
+		return c.resolveVariant(e, r)
+	}
+
+	if e.factory == nil {
+		return e.value, nil
+	}
+
+	switch e.lifecycle {
+	case Scoped:
+		return c.resolveScoped(e, r)
+	case Prototype:
+		// Prototypes built for a scope are disposed with it, see Dispose
+		if r == nil || r.disposer == nil {
+			if scope := c.disposalScope(); scope != nil {
+				r = r.disposedBy(scope)
+			}
+		}
+	}
+
+	return e.resolve(r)
+}
+
+// disposalScope returns the scope disposing prototypes resolved through c, or nil if c
+// is a long-lived container, so that prototypes do not pile up disposers until it closes
+func (c *Container) disposalScope() *Container {
+	scope := c
+	for scope.view {
+		scope = scope.parent
+	}
+	if !scope.ephemeral {
+		return nil
+	}
+	return scope
+}
+
+// resolveScoped returns the instance of e for the scope c, constructing it on first use.
+// The factory resolves its dependencies from the scope, and the instance is disposed
+// when the scope is closed.
+func (c *Container) resolveScoped(e *entry, r *resolution) (any, error) {
+	scope := c
+	for scope.view {
+		scope = scope.parent
+	}
+
+	if err := e.awaitReady(r); err != nil {
+		return nil, err
+	}
+
+	inner, err := r.enter(e)
+	if err != nil {
+		return nil, err
+	}
+
+	slot := scope.scopedSlot(e)
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+
+	if slot.done {
+		return slot.value, nil
+	}
+
+	// Scoped instances are owned by the scope, not by the resolving call
+	val, err := e.build(&resolution{chain: inner.chain, scope: scope, ctx: inner.ctx, disposer: scope})
+	if err != nil {
+		return nil, err
+	}
+
+	slot.value = val
+	slot.done = true
+	scope.trackDisposal(e, val)
+
+	return val, nil
+}
+
+// scopedSlot returns the slot of e in this scope, creating it if needed
+func (c *Container) scopedSlot(e *entry) *scopedSlot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.scoped == nil {
+		c.scoped = make(map[*entry]*scopedSlot)
+	}
+
+	slot, ok := c.scoped[e]
+	if !ok {
+		slot = &scopedSlot{}
+		c.scoped[e] = slot
+	}
+
+	return slot
+}
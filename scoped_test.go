@@ -0,0 +1,50 @@
+package dshot_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type unitOfWork struct {
+	Request *Service
+	closed  bool
+}
+
+func (u *unitOfWork) Close() error {
+	u.closed = true
+	return nil
+}
+
+func TestScopedLifecycle_OneInstancePerScope(t *testing.T) {
+	root := dshot.New()
+	dshot.ProvideAutoScoped(func(req *Service) *unitOfWork {
+		return &unitOfWork{Request: req}
+	}, root)
+
+	req1 := dshot.NewScoped(root, "request")
+	req1.Provide(&Service{Name: "req1"})
+	req2 := dshot.NewScoped(root, "request")
+	req2.Provide(&Service{Name: "req2"})
+
+	uow1 := dshot.MustResolve[*unitOfWork](req1)
+	if again := dshot.MustResolve[*unitOfWork](req1); again != uow1 {
+		t.Error("Expected the same instance within a scope")
+	}
+
+	uow2 := dshot.MustResolve[*unitOfWork](req2)
+	if uow2 == uow1 {
+		t.Error("Expected a new instance per scope")
+	}
+	if uow1.Request.Name != "req1" || uow2.Request.Name != "req2" {
+		t.Errorf("Expected scope dependencies, got %s and %s", uow1.Request.Name, uow2.Request.Name)
+	}
+
+	if err := req1.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !uow1.closed || uow2.closed {
+		t.Error("Expected only the closed scope's instance to be disposed")
+	}
+}
@@ -0,0 +1,85 @@
+package dshot
+
+import (
+	"sync"
+)
+
+// OverrideToken registers factory for token in scope, shadowing the parent registrations
+// of token within the scope only, even when the parent instance is already built. Parent
+// singletons and prototypes depending on token, directly or through other auto-wired
+// factories, are rebuilt within the scope on first resolution (copy-on-resolve), so they
+// see the override while the rest of the application keeps its instances.
+//
+// Example:
+//
+//	canary := container.NewScoped(app, "canary")
+//	container.OverrideToken(canary, pricingToken, NewExperimentalPricing)
+//	checkout := container.MustResolve[*Checkout](canary) // Built with the experimental pricing
+func OverrideToken[T any](scope *Container, token *Token[T], factory any) {
+	if scope == nil || scope.parent == nil {
+		panic("OverrideToken: scope must be a child container")
+	}
+
+	reg := BindAutoFactory(token, factory, scope)
+	reg.override = true
+	scope.Replace(reg)
+	scope.overridden.Store(&sync.Map{})
+}
+
+// overridesAffect reports whether e, registered in a parent, depends on a registration
+// overridden in c or one of its parents, see OverrideToken
+func (c *Container) overridesAffect(e *entry) bool {
+	if e.owner == c || e.wiring == nil || e.lifecycle == Scoped {
+		return false
+	}
+
+	var cache *sync.Map
+	for cur := c; cur != nil && cur != e.owner && cache == nil; cur = cur.parent {
+		cache = cur.overridden.Load()
+	}
+	if cache == nil {
+		return false
+	}
+
+	if affected, ok := cache.Load(e); ok {
+		return affected.(bool)
+	}
+	affected := c.dependsOnOverride(e)
+	cache.Store(e, affected)
+	return affected
+}
+
+// dependsOnOverride walks the dependencies of e as Validate does (parameters, In structs,
+// named fields, groups), resolved from c. Scoped dependencies are not followed: they are
+// built within the scope anyway.
+func (c *Container) dependsOnOverride(e *entry) bool {
+	v := &validator{done: make(map[*entry]bool), reached: make(map[*entry]bool), from: c}
+	v.visit(e, nil)
+
+	for reached := range v.reached {
+		if reached.override {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveOverridden builds e within the scope c, so its dependencies resolve from c
+func (c *Container) resolveOverridden(e *entry, r *resolution) (any, error) {
+	if e.lifecycle == Singleton {
+		return c.resolveScoped(e, r)
+	}
+
+	inner, err := r.enter(e)
+	if err != nil {
+		return nil, err
+	}
+	inner.scope = c
+
+	val, err := e.build(inner)
+	if err != nil {
+		return nil, err
+	}
+	r.disposedBy(c).trackBuilt(e, val)
+	return val, nil
+}
@@ -0,0 +1,76 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ProviderSet is a reusable bundle of providers, see Set
+type ProviderSet struct {
+	items    []any
+	profiles []string // Installed only when one is active, see When
+}
+
+// Set groups providers into a bundle that services install with Container.Install.
+// Items are auto-wired factories (func(...) T, func(...) (T, error) or factories returning
+// a cleanup function), token registrations, values provided by type and other sets.
+// A set included several times, e.g. through two sets that both include it, is installed
+// once. Unlike modules, sets carry no name and their registrations stay public.
+//
+// Example:
+//
+//	var PostgresSet = container.Set(NewPool, NewTxManager, container.Bind(dsnToken, dsn))
+//	var ObservabilitySet = container.Set(NewLogger, NewMetrics)
+//	var AppSet = container.Set(PostgresSet, ObservabilitySet, NewOrderService)
+//
+//	c.Install(AppSet)
+func Set(items ...any) *ProviderSet {
+	for i, item := range items {
+		if item == nil {
+			panic(fmt.Sprintf("Set: item %d is nil", i))
+		}
+	}
+	return &ProviderSet{items: items}
+}
+
+// Install registers the providers of sets in the container, in order.
+//
+// Example:
+//
+//	c.Install(PostgresSet, ObservabilitySet)
+func (c *Container) Install(sets ...*ProviderSet) {
+	c.checkWritable("Install")
+
+	installed := make(map[*ProviderSet]bool)
+	for _, set := range sets {
+		set.installTo(c, installed)
+	}
+}
+
+// installTo registers the items of s, skipping sets already installed
+func (s *ProviderSet) installTo(c *Container, installed map[*ProviderSet]bool) {
+	if installed[s] {
+		return
+	}
+	installed[s] = true
+
+	if s.profiles != nil && !c.anyProfileActive(s.profiles) {
+		return
+	}
+
+	for _, item := range s.items {
+		switch item := item.(type) {
+		case *ProviderSet:
+			item.installTo(c, installed)
+		case registration:
+			c.Register(item)
+		default:
+			if fnType := reflect.TypeOf(item); fnType.Kind() == reflect.Func {
+				withError := fnType.NumOut() == 2 && fnType.Out(1) == errorType
+				c.provideAutoFactoryWithLifecycle(item, Singleton, withError)
+			} else {
+				c.Provide(item)
+			}
+		}
+	}
+}
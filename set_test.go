@@ -0,0 +1,52 @@
+package dshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type setConfig struct {
+	Env string
+}
+
+func TestSet_ComposedAndInstalledOnce(t *testing.T) {
+	dbToken := dshot.NewToken[*Database]("db")
+	built := 0
+
+	storage := dshot.Set(
+		dshot.Bind(dbToken, &Database{ConnectionString: "postgres"}),
+		func(c *dshot.Container) *Repository {
+			built++
+			return &Repository{DB: dshot.Get(dbToken, c)}
+		},
+	)
+	services := dshot.Set(storage, func(repo *Repository) (*Service, error) {
+		if repo == nil {
+			return nil, errors.New("no repository")
+		}
+		return &Service{Name: repo.DB.ConnectionString}, nil
+	})
+	app := dshot.Set(storage, services, &setConfig{Env: "app"})
+
+	c := dshot.New()
+	c.Install(app)
+
+	if svc := dshot.MustResolve[*Service](c); svc.Name != "postgres" {
+		t.Errorf("Expected 'postgres', got '%s'", svc.Name)
+	}
+	if cfg := dshot.MustResolve[*setConfig](c); cfg.Env != "app" {
+		t.Errorf("Expected 'app', got '%s'", cfg.Env)
+	}
+	n := 0
+	for range c.All() {
+		n++
+	}
+	if n != 4 {
+		t.Errorf("Expected the shared set installed once (4 registrations), got %d", n)
+	}
+	if built != 1 {
+		t.Errorf("Expected the repository built once, got %d", built)
+	}
+}
@@ -0,0 +1,172 @@
+package dshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+var snapshotterType = reflect.TypeFor[Snapshotter]()
+
+// Snapshotter is implemented by expensive-to-build singletons, such as caches and indexes,
+// whose state can be persisted and restored, see WithSnapshots
+type Snapshotter interface {
+	Snapshot() ([]byte, error)
+	Restore(data []byte) error
+}
+
+// SnapshotStore persists singleton snapshots by key, see WithSnapshots
+type SnapshotStore interface {
+	Load(key string) (data []byte, ok bool, err error) // ok is false if there is no snapshot
+	Save(key string, data []byte) error
+}
+
+// WithSnapshots persists the state of singletons implementing Snapshotter in store when the
+// container is closed, and rehydrates them on the next start before falling back to full
+// construction, cutting restart time for caches and indexes. A singleton registered with a
+// pointer type is restored into a new zero value, so its factory and dependencies are
+// skipped: the snapshot must hold everything it needs. Restored values still go through
+// interceptors, nil checks and post-construction. A missing or failing snapshot falls back
+// to the factory, reporting failures as warnings. Snapshots are keyed by the container
+// namespace, the package path of the type and the token name. Scopes do not inherit the
+// store: only singletons registered in c are snapshotted.
+//
+// Example:
+//
+//	c := container.New(container.WithSnapshots(container.DirSnapshotStore("/var/cache/app")))
+//	container.ProvideAutoFactory(BuildSearchIndex, c) // Built once, restored afterwards
+//	defer c.Close(ctx)                                // Saves the index
+func WithSnapshots(store SnapshotStore) Option {
+	return func(c *Container) {
+		c.snapshots = store
+	}
+}
+
+// snapshottable reports whether singletons of e are snapshotted
+func (e *entry) snapshottable() bool {
+	return e.lifecycle == Singleton && e.owner != nil && e.owner.snapshots != nil && e.depType != nil &&
+		e.depType.Kind() == reflect.Ptr && e.depType.Implements(snapshotterType)
+}
+
+// snapshotKey identifies the singleton of e across restarts, e.g.
+// "billing:example.com/app/search.Index#products"
+func (e *entry) snapshotKey() string {
+	elem := e.depType.Elem()
+	key := elem.PkgPath() + "." + elem.Name()
+
+	if ns := e.owner.namespace; ns != "" {
+		key = ns + ":" + key
+	}
+	if k, ok := e.token.(*tokenKey); !ok || k.key != providedKey(e.depType) {
+		key += "#" + tokenName(e.token)
+	}
+	return key
+}
+
+// restoringFactory returns the factory of e, restoring the singleton from its snapshot
+// instead of calling it when there is one
+func (e *entry) restoringFactory() func(r *resolution) (any, error) {
+	factory := e.factory
+	if factory == nil || !e.snapshottable() {
+		return factory
+	}
+
+	return func(r *resolution) (any, error) {
+		if val, ok := e.restoreSnapshot(); ok {
+			return val, nil
+		}
+		return factory(r)
+	}
+}
+
+// restoreSnapshot returns a singleton of e rehydrated from its snapshot, if any
+func (e *entry) restoreSnapshot() (any, bool) {
+	c := e.owner
+	data, ok, err := c.snapshots.Load(e.snapshotKey())
+	if err == nil && ok {
+		val := reflect.New(e.depType.Elem()).Interface()
+		if err = val.(Snapshotter).Restore(data); err == nil {
+			return val, true
+		}
+	}
+
+	if err != nil && c.warnEnabled() {
+		c.warn(Warning{
+			Kind:    WarnSnapshot,
+			Message: fmt.Sprintf("Cannot restore snapshot of %s, constructing it: %v", e.label(), err),
+			Attrs:   []slog.Attr{slog.String("token", e.label()), slog.Any("error", err)},
+		})
+	}
+	return nil, false
+}
+
+// trackSnapshot schedules saving the snapshot of a singleton of e when c is closed,
+// before the singleton itself is disposed
+func (c *Container) trackSnapshot(e *entry, val any) {
+	snap, ok := val.(Snapshotter)
+	if !ok || !e.snapshottable() {
+		return
+	}
+
+	c.addDisposer(func(context.Context) error {
+		data, err := snap.Snapshot()
+		if err == nil {
+			err = c.snapshots.Save(e.snapshotKey(), data)
+		}
+		if err != nil {
+			return fmt.Errorf("snapshot %s: %w", e.label(), err)
+		}
+		return nil
+	})
+}
+
+// DirSnapshotStore returns a SnapshotStore keeping one file per singleton in dir,
+// which is created on the first save.
+//
+// Example:
+//
+//	store := container.DirSnapshotStore(filepath.Join(os.TempDir(), "app-snapshots"))
+func DirSnapshotStore(dir string) SnapshotStore {
+	return dirSnapshotStore(dir)
+}
+
+type dirSnapshotStore string
+
+func (d dirSnapshotStore) Load(key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	return data, err == nil, err
+}
+
+func (d dirSnapshotStore) Save(key string, data []byte) error {
+	if err := os.MkdirAll(string(d), 0o755); err != nil {
+		return err
+	}
+
+	// Written aside and renamed, so a crash never leaves a truncated snapshot
+	tmp := d.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.path(key))
+}
+
+// path maps a key, e.g. example.com/app/search.Index, to a file name
+func (d dirSnapshotStore) path(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, key)
+	return filepath.Join(string(d), name+".snapshot")
+}
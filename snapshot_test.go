@@ -0,0 +1,114 @@
+package dshot_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type snapshotIndex struct {
+	terms []string
+}
+
+func (i *snapshotIndex) Snapshot() ([]byte, error) {
+	return []byte(strings.Join(i.terms, ",")), nil
+}
+
+func (i *snapshotIndex) Restore(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty snapshot")
+	}
+	i.terms = strings.Split(string(data), ",")
+	return nil
+}
+
+func TestWithSnapshots_RestoresBeforeConstruction(t *testing.T) {
+	store := dshot.DirSnapshotStore(t.TempDir())
+
+	builds := 0
+	start := func() *dshot.Container {
+		c := dshot.New(dshot.WithSnapshots(store))
+		dshot.ProvideAutoFactory(func() *snapshotIndex {
+			builds++
+			return &snapshotIndex{terms: []string{"go", "di"}}
+		}, c)
+		return c
+	}
+
+	first := start()
+	dshot.MustResolve[*snapshotIndex](first).terms = append(dshot.MustResolve[*snapshotIndex](first).terms, "snapshot")
+	if err := first.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	second := start()
+	idx := dshot.MustResolve[*snapshotIndex](second)
+	if builds != 1 {
+		t.Errorf("Expected the restart to skip construction, got %d builds", builds)
+	}
+	if strings.Join(idx.terms, ",") != "go,di,snapshot" {
+		t.Errorf("Expected the saved state restored, got %v", idx.terms)
+	}
+
+	if err := store.Save("github.com/overdevelop/dshot_test.snapshotIndex", nil); err != nil {
+		t.Fatal(err)
+	}
+	var warnings []dshot.Warning
+	third := start()
+	third.SetWarningHandler(func(w dshot.Warning) { warnings = append(warnings, w) })
+	dshot.MustResolve[*snapshotIndex](third)
+	if builds != 2 || len(warnings) != 1 || warnings[0].Kind != dshot.WarnSnapshot {
+		t.Errorf("Expected a failed restore to fall back to the factory with a warning, got %d builds, %+v", builds, warnings)
+	}
+}
+
+type memSnapshotStore map[string][]byte
+
+func (m memSnapshotStore) Load(key string) ([]byte, bool, error) {
+	data, ok := m[key]
+	return data, ok, nil
+}
+
+func (m memSnapshotStore) Save(key string, data []byte) error {
+	m[key] = data
+	return nil
+}
+
+func TestWithSnapshots_RestoredValuesArePostConstructed(t *testing.T) {
+	store := memSnapshotStore{"tenant:github.com/overdevelop/dshot_test.snapshotIndex#index": []byte("restored")}
+
+	var initialized []string
+	c := dshot.New(
+		dshot.WithSnapshots(store),
+		dshot.WithNamespace("tenant"),
+		dshot.WithPostConstruct(func(ctx context.Context, v any) error {
+			if idx, ok := v.(*snapshotIndex); ok {
+				initialized = append(initialized, idx.terms...)
+			}
+			return nil
+		}),
+	)
+	c.Register(dshot.BindAutoFactory(dshot.NewToken[*snapshotIndex]("index"), func() *snapshotIndex {
+		return &snapshotIndex{terms: []string{"built"}}
+	}, c))
+
+	scope := dshot.NewScoped(c)
+	dshot.ProvideAutoFactory(func() *snapshotIndex { return &snapshotIndex{terms: []string{"scoped"}} }, scope)
+	dshot.MustResolve[*snapshotIndex](scope)
+	if err := scope.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(store) != 1 {
+		t.Errorf("Expected scope singletons not snapshotted, got %v", store)
+	}
+
+	if idx := dshot.MustResolve[*snapshotIndex](c); strings.Join(idx.terms, ",") != "restored" {
+		t.Errorf("Expected the namespaced token snapshot restored, got %v", idx.terms)
+	}
+	if strings.Join(initialized, ",") != "scoped,restored" {
+		t.Errorf("Expected the restored value post-constructed, got %v", initialized)
+	}
+}
@@ -0,0 +1,200 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"weak"
+)
+
+// maxSuggestions bounds the "did you mean" list of not-found errors
+const maxSuggestions = 3
+
+// addChild records a scope so that not-found errors in its siblings can point to it.
+// Children are held weakly, so abandoned scopes are still garbage collected.
+func (c *Container) addChild(child *Container) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.children) >= 64 && len(c.children) >= 2*c.liveChildren {
+		c.children = slices.DeleteFunc(c.children, func(p weak.Pointer[Container]) bool {
+			return p.Value() == nil
+		})
+		c.liveChildren = len(c.children)
+	}
+
+	c.children = append(c.children, weak.Make(child))
+}
+
+// siblings returns the open scopes sharing a parent with c (or one of its ancestors)
+func (c *Container) siblings() []*Container {
+	var result []*Container
+
+	for cur := c; cur.parent != nil; cur = cur.parent {
+		cur.parent.mu.RLock()
+		children := cur.parent.children
+		cur.parent.mu.RUnlock()
+
+		for _, p := range children {
+			if sib := p.Value(); sib != nil && sib != cur && !sib.closed.Load() {
+				result = append(result, sib)
+			}
+		}
+	}
+
+	return result
+}
+
+// notFound returns ErrNotFound for targetType, with suggestions when some are found
+func (c *Container) notFound(targetType reflect.Type) error {
+	if hint := c.typeHint(targetType); hint != "" {
+		return fmt.Errorf("%w (%s)", ErrNotFound, hint)
+	}
+	return ErrNotFound
+}
+
+// tokenNotFound returns ErrNotFound for token, with suggestions when some are found
+func (c *Container) tokenNotFound(token any) error {
+	if hint := c.tokenHint(token); hint != "" {
+		return fmt.Errorf("%w: %v (%s)", ErrNotFound, token, hint)
+	}
+	return fmt.Errorf("%w: %v", ErrNotFound, token)
+}
+
+// tokenHint suggests registered token names close to the requested one
+func (c *Container) tokenHint(token any) string {
+	name := tokenName(token)
+
+	type match struct {
+		name string
+		dist int
+	}
+	var matches []match
+	seen := make(map[string]bool)
+
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		for key, e := range cur.registry {
+			if k, ok := key.(*tokenKey); ok && k.key == providedKey(e.depType) {
+				continue
+			}
+			candidate := tokenName(key)
+			if seen[candidate] {
+				continue
+			}
+			seen[candidate] = true
+
+			limit := max(2, len(name)/3)
+			if d := levenshtein(strings.ToLower(name), strings.ToLower(candidate)); d <= limit {
+				matches = append(matches, match{candidate, d})
+			}
+		}
+		cur.mu.RUnlock()
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.dist != b.dist {
+			return a.dist - b.dist
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	var hints []string
+	for i, m := range matches {
+		if i == maxSuggestions {
+			break
+		}
+		hints = append(hints, fmt.Sprintf("%q", m.name))
+	}
+
+	if len(hints) == 0 {
+		for _, sib := range c.siblings() {
+			sib.mu.RLock()
+			_, ok := sib.registry[token]
+			sib.mu.RUnlock()
+			if ok {
+				return fmt.Sprintf("registered only in sibling scope %q", sib.name)
+			}
+		}
+		return ""
+	}
+
+	return "did you mean " + strings.Join(hints, ", ") + "?"
+}
+
+// typeHint suggests registered types that differ from targetType only in pointer-ness
+// or package, and sibling scopes registering targetType
+func (c *Container) typeHint(targetType reflect.Type) string {
+	want := baseType(targetType)
+
+	var hints []string
+	seen := make(map[reflect.Type]bool)
+
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		for _, e := range cur.entries {
+			typ := e.depType
+			if typ == nil || typ == targetType || seen[typ] {
+				continue
+			}
+			base := baseType(typ)
+			if base == want || (base.Name() != "" && base.Name() == want.Name()) {
+				seen[typ] = true
+				hints = append(hints, typ.String())
+			}
+		}
+		cur.mu.RUnlock()
+	}
+
+	if len(hints) > maxSuggestions {
+		hints = hints[:maxSuggestions]
+	}
+	if len(hints) > 0 {
+		return "did you mean " + strings.Join(hints, ", ") + "?"
+	}
+
+	for _, sib := range c.siblings() {
+		sib.mu.RLock()
+		found := slices.ContainsFunc(sib.entries, func(e *entry) bool {
+			return e.depType == targetType
+		})
+		sib.mu.RUnlock()
+		if found {
+			return fmt.Sprintf("registered only in sibling scope %q", sib.name)
+		}
+	}
+
+	return ""
+}
+
+// baseType strips pointers from typ
+func baseType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,54 @@
+package dshot_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestGetE_SuggestsCloseTokenNames(t *testing.T) {
+	c := dshot.New()
+	c.Register(dshot.Bind(dshot.NewToken[string]("databaseURL"), "postgres://"))
+
+	_, err := dshot.GetE(dshot.NewToken[string]("databaseUrl"), c)
+	if !errors.Is(err, dshot.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `did you mean "databaseURL"?`) {
+		t.Errorf("Expected suggestion in %q", err)
+	}
+}
+
+func TestResolveE_SuggestsPointerness(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{})
+
+	_, err := dshot.ResolveE[**Database](c)
+	if !errors.Is(err, dshot.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "did you mean *dshot_test.Database?") {
+		t.Errorf("Expected suggestion in %q", err)
+	}
+}
+
+func TestResolveE_SuggestsSiblingScope(t *testing.T) {
+	root := dshot.New()
+	admin := dshot.NewScoped(root, "admin")
+	admin.Provide(&Database{})
+	public := dshot.NewScoped(root, "public")
+
+	_, err := dshot.ResolveE[*Database](public)
+	if !strings.Contains(err.Error(), `registered only in sibling scope "admin"`) {
+		t.Errorf("Expected sibling scope hint in %q", err)
+	}
+
+	admin.Close(t.Context())
+
+	_, err = dshot.ResolveE[*Database](public)
+	if strings.Contains(err.Error(), "sibling") {
+		t.Errorf("Expected no hint for a closed scope, got %q", err)
+	}
+}
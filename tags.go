@@ -0,0 +1,88 @@
+package dshot
+
+import (
+	"slices"
+)
+
+// WithTags attaches free-form tags to the registration, e.g. "handler" or "v2", for
+// discovery across types with ResolveTagged. Tags are groups (see InGroup): a tagged
+// registration is also in ResolveGroup's results, and its tags are listed in
+// RegistrationInfo.Groups.
+//
+// Example:
+//
+//	container.Register(
+//	    container.BindAutoFactory(ordersToken, NewOrdersHandler).WithTags("handler", "v2"),
+//	    container.BindAutoFactory(auditToken, NewAuditLog).WithTags("handler"),
+//	)
+func (r Registration[T]) WithTags(tags ...string) Registration[T] {
+	return r.InGroup(tags...)
+}
+
+// ResolveTagged returns the values of every registration carrying all of tags, whatever
+// their type, in registration order followed by those of parent containers. A registration
+// in a scope shadows the parent's under the same token, or of the same type for
+// registrations made by type, and registrations private to a module are skipped.
+// It panics if one of them fails to resolve.
+//
+// Example:
+//
+//	for _, h := range c.ResolveTagged("handler") {
+//	    if route, ok := h.(Route); ok {
+//	        mux.Handle(route.Pattern(), route)
+//	    }
+//	}
+func (c *Container) ResolveTagged(tags ...string) []any {
+	vals, err := c.ResolveTaggedE(tags...)
+	if err != nil {
+		panic(err)
+	}
+	return vals
+}
+
+// ResolveTaggedE is ResolveTagged returning resolution failures instead of panicking
+func (c *Container) ResolveTaggedE(tags ...string) ([]any, error) {
+	var ordered []orderedValue
+	var restrictions []*Container
+	shadowed := make(map[any]bool) // Tokens, or types of type-based registrations
+
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		entries := cur.entries
+		cur.mu.RUnlock()
+
+		for _, e := range entries {
+			key := e.token
+			if e.typeBased() {
+				key = e.depType
+			}
+			if shadowed[key] || !hasTags(e.groups, tags) || !allowedBy(restrictions, e.depType) ||
+				!e.visibleTo(nil) {
+				continue
+			}
+			shadowed[key] = true
+
+			val, err := c.resolveEntry(e, nil)
+			if err != nil {
+				return nil, withStep(err, e.label())
+			}
+			ordered = append(ordered, orderedValue{e, val})
+		}
+
+		if cur.allow != nil {
+			restrictions = append(restrictions, cur)
+		}
+	}
+
+	return sortedValues(ordered), nil
+}
+
+// hasTags reports whether have contains every tag of want
+func hasTags(have, want []string) bool {
+	for _, tag := range want {
+		if !slices.Contains(have, tag) {
+			return false
+		}
+	}
+	return len(have) > 0
+}
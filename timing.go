@@ -0,0 +1,118 @@
+package dshot
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Construction reports how long a singleton factory took to build its value
+type Construction struct {
+	Info     RegistrationInfo
+	Duration time.Duration // Includes the construction of dependencies built by the factory
+}
+
+// SlowestConstructions returns the n slowest singleton constructions of this container,
+// slowest first. Only singletons that have already been built are reported.
+// A non-positive n returns all of them.
+//
+// Example:
+//
+//	for _, s := range c.SlowestConstructions(5) {
+//	    log.Printf("%s took %s", s.Info.Token, s.Duration)
+//	}
+func (c *Container) SlowestConstructions(n int) []Construction {
+	c.mu.RLock()
+	entries := c.entries
+	c.mu.RUnlock()
+
+	var result []Construction
+	for _, e := range entries {
+		if e.factory == nil || e.lifecycle != Singleton {
+			continue
+		}
+
+		e.mu.Lock()
+		done, d := e.done, e.buildTime
+		e.mu.Unlock()
+
+		if done {
+			result = append(result, Construction{Info: e.info(), Duration: d})
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b Construction) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+
+	return result
+}
+
+// Warmup constructs every singleton registered in this container, in registration order,
+// so configuration and connection errors fail fast at startup instead of on the first request.
+// All failures are reported, joined, as *ResolutionError values. Parent containers and
+// lazy modules that are not loaded yet are not warmed up.
+//
+// Concurrent calls share a single run: callers arriving while a warmup is in progress
+// wait for it and get its result, so a cold container under a burst of requests does
+// not retry failing factories once per caller. Factories must not call Warmup on the
+// container being warmed up.
+//
+// Example:
+//
+//	if err := c.Warmup(); err != nil {
+//	    log.Fatalf("startup: %v", err)
+//	}
+//	for _, s := range c.SlowestConstructions(3) {
+//	    log.Printf("slow: %s %s", s.Info.Token, s.Duration)
+//	}
+func (c *Container) Warmup() error {
+	c.mu.Lock()
+	if f := c.warming; f != nil {
+		c.mu.Unlock()
+		<-f.done
+		return f.err
+	}
+	f := &warmupFlight{done: make(chan struct{})}
+	c.warming = f
+	c.mu.Unlock()
+
+	f.err = c.warmup()
+
+	c.mu.Lock()
+	c.warming = nil
+	c.mu.Unlock()
+	close(f.done)
+
+	return f.err
+}
+
+// warmupFlight is a Warmup in progress, shared by concurrent callers
+type warmupFlight struct {
+	done chan struct{}
+	err  error
+}
+
+// warmup constructs the singletons of the container for Warmup
+func (c *Container) warmup() error {
+	c.mu.RLock()
+	entries := c.entries
+	c.mu.RUnlock()
+
+	var errs []error
+	for _, e := range entries {
+		if e.factory == nil || e.lifecycle != Singleton {
+			continue
+		}
+		if _, err := e.tryResolve(); err != nil {
+			errs = append(errs, withStep(err, e.label()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,121 @@
+package dshot_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestSlowestConstructions(t *testing.T) {
+	c := dshot.New()
+
+	c.ProvideFactory(func() *Database {
+		time.Sleep(10 * time.Millisecond)
+		return &Database{}
+	})
+	c.ProvideFactory(func() *Service {
+		return &Service{}
+	})
+	c.ProvidePrototype(func() *Repository {
+		return &Repository{}
+	})
+
+	if got := c.SlowestConstructions(0); len(got) != 0 {
+		t.Fatalf("Expected no constructions before resolution, got %d", len(got))
+	}
+
+	dshot.MustResolve[*Service](c)
+	dshot.MustResolve[*Database](c)
+	dshot.MustResolve[*Repository](c)
+
+	got := c.SlowestConstructions(1)
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 construction, got %d", len(got))
+	}
+	if got[0].Info.Type.String() != "*dshot_test.Database" {
+		t.Errorf("Expected *Database to be slowest, got %v", got[0].Info.Type)
+	}
+	if got[0].Duration < 10*time.Millisecond {
+		t.Errorf("Expected at least 10ms, got %s", got[0].Duration)
+	}
+
+	if all := c.SlowestConstructions(0); len(all) != 2 {
+		t.Errorf("Expected 2 singleton constructions, got %d", len(all))
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	c := dshot.New()
+	built := 0
+
+	c.ProvideFactory(func() *Database {
+		built++
+		return &Database{}
+	})
+	c.ProvidePrototype(func() *Service {
+		t.Error("Prototypes should not be built by Warmup")
+		return &Service{}
+	})
+	dshot.ProvideAutoFactory(func(cs *ComplexService) *Repository {
+		return &Repository{}
+	}, c)
+
+	err := c.Warmup()
+	if err == nil {
+		t.Fatal("Expected error for missing dependency")
+	}
+	if !strings.Contains(err.Error(), "*dshot_test.Repository -> param 0 (*dshot_test.ComplexService)") {
+		t.Errorf("Expected failing path in error, got %v", err)
+	}
+	if built != 1 {
+		t.Errorf("Expected singleton built once, got %d", built)
+	}
+}
+
+func TestWarmup_ConcurrentCallsShareOneRun(t *testing.T) {
+	synctest.Test(t, testWarmupSharesOneRun)
+}
+
+func testWarmupSharesOneRun(t *testing.T) {
+	c := dshot.New()
+	errDown := errors.New("database down")
+	var attempts atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	dshot.ProvideAutoFactoryErr(func() (*Database, error) {
+		if attempts.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return nil, errDown
+	}, c)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	wg.Go(func() { errs[0] = c.Warmup() })
+	<-started
+	for i := 1; i < len(errs); i++ {
+		wg.Go(func() { errs[i] = c.Warmup() })
+	}
+
+	// Every caller is blocked, on the factory or on the shared run
+	synctest.Wait()
+	close(release)
+	wg.Wait()
+
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("Expected one factory attempt, got %d", n)
+	}
+	for i, err := range errs {
+		if !errors.Is(err, errDown) {
+			t.Errorf("Caller %d: expected shared error, got %v", i, err)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package dshot
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
 
 type Token[T any] struct {
 	key string
@@ -10,6 +14,10 @@ type tokenKey struct {
 	key string
 }
 
+func (t *tokenKey) String() string {
+	return t.key
+}
+
 // NewToken creates a new typed token for dependency injection.
 // Optionally accepts a name; otherwise generates one from the type.
 func NewToken[T any](name ...string) *Token[T] {
@@ -45,3 +53,24 @@ func NewToken[T any](name ...string) *Token[T] {
 func (t *Token[T]) String() string {
 	return t.key
 }
+
+// providedKeys caches the token key of type-based registrations per type
+var providedKeys sync.Map // map[reflect.Type]string
+
+// providedKey returns the token key used for type-based registrations of typ
+func providedKey(typ reflect.Type) string {
+	if key, ok := providedKeys.Load(typ); ok {
+		return key.(string)
+	}
+
+	key, _ := providedKeys.LoadOrStore(typ, "__provided__"+typ.String())
+	return key.(string)
+}
+
+// tokenName returns the display name of a registry key
+func tokenName(token any) string {
+	if s, ok := token.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%v", token)
+}
@@ -0,0 +1,146 @@
+package dshot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Tx is a transaction that can be committed or rolled back, such as *sql.Tx
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// TxBeginner starts transactions for RunInTx. Register exactly one in the container.
+type TxBeginner interface {
+	BeginTx(ctx context.Context) (Tx, error)
+}
+
+// TxBeginnerFunc adapts a function to the TxBeginner interface
+type TxBeginnerFunc func(ctx context.Context) (Tx, error)
+
+// BeginTx calls f(ctx)
+func (f TxBeginnerFunc) BeginTx(ctx context.Context) (Tx, error) {
+	return f(ctx)
+}
+
+// SQLTxBeginner returns a TxBeginner starting database/sql transactions on db
+//
+// Example:
+//
+//	container.Provide(container.SQLTxBeginner(db, nil))
+func SQLTxBeginner(db *sql.DB, opts *sql.TxOptions) TxBeginner {
+	return TxBeginnerFunc(
+		func(ctx context.Context) (Tx, error) {
+			return db.BeginTx(ctx, opts)
+		},
+	)
+}
+
+// RunInTx runs fn as a unit of work inside a transaction.
+// A transaction is started with the container's TxBeginner and provided into a fresh scope
+// (under its concrete type, e.g. *sql.Tx), fn is invoked as with RunScoped, and the
+// transaction is committed if fn returns a nil error or rolled back otherwise.
+// The scope is closed only after the transaction has been committed or rolled back.
+//
+// Example:
+//
+//	err := container.RunInTx(ctx, func(ctx context.Context, tx *sql.Tx, repo *OrderRepo) error {
+//	    return repo.Save(ctx, tx, order)
+//	})
+func RunInTx(ctx context.Context, fn any, containers ...*Container) (err error) {
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "RunInTx")
+
+	val, ok := c.Resolve(reflect.TypeFor[TxBeginner]())
+	if !ok {
+		return errors.New("RunInTx: no TxBeginner registered")
+	}
+
+	tx, err := val.(TxBeginner).BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("RunInTx: begin transaction: %w", err)
+	}
+
+	scope := NewScoped(c, "tx")
+	scope.txScope = true
+
+	// Deferred first so the scope is closed after the rollback below
+	defer func() {
+		closeErr := scope.Close(context.WithoutCancel(ctx))
+		err = errors.Join(err, closeErr)
+	}()
+
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = errors.Join(err, fmt.Errorf("RunInTx: rollback: %w", rbErr))
+		}
+	}()
+
+	if err := scope.call(ctx, fn, []any{tx}, "RunInTx"); err != nil {
+		return err
+	}
+
+	committed = true
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("RunInTx: commit: %w", err)
+	}
+
+	return scope.runAfterCommit(context.WithoutCancel(ctx))
+}
+
+// AfterCommit registers fn to run after the enclosing RunInTx transaction commits successfully.
+// Callbacks run in registration order and are discarded if the transaction rolls back.
+// It panics if neither c nor any of its parents is a transactional scope.
+//
+// Example:
+//
+//	func (s *OrderService) Place(ctx context.Context, order *Order) error {
+//	    // ... write order using the transaction
+//	    s.scope.AfterCommit(func(ctx context.Context) error {
+//	        return s.events.Publish(ctx, OrderPlaced{ID: order.ID})
+//	    })
+//	    return nil
+//	}
+func (c *Container) AfterCommit(fn func(ctx context.Context) error) {
+	for cur := c; cur != nil; cur = cur.parent {
+		if cur.txScope {
+			cur.mu.Lock()
+			cur.afterCommit = append(cur.afterCommit, fn)
+			cur.mu.Unlock()
+			return
+		}
+	}
+
+	panic("AfterCommit: not inside a transactional scope (see RunInTx)")
+}
+
+// AfterCommit registers fn on the transactional scope attached to ctx.
+// See Container.AfterCommit.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context) error) {
+	FromContext(ctx).AfterCommit(fn)
+}
+
+// runAfterCommit runs the registered after-commit callbacks
+func (c *Container) runAfterCommit(ctx context.Context) error {
+	c.mu.Lock()
+	callbacks := c.afterCommit
+	c.afterCommit = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for _, fn := range callbacks {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
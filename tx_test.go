@@ -0,0 +1,150 @@
+package dshot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+type fakeTx struct {
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.rolledBack = true
+	return nil
+}
+
+func provideFakeBeginner(c *dshot.Container) *[]*fakeTx {
+	var txs []*fakeTx
+	c.Provide(dshot.TxBeginnerFunc(func(ctx context.Context) (dshot.Tx, error) {
+		tx := &fakeTx{}
+		txs = append(txs, tx)
+		return tx, nil
+	}))
+	return &txs
+}
+
+func TestRunInTx_CommitsOnSuccess(t *testing.T) {
+	c := dshot.New()
+	txs := provideFakeBeginner(c)
+
+	var injected *fakeTx
+	err := dshot.RunInTx(context.Background(), func(tx *fakeTx) error {
+		injected = tx
+		return nil
+	}, c)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if injected != (*txs)[0] {
+		t.Error("Transaction should be injected into fn")
+	}
+	if !injected.committed || injected.rolledBack {
+		t.Error("Transaction should be committed")
+	}
+}
+
+func TestRunInTx_RollsBackOnError(t *testing.T) {
+	c := dshot.New()
+	txs := provideFakeBeginner(c)
+	want := errors.New("failed")
+
+	err := dshot.RunInTx(context.Background(), func(tx dshot.Tx) error {
+		return want
+	}, c)
+
+	if !errors.Is(err, want) {
+		t.Fatalf("Expected %v, got %v", want, err)
+	}
+	tx := (*txs)[0]
+	if tx.committed || !tx.rolledBack {
+		t.Error("Transaction should be rolled back")
+	}
+}
+
+func TestRunInTx_ClosesScopeAfterCommit(t *testing.T) {
+	c := dshot.New()
+	provideFakeBeginner(c)
+
+	var committedAtClose bool
+	err := dshot.RunInTx(context.Background(), func(scope *dshot.Container, tx *fakeTx) error {
+		scope.OnScopeClose(func(ctx context.Context) error {
+			committedAtClose = tx.committed
+			return nil
+		})
+		return nil
+	}, c)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !committedAtClose {
+		t.Error("Transaction should be committed before the scope is closed")
+	}
+}
+
+func TestAfterCommit_RunsOnlyAfterCommit(t *testing.T) {
+	c := dshot.New()
+	provideFakeBeginner(c)
+
+	var calls []string
+	err := dshot.RunInTx(context.Background(), func(ctx context.Context, scope *dshot.Container) error {
+		dshot.AfterCommit(ctx, func(ctx context.Context) error {
+			calls = append(calls, "first")
+			return nil
+		})
+		scope.AfterCommit(func(ctx context.Context) error {
+			calls = append(calls, "second")
+			return nil
+		})
+		if len(calls) != 0 {
+			t.Error("Callbacks should not run before commit")
+		}
+		return nil
+	}, c)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Errorf("Expected callbacks in registration order, got %v", calls)
+	}
+}
+
+func TestAfterCommit_DiscardedOnRollback(t *testing.T) {
+	c := dshot.New()
+	provideFakeBeginner(c)
+
+	called := false
+	dshot.RunInTx(context.Background(), func(ctx context.Context) error {
+		dshot.AfterCommit(ctx, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		return errors.New("rollback")
+	}, c)
+
+	if called {
+		t.Error("After-commit callbacks should not run on rollback")
+	}
+}
+
+func TestAfterCommit_OutsideTxPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic outside a transactional scope")
+		}
+	}()
+
+	dshot.New().AfterCommit(func(ctx context.Context) error { return nil })
+}
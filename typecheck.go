@@ -0,0 +1,117 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypeMismatchError is returned, or panicked with, by the generic helpers when a resolved
+// value is not of the requested type, e.g. because an interceptor or a replaced registration
+// substituted another implementation.
+//
+// Example:
+//
+//	_, err := container.GetE(repoToken)
+//	var tm *container.TypeMismatchError
+//	if errors.As(err, &tm) {
+//	    log.Printf("%s is a %v, registered at %s", tm.Source, tm.Got, tm.Site)
+//	}
+type TypeMismatchError struct {
+	Want   reflect.Type
+	Got    reflect.Type // nil for a nil value
+	Source string       // Token, type or function that produced the value
+	Site   string       // Where the registration was made, empty if unknown
+}
+
+func (e *TypeMismatchError) Error() string {
+	got := "nil"
+	if e.Got != nil {
+		got = e.Got.String()
+	}
+
+	msg := fmt.Sprintf("%s: resolved %s, want %s", e.Source, got, e.Want)
+	if e.Site != "" {
+		msg += " (registered at " + e.Site + ")"
+	}
+	return msg
+}
+
+// as converts val to T, accepting nil for types that can be nil
+func as[T any](val any) (T, bool) {
+	typed, ok := val.(T)
+	if !ok && val == nil {
+		switch reflect.TypeFor[T]().Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return typed, true
+		}
+	}
+	return typed, ok
+}
+
+// typedByToken converts the value resolved for token to T
+func typedByToken[T any](val any, ci ContainerI, token any) (T, error) {
+	typed, ok := as[T](val)
+	if ok {
+		return typed, nil
+	}
+
+	if c, ok := ci.(*Container); ok {
+		if e, ok := c.lookupEntry(token); ok {
+			return typedEntry[T](val, e)
+		}
+	}
+	return typed, &TypeMismatchError{Want: reflect.TypeFor[T](), Got: reflect.TypeOf(val), Source: tokenName(token)}
+}
+
+// typedByType converts a value resolved by type to T. The registration site is reported
+// when a single registration matches T.
+func typedByType[T any](val any, ci ContainerI) (T, error) {
+	typed, ok := as[T](val)
+	if ok {
+		return typed, nil
+	}
+
+	want := reflect.TypeFor[T]()
+	if c, ok := ci.(*Container); ok {
+		if cand, ok, _ := c.findEntry(want, nil); ok {
+			return typedEntry[T](val, cand.e)
+		}
+	}
+	return typed, &TypeMismatchError{Want: want, Got: reflect.TypeOf(val), Source: want.String()}
+}
+
+// typedEntry converts the value resolved from e to T
+func typedEntry[T any](val any, e *entry) (T, error) {
+	typed, ok := as[T](val)
+	if ok {
+		return typed, nil
+	}
+	if converted, ok := e.convertDrift(reflect.TypeFor[T](), val); ok {
+		if typed, ok := as[T](converted); ok {
+			return typed, nil
+		}
+	}
+	return typed, &TypeMismatchError{
+		Want:   reflect.TypeFor[T](),
+		Got:    reflect.TypeOf(val),
+		Source: e.label(),
+		Site:   e.registrationSite(),
+	}
+}
+
+// typedResult converts the value returned by a called function to T
+func typedResult[T any](val any, source string) (T, error) {
+	typed, ok := as[T](val)
+	if ok {
+		return typed, nil
+	}
+	return typed, &TypeMismatchError{Want: reflect.TypeFor[T](), Got: reflect.TypeOf(val), Source: source}
+}
+
+// mustTyped panics with err if the conversion failed
+func mustTyped[T any](typed T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return typed
+}
@@ -0,0 +1,98 @@
+package dshot_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestTypeMismatch_ReportsTypesAndSite(t *testing.T) {
+	token := dshot.NewToken[*Service]("service")
+
+	c := dshot.New(dshot.WithRegistrationSites())
+	c.Register(dshot.BindAutoFactory(token, func() *Service { return &Service{Name: "real"} }, c))
+	c.AddInterceptor(func(info dshot.RegistrationInfo, next func() (any, error)) (any, error) {
+		return &Database{}, nil
+	})
+
+	_, err := dshot.GetE(token, c)
+	var tm *dshot.TypeMismatchError
+	if !errors.As(err, &tm) {
+		t.Fatalf("Expected a *TypeMismatchError, got %v", err)
+	}
+	if tm.Want.String() != "*dshot_test.Service" || tm.Got.String() != "*dshot_test.Database" {
+		t.Errorf("Expected want *Service, got *Database; got %v and %v", tm.Want, tm.Got)
+	}
+	if !strings.Contains(tm.Site, "typecheck_test.go:") {
+		t.Errorf("Expected the registration site, got %q", tm.Site)
+	}
+
+	defer func() {
+		if msg := fmt.Sprint(recover()); !strings.Contains(msg, "want *dshot_test.Service") {
+			t.Errorf("Expected Get to panic with the mismatch, got %q", msg)
+		}
+	}()
+	dshot.Get(token, c)
+}
+
+func TestCall_TypeMismatch(t *testing.T) {
+	c := dshot.New()
+
+	if r := dshot.Call[io.Reader](func() io.Reader { return nil }, c); r != nil {
+		t.Errorf("Expected a nil reader, got %v", r)
+	}
+
+	_, err := dshot.CallE[io.Reader](func() *Service { return &Service{} }, c)
+	var tm *dshot.TypeMismatchError
+	if !errors.As(err, &tm) || tm.Got.String() != "*dshot_test.Service" {
+		t.Errorf("Expected a *TypeMismatchError for *Service, got %v", err)
+	}
+}
+
+func TestWithTypeDrift(t *testing.T) {
+	token := dshot.NewToken[*Service]("service")
+	setup := func(c *dshot.Container) {
+		c.Register(dshot.BindAutoFactory(token, func() *Service { return &Service{Name: "real"} }, c))
+		c.AddInterceptor(func(info dshot.RegistrationInfo, next func() (any, error)) (any, error) {
+			return Service{Name: "drifted"}, nil
+		})
+	}
+
+	strict := dshot.New(dshot.WithSilentDiagnostics())
+	setup(strict)
+	if _, err := dshot.GetE(token, strict); err == nil {
+		t.Error("Expected the drift reported by default")
+	}
+	var tm *dshot.TypeMismatchError
+	if err := strict.Validate(); !errors.As(err, &tm) || tm.Source != "service" {
+		t.Errorf("Expected Validate to report the drifted token, got %v", err)
+	}
+
+	lenient := dshot.New(dshot.WithSilentDiagnostics(), dshot.WithTypeDrift(dshot.DriftConvert))
+	setup(lenient)
+	if svc := dshot.Get(token, lenient); svc.Name != "drifted" {
+		t.Errorf("Expected the drifted value converted, got %+v", svc)
+	}
+	if err := lenient.Validate(); err != nil {
+		t.Errorf("Expected a convertible drift accepted, got %v", err)
+	}
+}
+
+func TestValidate_FromWithinFactory(t *testing.T) {
+	c := dshot.New()
+	token := dshot.NewToken[*Service]("service")
+	var validateErr error
+	c.Register(dshot.BindAutoFactory(token, func() *Service {
+		validateErr = c.Validate()
+		return &Service{}
+	}, c))
+
+	dshot.Get(token, c)
+	if validateErr != nil {
+		t.Errorf("Expected a valid graph, got %v", validateErr)
+	}
+}
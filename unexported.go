@@ -0,0 +1,69 @@
+package dshot
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// unexportedTarget is an Inject target whose unexported fields are populated too
+type unexportedTarget struct {
+	target any
+}
+
+// IncludeUnexported marks an Inject target so that its unexported fields are populated
+// too, for service structs that keep their dependencies unexported by design. An
+// unexported field is set when its type (or the token named by its dshot tag) resolves,
+// and otherwise left as is, since such structs usually also hold private state like
+// mutexes and counters. It works with Inject, InjectE, InjectCtx and wrapped containers.
+//
+// Example:
+//
+//	type OrderService struct {
+//	    repo   *OrderRepo
+//	    events *EventBus
+//	    mu     sync.Mutex // Left alone
+//	}
+//
+//	svc := &OrderService{}
+//	c.Inject(container.IncludeUnexported(svc))
+func IncludeUnexported(target any) any {
+	return unexportedTarget{target: target}
+}
+
+// unwrapInjectTarget returns the Inject target and whether unexported fields are included
+func unwrapInjectTarget(target any) (any, bool) {
+	if u, ok := target.(unexportedTarget); ok {
+		return u.target, true
+	}
+	return target, false
+}
+
+// injectUnexported populates the unexported field of an addressable struct value
+// if it resolves
+func (c *Container) injectUnexported(field reflect.StructField, fieldValue reflect.Value, r *resolution) error {
+	tag, err := parseInjectTag(field)
+	if err != nil || tag.skip || isMarker(field.Type) {
+		return err
+	}
+
+	// Addressable, since Inject takes a pointer
+	fieldValue = reflect.NewAt(field.Type, unsafe.Pointer(fieldValue.UnsafeAddr())).Elem()
+
+	if tag.name != "" {
+		val, err := c.resolveNamed(tag.name, field.Type, tag.optional, r)
+		if err != nil {
+			return err
+		}
+		if val.IsValid() {
+			fieldValue.Set(val)
+		}
+		return nil
+	}
+
+	val, ok, err := c.resolveType(field.Type, r)
+	if err != nil || !ok {
+		return err
+	}
+	fieldValue.Set(reflect.ValueOf(val))
+	return nil
+}
@@ -0,0 +1,58 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RemoveUnreachable unregisters, in place, the registrations of the container that cannot
+// be reached from the given entry points, returning how many were removed; like Remove, it
+// cannot be undone, so build a fresh container to keep the full graph. It suits binaries
+// embedding a large shared wiring library but using a slice of it: the pruned container
+// holds less and validates faster. Entry points are tokens, reflect.Type values or functions, whose parameters are
+// the entry points (as passed to Call). Reachability follows the parameters of auto-wired
+// factories, including group members and every variant; the dependencies of other
+// factories, and what factories resolve through a *Container parameter, cannot be seen and
+// must be listed as entry points. Parent containers are not pruned. Call it once the
+// container is fully registered, before resolving.
+//
+// Example:
+//
+//	c := wiring.NewContainer() // Hundreds of registrations
+//	c.RemoveUnreachable(func(srv *http.Server, jobs *Scheduler) {}, metricsToken)
+//	if err := c.Validate(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Container) RemoveUnreachable(roots ...any) int {
+	c.checkWritable("RemoveUnreachable")
+
+	if err := c.loadModules(); err != nil {
+		panic(fmt.Sprintf("RemoveUnreachable: %v", err))
+	}
+
+	v := &validator{done: make(map[*entry]bool), reached: make(map[*entry]bool)}
+	for _, root := range roots {
+		switch root := root.(type) {
+		case reflect.Type:
+			v.visit(&entry{wiring: &wiring{c: c, params: []reflect.Type{root}}}, nil)
+		default:
+			if fnType := reflect.TypeOf(root); fnType != nil && fnType.Kind() == reflect.Func {
+				v.visit(&entry{wiring: newWiring(c, fnType)}, nil)
+				continue
+			}
+			e, ok := c.lookupEntry(root)
+			if !ok {
+				panic(fmt.Sprintf("RemoveUnreachable: %s is not registered", tokenName(root)))
+			}
+			v.visit(e, nil)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkWritable("RemoveUnreachable")
+	return c.removeEntries(func(e *entry) bool {
+		return !v.reached[e]
+	})
+}
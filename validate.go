@@ -0,0 +1,275 @@
+package dshot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// wiring records the parameters of an auto-wired factory and the container they are resolved from
+type wiring struct {
+	c      *Container
+	params []reflect.Type
+}
+
+func newWiring(c *Container, fnType reflect.Type) *wiring {
+	params := make([]reflect.Type, fnType.NumIn())
+	for i := range params {
+		params[i] = fnType.In(i)
+	}
+
+	return &wiring{c: c, params: params}
+}
+
+// Validate checks that every auto-wired factory visible from the container can have its
+// parameters resolved, without constructing anything. All problems are reported at once,
+// each as a *ResolutionError joined into the returned error. Dependency cycles are reported
+// with ErrCircularDependency. Pending lazy modules are loaded so their registrations are
+// checked too; types a resolver would instantiate are considered resolvable.
+//
+// Example:
+//
+//	func TestWiring(t *testing.T) {
+//	    c := app.NewContainer()
+//	    if err := c.Validate(); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+func (c *Container) Validate() error {
+	if err := c.loadModules(); err != nil {
+		return err
+	}
+
+	v := &validator{done: make(map[*entry]bool)}
+
+	var restrictions []*Container
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		entries := cur.entries
+		cur.mu.RUnlock()
+
+		for _, e := range entries {
+			if allowedBy(restrictions, e.depType) {
+				if err := e.drift(); err != nil {
+					v.fail([]string{e.label()}, err)
+				}
+				v.visit(e, nil)
+			}
+		}
+
+		if cur.allow != nil {
+			restrictions = append(restrictions, cur)
+		}
+	}
+
+	return errors.Join(v.errs...)
+}
+
+// validator walks the dependency graph of auto-wired factories
+type validator struct {
+	done        map[*entry]bool
+	reached     map[*entry]bool // Every entry visited, when collecting for RemoveUnreachable
+	from        *Container      // Resolves every dependency from this scope, see dependsOnOverride
+	instantiate bool            // Registers the types resolvers provide, see instantiateWired
+	errs        []error
+}
+
+// visit checks the parameters of e and, transitively, of the factories they resolve to
+func (v *validator) visit(e *entry, stack []*entry) {
+	for i, cur := range stack {
+		if cur == e {
+			v.errs = append(v.errs, newCycleError(append(stack[i:len(stack):len(stack)], e)))
+			return
+		}
+	}
+
+	if v.reached != nil {
+		v.reached[e] = true
+	}
+
+	// Every variant may serve a scope, see Variants
+	if e.variants != nil {
+		for _, variant := range e.variants.entries {
+			v.visit(variant, stack)
+		}
+		return
+	}
+
+	// Scoped factories resolve their dependencies from scopes that may not exist yet,
+	// though RemoveUnreachable must keep what they depend on
+	if v.done[e] || e.wiring == nil || e.lifecycle == Scoped && (v.reached == nil || v.from != nil) {
+		return
+	}
+
+	stack = append(stack, e)
+	w := e.wiring
+	c := w.c
+	if v.from != nil {
+		c = v.from
+	}
+
+	for i, paramType := range w.params {
+		path := []string{e.label(), paramStep(i, paramType)}
+
+		if isGroup(paramType) || isKeyedGroup(paramType) {
+			v.visitGroup(c, paramType, path, stack)
+			continue
+		}
+
+		searchType := paramType
+		if searchType.Kind() == reflect.Ptr {
+			searchType = searchType.Elem()
+		}
+		if isUnresolvable(searchType.Kind()) {
+			v.fail(path, fmt.Errorf("cannot auto-resolve primitive type %s", paramType))
+			continue
+		}
+
+		if v.visitType(c, paramType, path, stack) {
+			continue
+		}
+
+		if isInStruct(paramType) || len(w.params) == 1 && searchType.Kind() == reflect.Struct {
+			v.visitStruct(c, searchType, path, stack)
+			continue
+		}
+
+		v.fail(path, ErrNotFound)
+	}
+
+	v.done[e] = true
+}
+
+// visitType checks that targetType resolves in c, reporting whether it was found
+func (v *validator) visitType(c *Container, targetType reflect.Type, path []string, stack []*entry) bool {
+	if targetType == containerType || targetType == scopeInfoType || targetType == lifecycleHooksType ||
+		targetType == contextType {
+		return true
+	}
+
+	if elem, ok := optionalElem(targetType); ok {
+		v.visitType(c, elem, path, stack)
+		return true
+	}
+
+	cand, ok, err := c.findEntry(targetType, stack[len(stack)-1])
+	if err != nil {
+		v.fail(path, err)
+		return true
+	}
+	if ok {
+		v.visit(cand.e, stack)
+		return true
+	}
+
+	if v.instantiate && c.instantiate(targetType) {
+		if cand, ok, _ := c.findEntry(targetType, stack[len(stack)-1]); ok {
+			v.visit(cand.e, stack)
+		}
+		return true
+	}
+
+	return c.resolverProvides(targetType)
+}
+
+// visitStruct checks the fields Inject would populate on an auto-injected struct
+func (v *validator) visitStruct(c *Container, structType reflect.Type, path []string, stack []*entry) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], fieldStep(field))
+
+		if isMarker(field.Type) {
+			continue
+		}
+
+		tag, err := parseInjectTag(field)
+		if tag.skip {
+			continue
+		}
+		if err != nil || tag.name != "" {
+			v.visitNamed(c, tag, field.Type, fieldPath, err, stack)
+			continue
+		}
+		if isKeyedGroup(field.Type) {
+			v.visitGroup(c, field.Type, fieldPath, stack)
+			continue
+		}
+
+		if v.visitType(c, field.Type, fieldPath, stack) {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			v.visitStruct(c, field.Type, fieldPath, stack)
+			continue
+		}
+
+		if !tag.optional {
+			v.fail(fieldPath, ErrNotFound)
+		}
+	}
+}
+
+// visitGroup checks a []T or map[string]T collected from every registration of T
+func (v *validator) visitGroup(c *Container, groupType reflect.Type, path []string, stack []*entry) {
+	if v.visitType(c, groupType, path, stack) {
+		return
+	}
+	for _, cand := range c.candidates(groupType.Elem(), stack[len(stack)-1]) {
+		v.visit(cand.e, stack)
+	}
+}
+
+// visitNamed checks a field resolved by token name through a dshot tag
+func (v *validator) visitNamed(c *Container, tag injectTag, fieldType reflect.Type, path []string, tagErr error, stack []*entry) {
+	if tagErr != nil {
+		v.fail(path, tagErr)
+		return
+	}
+
+	targetType := fieldType
+	elem, isOptional := optionalElem(fieldType)
+	if isOptional {
+		targetType = elem
+	}
+
+	e, err := c.findNamed(tag.name, targetType)
+	switch {
+	case err != nil:
+		v.fail(path, err)
+	case e != nil:
+		v.visit(e, stack)
+	case !isOptional && !tag.optional:
+		v.fail(path, fmt.Errorf("%w: token %q", ErrNotFound, tag.name))
+	}
+}
+
+func (v *validator) fail(path []string, err error) {
+	v.errs = append(v.errs, &ResolutionError{Path: path, Err: err})
+}
+
+// resolverProvides reports whether a resolver of the container or the parents it can
+// resolve from would instantiate targetType, without registering anything
+func (c *Container) resolverProvides(targetType reflect.Type) bool {
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		resolvers := cur.resolvers
+		cur.mu.RUnlock()
+
+		for _, r := range resolvers {
+			if _, ok := r(targetType); ok {
+				return true
+			}
+		}
+
+		if !cur.allowsFromParent(targetType) {
+			return false
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package dshot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestValidate_OK(t *testing.T) {
+	c := dshot.New()
+	constructed := false
+
+	c.Provide(&Database{ConnectionString: "localhost"})
+	dshot.ProvideAutoFactory(func(db *Database) *Repository {
+		constructed = true
+		return &Repository{DB: db}
+	}, c)
+	dshot.ProvideAutoFactory(func(deps struct {
+		Repo *Repository
+		C    *dshot.Container
+	}) *Service {
+		return &Service{}
+	}, c)
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if constructed {
+		t.Error("Validate should not construct anything")
+	}
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	c := dshot.New()
+
+	dshot.ProvideAutoFactory(func(db *Database) *Repository {
+		return &Repository{DB: db}
+	}, c)
+	dshot.ProvideAutoFactory(func(deps struct{ Svc *ComplexService }) *Service {
+		return &Service{}
+	}, c)
+	dshot.ProvideAutoFactory(func(b *cycleB) *cycleA { return &cycleA{B: b} }, c)
+	dshot.ProvideAutoFactory(func(a *cycleA) *cycleB { return &cycleB{A: a} }, c)
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	if !errors.Is(err, dshot.ErrNotFound) || !errors.Is(err, dshot.ErrCircularDependency) {
+		t.Errorf("Expected missing and cycle errors, got %v", err)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Expected joined errors, got %T", err)
+	}
+	if n := len(joined.Unwrap()); n != 3 {
+		t.Errorf("Expected 3 problems, got %d: %v", n, err)
+	}
+}
+
+func TestValidate_CanaryVariants(t *testing.T) {
+	c := dshot.New()
+	dbToken := dshot.NewToken[*Database]("db")
+	c.Register(dshot.Canary(10,
+		dshot.BindAutoFactory(dbToken, func() *Database { return &Database{} }, c),
+		dshot.BindAutoFactory(dbToken, func(svc *Service) *Database { return &Database{} }, c),
+	))
+
+	if err := c.Validate(); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected the canary's missing dependency reported, got %v", err)
+	}
+}
+
+func TestRemoveUnreachable_KeepsReachableRegistrations(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{ConnectionString: "db"})
+	dshot.ProvideAutoFactory(func(db *Database) *Repository { return &Repository{DB: db} }, c)
+	dshot.ProvideAutoFactory(func(db *Database) *ComplexService { return &ComplexService{} }, c)
+	cacheToken := dshot.NewToken[*Service]("cache")
+	c.Register(dshot.Bind(cacheToken, &Service{Name: "cache"}))
+	c.Register(dshot.Bind(dshot.NewToken[*Service]("unused"), &Service{Name: "unused"}))
+
+	if removed := c.RemoveUnreachable(func(repo *Repository) {}, cacheToken); removed != 2 {
+		t.Errorf("Expected 2 registrations removed, got %d", removed)
+	}
+	if repo := dshot.MustResolve[*Repository](c); repo.DB.ConnectionString != "db" {
+		t.Errorf("Expected the reachable graph intact, got '%s'", repo.DB.ConnectionString)
+	}
+	if _, err := dshot.ResolveE[*ComplexService](c); !errors.Is(err, dshot.ErrNotFound) {
+		t.Errorf("Expected the unreachable factory removed, got %v", err)
+	}
+	if svc := dshot.Get(cacheToken, c); svc.Name != "cache" {
+		t.Errorf("Expected the token entry point kept, got '%s'", svc.Name)
+	}
+}
@@ -0,0 +1,86 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// valueName is the token of a value registered with ProvideNamed
+type valueName string
+
+func (n valueName) String() string {
+	return string(n)
+}
+
+// ProvideNamed registers a value under name, typically a primitive that auto-wiring never
+// resolves by type, such as a DSN, port or timeout. Struct fields request it with a
+// `dshot:"value=db.dsn"` tag (a synonym of name=), in Inject targets and in the dependency
+// structs of auto-wired factories; other code reads it with Value.
+//
+// Example:
+//
+//	c.ProvideNamed("db.dsn", "postgres://localhost/app")
+//	c.ProvideNamed("http.port", 8080)
+//
+//	type DBParams struct {
+//	    container.In
+//
+//	    DSN     string        `dshot:"value=db.dsn"`
+//	    Timeout time.Duration `dshot:"value=db.timeout,optional"`
+//	}
+//	container.ProvideAutoFactory(func(p DBParams) (*sql.DB, error) {
+//	    return sql.Open("postgres", p.DSN)
+//	})
+func (c *Container) ProvideNamed(name string, value any) {
+	if name == "" {
+		panic("ProvideNamed: name cannot be empty")
+	}
+	typ := reflect.TypeOf(value)
+	if typ == nil {
+		panic("ProvideNamed: cannot register nil value")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.addEntry(valueName(name), &entry{value: value, depType: typ})
+}
+
+// Value returns the value registered under name with ProvideNamed, or any token registration
+// of that name, from the specified container (or global if nil). It panics if the name is
+// not registered or its value is not a T.
+//
+// Example:
+//
+//	port := container.Value[int]("http.port", c)
+func Value[T any](name string, containers ...*Container) T {
+	val, err := ValueE[T](name, containers...)
+	if err != nil {
+		panic(fmt.Sprintf("Value: %v", err))
+	}
+	return val
+}
+
+// ValueE is Value returning an error instead of panicking. The error wraps ErrNotFound if
+// the name is not registered.
+//
+// Example:
+//
+//	dsn, err := container.ValueE[string]("db.dsn", c)
+func ValueE[T any](name string, containers ...*Container) (T, error) {
+	var zero T
+
+	c := containerOrGlobal(containers)
+	checkGlobalUse(c, "Value")
+
+	e, err := c.lookupNamed(name, reflect.TypeFor[T]())
+	if err != nil {
+		return zero, err
+	}
+	val, err := c.resolveEntry(e, nil)
+	if err != nil {
+		return zero, withStep(err, name)
+	}
+
+	return typedEntry[T](val, e)
+}
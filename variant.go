@@ -0,0 +1,211 @@
+package dshot
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// VariantSelector chooses the variant of a registration serving a scope, e.g. from the user
+// or tenant stored in the scope with SetValue. It runs once per scope and registration.
+// Returning a name that is not among variants selects the first one.
+type VariantSelector interface {
+	SelectVariant(scope *Container, variants []string) string
+}
+
+// VariantSelectorFunc adapts a function to the VariantSelector interface
+type VariantSelectorFunc func(scope *Container, variants []string) string
+
+// SelectVariant calls f
+func (f VariantSelectorFunc) SelectVariant(scope *Container, variants []string) string {
+	return f(scope, variants)
+}
+
+// Variant is a named alternative implementation, see Variants
+type Variant[T any] struct {
+	Name         string
+	Registration Registration[T]
+}
+
+// Variants registers alternative implementations of a token and lets selector choose the
+// one serving each scope, making the container the enforcement point of experiments on
+// implementations. The choice is made once per scope, so every resolution within a request
+// sees the same implementation, and is reported by ServedVariant and VariantUsage. Each
+// variant keeps its own lifecycle. All registrations must use the same token; the first
+// variant is the default.
+//
+// Example:
+//
+//	c.Register(container.Variants(container.VariantSelectorFunc(
+//	    func(scope *container.Container, variants []string) string {
+//	        user, _ := scope.GetValue(userIDKey{})
+//	        return experiments.Assign("ranking-v2", user, variants)
+//	    }),
+//	    container.Variant[Ranker]{Name: "control", Registration: container.BindAutoFactory(rankerToken, NewRanker)},
+//	    container.Variant[Ranker]{Name: "v2", Registration: container.BindAutoFactory(rankerToken, NewRankerV2)},
+//	))
+func Variants[T any](selector VariantSelector, variants ...Variant[T]) Registration[T] {
+	if selector == nil {
+		panic("Variants: selector cannot be nil")
+	}
+	if len(variants) == 0 {
+		panic("Variants: at least one variant is required")
+	}
+
+	spec := &variantSpec[T]{}
+	for _, v := range variants {
+		if v.Registration.token != variants[0].Registration.token {
+			panic(fmt.Sprintf("Variants: variant %q uses a different token", v.Name))
+		}
+		spec.names = append(spec.names, v.Name)
+		spec.regs = append(spec.regs, v.Registration)
+	}
+	spec.pick = func(scope *Container) int {
+		name := selector.SelectVariant(scope, spec.names)
+		for i, n := range spec.names {
+			if n == name {
+				return i
+			}
+		}
+		return 0
+	}
+
+	return Registration[T]{
+		token:     variants[0].Registration.token,
+		lifecycle: variants[0].Registration.lifecycle,
+		variants:  spec,
+	}
+}
+
+// VariantCount is the number of scopes a variant served, see VariantUsage
+type VariantCount struct {
+	Variant string
+	Scopes  int64
+}
+
+// variantSpec holds the variants of a registration until it is registered
+type variantSpec[T any] struct {
+	names []string
+	regs  []Registration[T]
+	pick  func(scope *Container) int // Index of the variant serving scope
+}
+
+// newSet returns the variants as entries, not yet owned by a container
+func (s *variantSpec[T]) newSet() *variantSet {
+	set := &variantSet{
+		names:  s.names,
+		pick:   s.pick,
+		served: make([]atomic.Int64, len(s.regs)),
+	}
+	for _, reg := range s.regs {
+		set.entries = append(set.entries, reg.newEntry())
+	}
+	return set
+}
+
+// variantSet holds the alternative implementations of an entry
+type variantSet struct {
+	names   []string
+	entries []*entry
+	pick    func(scope *Container) int
+	served  []atomic.Int64 // Number of scopes served per variant
+}
+
+// adoptVariants registers the variants of e with c, alongside e. Callers must hold c.mu.
+func (c *Container) adoptVariants(e *entry) {
+	for _, v := range e.variants.entries {
+		v.token = e.token
+		v.owner = c
+		v.module = e.module
+		v.registeredAt = e.registeredAt
+		v.sitePCs = e.sitePCs
+		if c.nilGuard {
+			c.guardRegistration(v)
+		}
+	}
+}
+
+// resolveVariant resolves the variant of e serving the scope of c
+func (c *Container) resolveVariant(e *entry, r *resolution) (any, error) {
+	scope := c
+	for scope.view {
+		scope = scope.parent
+	}
+
+	return c.resolveEntry(e.variants.entries[scope.variantOf(e)], r)
+}
+
+// variantOf returns the index of the variant of e serving this scope, picking it on first use
+func (c *Container) variantOf(e *entry) int {
+	c.mu.RLock()
+	i, ok := c.variantPicks[e]
+	c.mu.RUnlock()
+	if ok {
+		return i
+	}
+
+	// Picked outside the lock, so selectors can read the scope
+	i = e.variants.pick(c)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if prev, ok := c.variantPicks[e]; ok {
+		return prev
+	}
+	if c.variantPicks == nil {
+		c.variantPicks = make(map[*entry]int)
+	}
+	c.variantPicks[e] = i
+	e.variants.served[i].Add(1)
+
+	return i
+}
+
+// ServedVariant returns the name of the variant of token that served this scope, e.g.
+// "canary", or false if the token has no variants or was not resolved from the scope yet.
+//
+// Example:
+//
+//	if v, ok := reqScope.ServedVariant(pricingToken); ok {
+//	    w.Header().Set("X-Pricing-Variant", v)
+//	}
+func (c *Container) ServedVariant(token any) (string, bool) {
+	e, ok := c.lookupEntry(token)
+	if !ok || e.variants == nil {
+		return "", false
+	}
+
+	scope := c
+	for scope.view {
+		scope = scope.parent
+	}
+
+	scope.mu.RLock()
+	i, ok := scope.variantPicks[e]
+	scope.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	return e.variants.names[i], true
+}
+
+// VariantUsage reports how many scopes each variant of token served, in declaration order,
+// or nil if the token has no variants.
+//
+// Example:
+//
+//	for _, u := range c.VariantUsage(pricingToken) {
+//	    metrics.Gauge("pricing_variant_scopes", u.Scopes, "variant", u.Variant)
+//	}
+func (c *Container) VariantUsage(token any) []VariantCount {
+	e, ok := c.lookupEntry(token)
+	if !ok || e.variants == nil {
+		return nil
+	}
+
+	usage := make([]VariantCount, len(e.variants.names))
+	for i, name := range e.variants.names {
+		usage[i] = VariantCount{Variant: name, Scopes: e.variants.served[i].Load()}
+	}
+	return usage
+}
@@ -0,0 +1,98 @@
+package dshot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ReadOnly returns a view of the container that can resolve everything c can except its
+// *LifecycleHooks, and panics on Provide, Register and Clear. Hand it to plugins and
+// request code that must not mutate application wiring.
+// Scopes created from the view are writable and only affect themselves.
+//
+// Example:
+//
+//	plugin.Init(app.ReadOnly())
+func (c *Container) ReadOnly() *Container {
+	if c.readOnly {
+		return c
+	}
+
+	view := newView(c)
+	view.readOnly = true
+	return view
+}
+
+// newView creates a registration-less child that resolves through c
+// without counting as a scope
+func newView(c *Container) *Container {
+	view := &Container{
+		registry:     make(map[any]*entry),
+		typeRegistry: make(map[reflect.Type][]*entry),
+		parent:       c,
+		name:         c.name,
+		depth:        c.depth,
+		createdAt:    c.createdAt,
+		view:         true,
+	}
+	view.inherit(c)
+	return view
+}
+
+// checkWritable panics if the container is a read-only view or frozen
+func (c *Container) checkWritable(op string) {
+	if c.readOnly {
+		panic(fmt.Sprintf("%s: container is read-only", op))
+	}
+	if c.frozen.Load() {
+		panic(fmt.Sprintf("%s: container is frozen", op))
+	}
+}
+
+// NewRestricted creates a child container that can only resolve the listed types from parent.
+// Registrations made directly in the restricted container remain resolvable. Use it to give
+// sandboxed code (plugins, tenant scripts) access to an approved subset of the application graph.
+// The parent's *LifecycleHooks are not resolvable from it unless listed.
+//
+// Example:
+//
+//	sandbox := container.NewRestricted(app,
+//	    reflect.TypeFor[*Logger](),
+//	    reflect.TypeFor[KVStore](),
+//	)
+//	plugin.Run(sandbox)
+func NewRestricted(parent *Container, allow ...reflect.Type) *Container {
+	if parent == nil {
+		panic("NewRestricted: parent container cannot be nil")
+	}
+
+	restricted := newView(parent)
+	restricted.allow = make(map[reflect.Type]bool, len(allow))
+	for _, typ := range allow {
+		restricted.allow[typ] = true
+	}
+
+	return restricted
+}
+
+// sharesHooks reports whether resolving *LifecycleHooks from c may return the hooks it
+// uses: a view uses its parent's, which a read-only view must not extend and a restricted
+// one only exposes when they are allowed
+func (c *Container) sharesHooks() bool {
+	return !c.view || !c.readOnly && c.allow[lifecycleHooksType]
+}
+
+// allowsFromParent reports whether typ may be resolved from the parent container
+func (c *Container) allowsFromParent(typ reflect.Type) bool {
+	return c.allow == nil || c.allow[typ]
+}
+
+// allowedBy reports whether typ passes every restriction
+func allowedBy(restrictions []*Container, typ reflect.Type) bool {
+	for _, r := range restrictions {
+		if !r.allowsFromParent(typ) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+package dshot_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/overdevelop/dshot"
+)
+
+func TestReadOnly_ResolvesButRejectsMutation(t *testing.T) {
+	c := dshot.New()
+	token := dshot.NewToken[*Database]("db")
+	c.Provide(&Service{Name: "app"})
+	c.Register(dshot.Bind(token, &Database{ConnectionString: "localhost"}))
+
+	view := c.ReadOnly()
+
+	if svc := dshot.MustResolve[*Service](view); svc.Name != "app" {
+		t.Errorf("Expected 'app', got '%s'", svc.Name)
+	}
+	if db := dshot.Get(token, view); db.ConnectionString != "localhost" {
+		t.Errorf("Expected 'localhost', got '%s'", db.ConnectionString)
+	}
+
+	mutations := map[string]func(){
+		"Provide":  func() { view.Provide(&Database{}) },
+		"Register": func() { view.Register(dshot.Bind(token, &Database{})) },
+		"Clear":    func() { view.Clear() },
+		"Parent":   func() { dshot.NewScoped(view).Parent().Provide(&Database{}) },
+	}
+	for name, mutate := range mutations {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: expected panic on read-only view", name)
+				}
+			}()
+			mutate()
+		}()
+	}
+}
+
+func TestReadOnly_ScopesAreWritable(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Service{Name: "app"})
+
+	scope := dshot.NewScoped(c.ReadOnly(), "plugin")
+	scope.Provide(&Database{ConnectionString: "plugin-db"})
+
+	if _, ok := dshot.Resolve[*Database](c); ok {
+		t.Error("Scope registrations should not leak into the underlying container")
+	}
+	if _, ok := dshot.Resolve[*Service](scope); !ok {
+		t.Error("Scope should resolve through the read-only view")
+	}
+	if info := scope.ScopeInfo(); info.Depth != 1 || len(info.Parents) != 1 {
+		t.Errorf("View should be transparent in scope info: %+v", info)
+	}
+}
+
+func TestNewRestricted_OnlyResolvesAllowedTypes(t *testing.T) {
+	c := dshot.New()
+	secretToken := dshot.NewToken[*Database]("secrets")
+	c.Provide(&Service{Name: "public"})
+	c.Register(dshot.Bind(secretToken, &Database{ConnectionString: "secret"}))
+
+	sandbox := dshot.NewRestricted(c, reflect.TypeFor[*Service]())
+
+	if _, ok := dshot.Resolve[*Service](sandbox); !ok {
+		t.Error("Allowed type should resolve")
+	}
+	if _, ok := dshot.Resolve[*Database](sandbox); ok {
+		t.Error("Disallowed type should not resolve by type")
+	}
+	if _, ok := dshot.Find(secretToken, sandbox); ok {
+		t.Error("Disallowed type should not resolve by token")
+	}
+
+	count := 0
+	for range sandbox.All() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 visible registration, got %d", count)
+	}
+
+	sandbox.Provide(&Database{ConnectionString: "local"})
+	if db, ok := dshot.Resolve[*Database](sandbox); !ok || db.ConnectionString != "local" {
+		t.Error("Local registrations should resolve")
+	}
+
+	if sandbox.Parent() != nil || sandbox.ReadOnly().Parent() != nil {
+		t.Error("Restricted containers should not expose their parent")
+	}
+	if dshot.NewScoped(sandbox).Parent() != sandbox {
+		t.Error("Scopes of a restricted container should return it as their parent")
+	}
+}
+
+func TestViews_DoNotExposeParentHooks(t *testing.T) {
+	base := context.WithValue(context.Background(), traceKey{}, "base")
+	c := dshot.New(dshot.WithBaseContext(base))
+	hooks := reflect.TypeFor[*dshot.LifecycleHooks]()
+
+	sandbox := dshot.NewRestricted(c, reflect.TypeFor[*Service]())
+	if _, ok := dshot.Resolve[*dshot.LifecycleHooks](sandbox); ok {
+		t.Error("Restricted containers should not expose the parent's hooks")
+	}
+	if _, ok := dshot.Resolve[*dshot.LifecycleHooks](c.ReadOnly()); ok {
+		t.Error("Read-only views should not expose the parent's hooks")
+	}
+	if resolved := dshot.MustResolve[*dshot.Container](sandbox); resolved != sandbox {
+		t.Error("Expected *Container to resolve to the restricted container itself")
+	}
+
+	allowed := dshot.NewRestricted(c, hooks)
+	if lc := dshot.MustResolve[*dshot.LifecycleHooks](allowed); lc != dshot.MustResolve[*dshot.LifecycleHooks](c) {
+		t.Error("Expected the parent's hooks when allowed")
+	}
+
+	var trace any
+	dshot.ProvideAutoPrototype(func(ctx context.Context) *Database {
+		trace = ctx.Value(traceKey{})
+		return &Database{}
+	}, sandbox)
+	dshot.MustResolve[*Database](sandbox)
+	if trace != "base" {
+		t.Errorf("Expected views to inherit the base context, got %v", trace)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	c := dshot.New()
+	c.Provide(&Database{})
+	c.RegisterLazyModule("repos", func() dshot.Module {
+		return func(c *dshot.Container) {
+			dshot.ProvideAutoFactory(func(db *Database) *Repository { return &Repository{DB: db} }, c)
+		}
+	}, reflect.TypeFor[*Repository]())
+
+	c.Freeze()
+	c.Freeze()
+	if !c.Frozen() {
+		t.Fatal("Expected the container to be frozen")
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if repo := dshot.MustResolve[*Repository](c); repo.DB == nil {
+				t.Error("Expected the lazy module loaded before freezing")
+			}
+		})
+	}
+	wg.Wait()
+
+	for name, register := range map[string]func(){
+		"Provide":  func() { c.Provide(&Service{}) },
+		"Register": func() { c.Register(dshot.Bind(dshot.NewToken[*Service]("svc"), &Service{})) },
+		"Clear":    func() { c.Clear() },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "frozen") {
+					t.Errorf("Expected %s to panic on a frozen container, got %v", name, r)
+				}
+			}()
+			register()
+		}()
+	}
+
+	scope := dshot.NewScoped(c)
+	scope.Provide(&Service{Name: "scoped"})
+	if svc := dshot.MustResolve[*Service](scope); svc.Name != "scoped" {
+		t.Errorf("Expected scopes of a frozen container to stay writable")
+	}
+}
+
+func TestFreeze_ConcurrentWithRegistration(t *testing.T) {
+	c := dshot.New()
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		defer func() { recover() }()
+		for i := 0; ; i++ {
+			c.Register(dshot.Bind(dshot.NewToken[*Service](fmt.Sprint("svc-", i)), &Service{}))
+		}
+	})
+	c.Freeze()
+	wg.Wait()
+
+	for range 100 {
+		dshot.ResolveAll[*Service](c)
+	}
+}
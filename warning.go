@@ -0,0 +1,78 @@
+package dshot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WarningKind identifies a diagnostic warning, see Warning
+type WarningKind int
+
+const (
+	WarnSimilarType    WarningKind = iota // A pointer/value counterpart served a type lookup
+	WarnTypeMismatch                      // A similar-type conversion failed
+	WarnTypeDrift                         // A token value was converted to the token type, see DriftConvert
+	WarnSnapshot                          // A snapshot could not be restored, see WithSnapshots
+	WarnUnownedCleanup                    // A prototype cleanup was dropped, see ProvideAutoPrototype
+)
+
+// Warning is a diagnostic reported by a container, such as a similar-type fallback
+type Warning struct {
+	Kind    WarningKind
+	Message string
+	Attrs   []slog.Attr // Structured details, e.g. the target type
+}
+
+// WithWarningHandler sends the container's diagnostic warnings to fn instead of the logger,
+// see SetWarningHandler.
+//
+// Example:
+//
+//	c := container.New(container.WithWarningHandler(func(w container.Warning) {
+//	    metrics.Inc("dshot_warnings", "kind", w.Kind)
+//	}))
+func WithWarningHandler(fn func(Warning)) Option {
+	return func(c *Container) {
+		c.SetWarningHandler(fn)
+	}
+}
+
+// SetWarningHandler sends the diagnostic warnings of the container and its scopes to fn
+// instead of the logger, e.g. to rate-limit them in high-QPS services or to assert on them
+// in tests. A no-op fn silences them; nil restores logging. Scopes use the handler of their
+// nearest container that has one, including scopes created before the call.
+// WithSilentDiagnostics still takes precedence.
+//
+// Example:
+//
+//	var warnings []container.Warning
+//	c.SetWarningHandler(func(w container.Warning) {
+//	    warnings = append(warnings, w)
+//	})
+func (c *Container) SetWarningHandler(fn func(Warning)) {
+	if fn == nil {
+		c.warnings.Store(nil)
+		return
+	}
+	c.warnings.Store(&fn)
+}
+
+// warningHandler returns the handler of the nearest container that has one, or nil
+func (c *Container) warningHandler() func(Warning) {
+	for cur := c; cur != nil; cur = cur.parent {
+		if fn := cur.warnings.Load(); fn != nil {
+			return *fn
+		}
+	}
+	return nil
+}
+
+// warn reports a diagnostic warning. Callers check warnEnabled first, so that
+// warnings are not formatted when nobody receives them.
+func (c *Container) warn(w Warning) {
+	if fn := c.warningHandler(); fn != nil {
+		fn(w)
+		return
+	}
+	c.log().LogAttrs(context.Background(), slog.LevelWarn, w.Message, w.Attrs...)
+}